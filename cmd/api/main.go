@@ -14,7 +14,10 @@ func main() {
 		log.Fatal(err)
 	}
 
-	srv := server.NewServer(ctx)
+	srv, err := server.NewServer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
 	if err := srv.Run(ctx); err != nil {
 		log.Fatal(err)
 	}
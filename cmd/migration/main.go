@@ -12,6 +12,9 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/mysql"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/sqlite"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
@@ -25,25 +28,25 @@ func main() {
 	flag.StringVar(&name, "name", "", "name for new migration")
 	flag.Parse()
 
-	migrations := &migrate.FileMigrationSource{
-		Dir: "db/migrations", // パスを修正
-	}
-
-	db, err := setupDB()
+	db, dialect, dir, err := setupDB()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	migrations := &migrate.FileMigrationSource{
+		Dir: dir,
+	}
+
 	switch command {
 	case "up":
-		n, err := migrate.Exec(db, "mysql", migrations, migrate.Up)
+		n, err := migrate.Exec(db, dialect, migrations, migrate.Up)
 		if err != nil {
 			log.Fatal(err)
 		}
 		fmt.Printf("Applied %d migrations\n", n)
 
 	case "down":
-		n, err := migrate.Exec(db, "mysql", migrations, migrate.Down)
+		n, err := migrate.Exec(db, dialect, migrations, migrate.Down)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -54,7 +57,7 @@ func main() {
 			log.Fatal("Migration name is required")
 		}
 		filename := fmt.Sprintf("%s_%s.sql", time.Now().Format(_layout), name)
-		f, err := os.Create(fmt.Sprintf("db/migrations/%s", filename))
+		f, err := os.Create(filepath.Join(dir, filename))
 		if err != nil {
 			log.Fatal("failed to create file:", err)
 		}
@@ -65,7 +68,7 @@ func main() {
 		fmt.Printf("Created new migration: %s\n", filename)
 
 	case "status":
-		records, err := migrate.GetMigrationRecords(db, "mysql")
+		records, err := migrate.GetMigrationRecords(db, dialect)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -73,6 +76,10 @@ func main() {
 			fmt.Printf("Applied: %s\n", record.Id)
 		}
 	case "seed":
+		// シードデータはMySQLの構文で書かれている
+		if dialect != mysql.MySQL.Name() {
+			log.Fatal("Seed is only supported for MySQL")
+		}
 		if err := seedData(db); err != nil {
 			log.Fatal(err)
 		}
@@ -83,10 +90,20 @@ func main() {
 	}
 }
 
-func setupDB() (*sql.DB, error) {
+// setupDB は DATABASE_URL のスキームからデータベースを選び、
+// 接続と sql-migrate の方言名、マイグレーションファイルのディレクトリを返します
+// sqlite:// で始まる場合はSQLite (db/migrations/sqlite)、それ以外はMySQL (db/migrations) を使います
+func setupDB() (*sql.DB, string, string, error) {
 	dsn := os.Getenv("DATABASE_URL")
 
-	return sql.Open("mysql", dsn)
+	switch config.DBDriverFromDSN(dsn) {
+	case config.DBDriverSQLite:
+		db, err := sqlite.Open(dsn)
+		return db, sqlite.Dialect.Name(), "db/migrations/sqlite", err
+	default:
+		db, err := sql.Open("mysql", dsn)
+		return db, mysql.MySQL.Name(), "db/migrations", err
+	}
 }
 
 func seedData(db *sql.DB) error {
@@ -0,0 +1,15 @@
+// Package db はマイグレーションファイルをバイナリに埋め込みます
+// デプロイ用のイメージにはマイグレーションファイルを含めないため、/readyz で未適用のマイグレーションを確認するのに使います
+package db
+
+import "embed"
+
+// MySQLMigrations は db/migrations のMySQLのマイグレーションです
+//
+//go:embed migrations/*.sql
+var MySQLMigrations embed.FS
+
+// SQLiteMigrations は db/migrations/sqlite のSQLiteのマイグレーションです
+//
+//go:embed migrations/sqlite/*.sql
+var SQLiteMigrations embed.FS
@@ -0,0 +1,23 @@
+-- +migrate Up
+-- データの変更と同じトランザクションで保存するドメインイベントのテーブル
+-- 配信に成功したイベントは delivered、再試行の上限に達したイベントは dead (デッドレター) になる
+CREATE TABLE IF NOT EXISTS outbox (
+    seq BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '保存した順の番号',
+    id CHAR(36) NOT NULL UNIQUE COMMENT 'イベントID（UUID）',
+    event_type VARCHAR(100) NOT NULL COMMENT 'イベントの種類',
+    aggregate_id CHAR(36) NOT NULL COMMENT '変更したデータのID',
+    payload JSON NOT NULL COMMENT '変更後のデータ',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '配信状態 (pending / delivered / dead)',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '配信に失敗した回数',
+    last_error TEXT NULL COMMENT '最後の配信エラー',
+    claim_token CHAR(36) NULL COMMENT '配信処理が取得したときのトークン',
+    occurred_at DATETIME(6) NOT NULL COMMENT '発生日時 (UTC)',
+    next_attempt_at DATETIME(6) NOT NULL COMMENT '次に配信する日時 (UTC)',
+    delivered_at DATETIME(6) NULL COMMENT '配信日時 (UTC)',
+    INDEX idx_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_claim_token (claim_token),
+    INDEX idx_aggregate_id (aggregate_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='ドメインイベントのアウトボックス';
+
+-- +migrate Down
+DROP TABLE IF EXISTS outbox;
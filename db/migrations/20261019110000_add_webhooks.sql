@@ -0,0 +1,42 @@
+-- +migrate Up
+-- ドメインイベントを外部に通知するWebhookと配信履歴のテーブル
+CREATE TABLE IF NOT EXISTS webhooks (
+    id VARCHAR(36) PRIMARY KEY COMMENT 'WebhookID (UUID)',
+    user_id VARCHAR(36) NOT NULL COMMENT '登録したユーザーID',
+    url VARCHAR(2048) NOT NULL COMMENT '送信先URL',
+    secret VARCHAR(255) NOT NULL COMMENT '署名の秘密鍵',
+    event_types TEXT NOT NULL COMMENT '購読するイベントの種類 (カンマ区切り)',
+    active BOOLEAN NOT NULL DEFAULT TRUE COMMENT '有効かどうか',
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '作成日時',
+    updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '更新日時',
+    INDEX idx_user_id (user_id),
+    CONSTRAINT fk_webhooks_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhook';
+
+-- 日時はアウトボックスと同じくアプリケーションがUTCで保存して比較する
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    seq BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY COMMENT '保存した順の番号',
+    id CHAR(36) NOT NULL UNIQUE COMMENT '配信ID（UUID）',
+    webhook_id VARCHAR(36) NOT NULL COMMENT 'WebhookID',
+    event_id CHAR(36) NOT NULL COMMENT 'イベントID',
+    event_type VARCHAR(100) NOT NULL COMMENT 'イベントの種類',
+    payload JSON NOT NULL COMMENT '送信するボディ',
+    dedup_key CHAR(36) NULL COMMENT '重複配信を防ぐキー (再送の場合はNULL)',
+    redelivery_of CHAR(36) NULL COMMENT '再送元の配信ID',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '配信状態 (pending / succeeded / dead)',
+    attempts INT NOT NULL DEFAULT 0 COMMENT '送信した回数',
+    response_status INT NOT NULL DEFAULT 0 COMMENT '最後のレスポンスのステータスコード',
+    last_error TEXT NULL COMMENT '最後の送信エラー',
+    claim_token CHAR(36) NULL COMMENT '送信処理が取得したときのトークン',
+    next_attempt_at DATETIME(6) NOT NULL COMMENT '次に送信する日時 (UTC)',
+    created_at DATETIME(6) NOT NULL COMMENT '作成日時 (UTC)',
+    delivered_at DATETIME(6) NULL COMMENT '送信に成功した日時 (UTC)',
+    UNIQUE KEY uk_webhook_dedup_key (webhook_id, dedup_key),
+    INDEX idx_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_claim_token (claim_token),
+    CONSTRAINT fk_webhook_deliveries_webhook_id FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Webhookの配信履歴';
+
+-- +migrate Down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
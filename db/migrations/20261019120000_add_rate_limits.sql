@@ -0,0 +1,11 @@
+-- +migrate Up
+-- 複数のインスタンスで共有するレート制限のバケット
+-- tat はトークンが満杯まで回復する時刻 (UNIX時間のマイクロ秒) で、過ぎた行は削除してよい
+CREATE TABLE IF NOT EXISTS rate_limits (
+    bucket_key VARCHAR(255) NOT NULL PRIMARY KEY COMMENT 'ルートのグループとクライアントのキー',
+    tat BIGINT NOT NULL COMMENT 'トークンが満杯まで回復する時刻 (UNIX時間のマイクロ秒)',
+    INDEX idx_tat (tat)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='レート制限のバケット';
+
+-- +migrate Down
+DROP TABLE IF EXISTS rate_limits;
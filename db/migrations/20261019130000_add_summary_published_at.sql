@@ -0,0 +1,8 @@
+-- +migrate Up
+-- 説明を確定して公開した日時。未公開のサマリーは NULL
+ALTER TABLE summaries
+    ADD COLUMN published_at TIMESTAMP(6) NULL DEFAULT NULL COMMENT '公開日時' AFTER user_id;
+
+-- +migrate Down
+ALTER TABLE summaries
+    DROP COLUMN published_at;
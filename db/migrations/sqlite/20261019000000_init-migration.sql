@@ -0,0 +1,63 @@
+-- +migrate Up
+-- db/migrations のMySQLのスキーマをまとめたもの
+-- SQLiteは ALTER TABLE で外部キー制約を追加できないため、最終的なスキーマで作成する
+-- 大文字小文字を区別しない照合順序 (utf8mb4_unicode_ci) に合わせて COLLATE NOCASE を指定する
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE,
+    email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    user_type TEXT NOT NULL COLLATE NOCASE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    deleted_at TIMESTAMP NULL DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_users_user_type ON users (user_type);
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS subcategories (
+    id TEXT PRIMARY KEY,
+    category_id TEXT NOT NULL,
+    name TEXT NOT NULL COLLATE NOCASE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE (category_id, name),
+    CONSTRAINT fk_subcategory_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_subcategories_category_id ON subcategories (category_id);
+
+CREATE TABLE IF NOT EXISTS summaries (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT,
+    content TEXT NOT NULL,
+    category_id TEXT NULL,
+    subcategory_id TEXT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    CONSTRAINT fk_summaries_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT fk_summary_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    CONSTRAINT fk_summary_subcategory FOREIGN KEY (subcategory_id) REFERENCES subcategories(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_summaries_title ON summaries (title);
+CREATE INDEX IF NOT EXISTS idx_summaries_user_id ON summaries (user_id);
+CREATE INDEX IF NOT EXISTS idx_summaries_category_id ON summaries (category_id);
+CREATE INDEX IF NOT EXISTS idx_summaries_subcategory_id ON summaries (subcategory_id);
+CREATE INDEX IF NOT EXISTS idx_summaries_created_at ON summaries (created_at);
+CREATE INDEX IF NOT EXISTS idx_summaries_deleted_at ON summaries (deleted_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS summaries;
+DROP TABLE IF EXISTS subcategories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
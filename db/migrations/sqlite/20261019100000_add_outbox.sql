@@ -0,0 +1,23 @@
+-- +migrate Up
+-- db/migrations/20261019100000_add_outbox.sql と同じテーブル
+-- 日時はアプリケーションがUTCの文字列で保存して比較する
+CREATE TABLE IF NOT EXISTS outbox (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    claim_token TEXT NULL,
+    occurred_at DATETIME NOT NULL,
+    next_attempt_at DATETIME NOT NULL,
+    delivered_at DATETIME NULL
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_status_next_attempt_at ON outbox (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_claim_token ON outbox (claim_token);
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate_id ON outbox (aggregate_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS outbox;
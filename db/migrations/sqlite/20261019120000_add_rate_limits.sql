@@ -0,0 +1,10 @@
+-- +migrate Up
+-- db/migrations/20261019120000_add_rate_limits.sql と同じテーブル
+CREATE TABLE IF NOT EXISTS rate_limits (
+    bucket_key TEXT NOT NULL PRIMARY KEY,
+    tat INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limits_tat ON rate_limits (tat);
+
+-- +migrate Down
+DROP TABLE IF EXISTS rate_limits;
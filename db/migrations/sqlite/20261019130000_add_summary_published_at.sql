@@ -0,0 +1,6 @@
+-- +migrate Up
+-- db/migrations/20261019130000_add_summary_published_at.sql と同じカラム
+ALTER TABLE summaries ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE summaries DROP COLUMN published_at;
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Type はドメインイベントの種類です
+type Type string
+
+const (
+	TypeSummaryCreated     Type = "summary.created"
+	TypeSummaryUpdated     Type = "summary.updated"
+	TypeSummaryDeleted     Type = "summary.deleted"
+	TypeSummaryPublished   Type = "summary.published"
+	TypeCategoryCreated    Type = "category.created"
+	TypeCategoryUpdated    Type = "category.updated"
+	TypeCategoryDeleted    Type = "category.deleted"
+	TypeSubcategoryCreated Type = "subcategory.created"
+	TypeSubcategoryUpdated Type = "subcategory.updated"
+	TypeSubcategoryDeleted Type = "subcategory.deleted"
+)
+
+// 配信状態
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	// StatusDead は再試行の上限に達して配信を諦めたイベントです (デッドレター)
+	StatusDead = "dead"
+)
+
+// Event はデータの変更を表すドメインイベントです
+// Payload には変更後のモデルをJSONで保存します。削除の場合は削除前のモデルを保存するため、
+// 購読者は削除されたデータの category_id なども参照できます
+type Event struct {
+	ID          string          `json:"id"`
+	Type        Type            `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// Record はアウトボックスに保存されたイベントと配信状態です
+// Seq は保存した順に増える番号で、イベントはこの順に配信します
+type Record struct {
+	Event
+	Seq           int64
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+}
+
+// IOutboxRepository はイベントをデータの変更と同じトランザクションで保存し、配信するまで保持します
+type IOutboxRepository interface {
+	// Append はイベントを保存します
+	// トランザクション中に呼び出すと、データの変更と同時にコミット・ロールバックされます
+	Append(ctx context.Context, events ...*Event) error
+	// Claim は配信予定時刻を過ぎた未配信のイベントを保存した順に最大 limit 件取得します
+	// 取得したイベントは lease の間、他の配信処理から取得されません
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Record, error)
+	// MarkDelivered はイベントを配信済みにします
+	MarkDelivered(ctx context.Context, id string) error
+	// MarkFailed は配信の失敗を記録します
+	// record の Status・Attempts・LastError・NextAttemptAt を保存します
+	MarkFailed(ctx context.Context, record *Record) error
+	// ListAfter は seq より後に保存されたイベントを、配信状態に関わらず保存した順に最大 limit 件取得します
+	// Claim と異なり取得したイベントをリースしないため、すべてのインスタンスが同じイベントを読み取れます
+	ListAfter(ctx context.Context, seq int64, limit int) ([]*Record, error)
+	// LastSeq は最後に保存したイベントの Seq を返します。イベントがない場合は0を返します
+	LastSeq(ctx context.Context) (int64, error)
+}
+
+// New はモデルをJSONに変換してイベントを生成します
+func New(typ Type, aggregateID string, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		Type:        typ,
+		AggregateID: aggregateID,
+		Payload:     data,
+	}, nil
+}
@@ -11,6 +11,10 @@ type ISubcategoryRepository interface {
 	Save(ctx context.Context, model *Subcategory) error
 	List(ctx context.Context, categoryID string) (SubcategorySlice, error)
 	Detail(ctx context.Context, model *Subcategory) (*Subcategory, error)
+	// DetailForShare は Detail と同じくサブカテゴリを取得し、トランザクション中はコミットまで
+	// 他のトランザクションによるサブカテゴリの変更・削除を待たせます
+	// キャッシュやレプリカは使わず、常にプライマリから読み取ります
+	DetailForShare(ctx context.Context, model *Subcategory) (*Subcategory, error)
 	Delete(ctx context.Context, model *Subcategory) error
 }
 
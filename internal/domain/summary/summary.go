@@ -15,6 +15,9 @@ type ISummaryRepository interface {
 	List(ctx context.Context, opts ListOptions) (*ListResult, error)
 	Detail(ctx context.Context, model *Summary) (*Summary, error)
 	Delete(ctx context.Context, model *Summary) error
+	// Publish は説明を確定したサマリーを公開し、公開日時を記録します
+	// 公開済みの場合は Errors.ErrConflict、存在しない場合は Errors.ErrRecordNotFound を返します
+	Publish(ctx context.Context, model *Summary) error
 }
 
 type ListOptions struct {
@@ -41,6 +44,7 @@ type Summary struct {
 	CategoryID    sql.NullString           `json:"category_id"`
 	SubcategoryID sql.NullString           `json:"subcategory_id"`
 	UserID        string                   `json:"user_id"`
+	PublishedAt   sql.NullTime             `json:"published_at"`
 	User          *user.User               `json:"user,omitempty"`
 	Category      *category.Category       `json:"category,omitempty"`
 	Subcategory   *subcategory.Subcategory `json:"subcategory,omitempty"`
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ITxManager は複数のリポジトリ呼び出しを1つのトランザクションで実行します
+type ITxManager interface {
+	// WithinTx は fn を1つのトランザクションで実行します
+	// fn がエラーを返した場合はロールバックし、それ以外はコミットします
+	// fn に渡す ctx を使ってリポジトリを呼び出すと、同じトランザクションでクエリが実行されます
+	// すでにトランザクション中の場合はセーブポイントを作成し、fn のエラー時はセーブポイントまでロールバックします
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+// TxOptions はトランザクションの設定です
+// ネストしたトランザクション(セーブポイント)では外側のトランザクションの設定が使われます
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+}
+
+type TxOption func(*TxOptions)
+
+// WithIsolation はトランザクション分離レベルを指定します
+// 指定しない場合はデータベースのデフォルト(MySQLではREPEATABLE READ)になります
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.Isolation = level
+	}
+}
+
+// WithReadOnly は読み取り専用のトランザクションにします
+func WithReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.ReadOnly = true
+	}
+}
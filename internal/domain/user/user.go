@@ -8,11 +8,28 @@ import (
 
 type IUserRepository interface {
 	Save(ctx context.Context, model *User) error
-	List(ctx context.Context) (UserSlice, error)
+	Update(ctx context.Context, model *User) error
+	List(ctx context.Context, opts ListOptions) (*ListResult, error)
 	Detail(ctx context.Context, model *User) (*User, error)
 	Delete(ctx context.Context, model *User) error
 }
 
+type ListOptions struct {
+	Email    string
+	Name     string
+	UserType string
+	Limit    int
+	Offset   int
+}
+
+type ListResult struct {
+	Items   UserSlice `json:"items"`
+	Total   int       `json:"total"`
+	Limit   int       `json:"limit"`
+	Offset  int       `json:"offset"`
+	HasNext bool      `json:"has_next"`
+}
+
 type User struct {
 	domain.WYHBaseModel
 	Name     string `json:"name"`
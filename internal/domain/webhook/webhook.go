@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+)
+
+type IWebhookRepository interface {
+	// Save はWebhookを保存します。同じIDのWebhookがある場合は更新します
+	Save(ctx context.Context, model *Webhook) error
+	// List はユーザーのWebhookを返します。userID が空の場合はすべてのWebhookを返します
+	List(ctx context.Context, userID string) (WebhookSlice, error)
+	Detail(ctx context.Context, model *Webhook) (*Webhook, error)
+	// Delete はWebhookと配信履歴を削除します
+	Delete(ctx context.Context, model *Webhook) error
+}
+
+// 配信状態
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	// DeliveryStatusDead は再試行の上限に達して配信を諦めた状態です
+	DeliveryStatusDead = "dead"
+)
+
+type IDeliveryRepository interface {
+	// Create は配信を保存します
+	// 同じWebhookに同じ DedupKey の配信がある場合は Errors.ErrUniqueConstraint を返します
+	Create(ctx context.Context, model *Delivery) error
+	// List はWebhookの配信履歴を新しい順に返します
+	List(ctx context.Context, webhookID string, limit, offset int) (*DeliveryListResult, error)
+	Detail(ctx context.Context, model *Delivery) (*Delivery, error)
+	// Claim は配信予定時刻を過ぎた未配信の配信を古い順に最大 limit 件取得します
+	// 取得した配信は lease の間、他の送信処理から取得されません
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	// UpdateResult は送信の結果 (状態、試行回数、レスポンス、エラー、次の配信予定時刻、配信日時) を保存します
+	UpdateResult(ctx context.Context, model *Delivery) error
+}
+
+// Webhook は指定した種類のイベントを通知する送信先です
+// Secret は配信の署名に使うため、レスポンスには含めません
+type Webhook struct {
+	domain.WYHBaseModel
+	UserID     string       `json:"user_id"`
+	URL        string       `json:"url"`
+	Secret     string       `json:"-"`
+	EventTypes []event.Type `json:"event_types"`
+	Active     bool         `json:"active"`
+}
+
+type WebhookSlice []*Webhook
+
+// Subscribes はWebhookがイベントの種類を購読しているかを返します
+func (w *Webhook) Subscribes(t event.Type) bool {
+	if !w.Active {
+		return false
+	}
+	for _, et := range w.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery は1つのイベントを1つのWebhookに送信した記録です
+// Payload は送信するリクエストのボディで、再送でも同じ内容を送信します
+type Delivery struct {
+	ID        string          `json:"id"`
+	Seq       int64           `json:"-"`
+	WebhookID string          `json:"webhook_id"`
+	EventID   string          `json:"event_id"`
+	EventType event.Type      `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	// DedupKey はアウトボックスから同じイベントが複数回届いた場合に重複して配信しないためのキーです
+	// 再送の場合は空にします
+	DedupKey       string     `json:"-"`
+	RedeliveryOf   string     `json:"redelivery_of,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+type DeliverySlice []*Delivery
+
+type DeliveryListResult struct {
+	Items   DeliverySlice `json:"items"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+	HasNext bool          `json:"has_next"`
+}
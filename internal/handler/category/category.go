@@ -7,8 +7,10 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/category"
 	"github.com/o-ga09/web-ya-hime/internal/handler/request"
 	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/httputil"
-	"github.com/o-ga09/web-ya-hime/pkg/logger"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/uuid"
 )
 
@@ -31,7 +33,7 @@ func New(repo category.ICategoryRepository) ICategoryHandler {
 
 func (h *categoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost && r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -39,16 +41,16 @@ func (h *categoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 	var req request.CategorySaveRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
 	if r.Method == http.MethodPut && req.ID == "" {
-		http.Error(w, "Category ID is required for update", http.StatusBadRequest)
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgIDRequiredForUpdate, i18n.T(ctx, i18n.ResCategory))))
 		return
 	}
 
@@ -66,8 +68,7 @@ func (h *categoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Save(ctx, model); err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to save category", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -78,7 +79,7 @@ func (h *categoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 func (h *categoryHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -86,8 +87,7 @@ func (h *categoryHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	categories, err := h.repo.List(ctx)
 	if err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to get category list", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -104,12 +104,12 @@ func (h *categoryHandler) List(w http.ResponseWriter, r *http.Request) {
 	res := response.CategoryListResponse{
 		Categories: categoryResponses,
 	}
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (h *categoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -117,11 +117,11 @@ func (h *categoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 
 	var req request.CategoryDetailRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -133,8 +133,7 @@ func (h *categoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 
 	cat, err := h.repo.Detail(ctx, model)
 	if err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to get category detail", http.StatusNotFound)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -144,12 +143,12 @@ func (h *categoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: cat.CreatedAt,
 		UpdatedAt: cat.UpdatedAt,
 	}
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (h *categoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -157,11 +156,11 @@ func (h *categoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	var req request.CategoryDeleteRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -172,12 +171,11 @@ func (h *categoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Delete(ctx, model); err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to delete category", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
 	httputil.Response(&w, http.StatusOK, map[string]string{
-		"message": "Category deleted successfully",
+		"message": i18n.T(ctx, i18n.MsgDeleted, i18n.T(ctx, i18n.ResCategory)),
 	})
 }
@@ -1,14 +1,14 @@
 package request
 
 type CategorySaveRequest struct {
-	ID   string `json:"id" path:"id"`
+	ID   string `json:"id" path:"id" validate:"uuid"`
 	Name string `json:"name" validate:"required"`
 }
 
 type CategoryDetailRequest struct {
-	ID string `json:"id" path:"id" validate:"required"`
+	ID string `json:"id" path:"id" validate:"required,uuid"`
 }
 
 type CategoryDeleteRequest struct {
-	ID string `json:"id" path:"id" validate:"required"`
+	ID string `json:"id" path:"id" validate:"required,uuid"`
 }
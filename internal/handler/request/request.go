@@ -1,122 +1,217 @@
 package request
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
-)
 
-// Validate はリフレクションを使用してvalidateタグに基づいたバリデーションを行います
-func Validate(v interface{}) error {
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
 
-	typ := val.Type()
+// multipartMaxMemory はマルチパートのフォームでメモリに保持する最大のサイズです。超えた分は一時ファイルに保存します
+const multipartMaxMemory = 8 << 20
 
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
-		value := val.Field(i)
-		validateTag := field.Tag.Get("validate")
+// Strict を実装したリクエストの構造体は、構造体にないフィールドを含むJSONを拒否します
+type Strict interface {
+	DisallowUnknownFields()
+}
 
-		if validateTag == "" {
-			continue
+// Bind はHTTPリクエストから構造体にデータをバインドします
+// JSONボディ、フォーム、クエリパラメータ、URLパスパラメータ、ヘッダーをサポートします
+// タグ: json, form, query, path, header
+// ボディは Content-Type が application/json (または +json) の場合だけ受け付け、それ以外は Errors.ErrUnsupportedMediaType を返します
+// form タグのある構造体では multipart/form-data と application/x-www-form-urlencoded も受け付けます
+// ボディのサイズの上限を超えた場合は Errors.ErrRequestEntityTooLarge を返します
+func Bind(r *http.Request, v interface{}) error {
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if err := bindBody(r, v); err != nil {
+			return err
 		}
+	}
 
-		rules := strings.Split(validateTag, ",")
-		for _, rule := range rules {
-			if err := validateField(field.Name, value, rule); err != nil {
-				return err
-			}
-		}
+	// クエリパラメータのバインド
+	if err := bindQuery(r, v); err != nil {
+		return err
+	}
+
+	// パスパラメータのバインド（カスタムコンテキストから取得）
+	if err := bindPath(r, v); err != nil {
+		return err
+	}
+
+	// ヘッダーのバインド
+	if err := bindTag(v, "header", r.Header.Get); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func validateField(fieldName string, value reflect.Value, rule string) error {
-	switch {
-	case rule == "required":
-		if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
-			return fmt.Errorf("%s is required", fieldName)
-		}
-		if value.Kind() == reflect.Int && value.Int() == 0 {
-			return fmt.Errorf("%s is required", fieldName)
-		}
-	case strings.HasPrefix(rule, "max="):
-		maxStr := strings.TrimPrefix(rule, "max=")
-		max, err := strconv.Atoi(maxStr)
-		if err != nil {
-			return fmt.Errorf("invalid max value for %s", fieldName)
-		}
-		if value.Kind() == reflect.String && len(value.String()) > max {
-			return fmt.Errorf("%s must be less than or equal to %d characters", fieldName, max)
-		}
-		if value.Kind() == reflect.Int && value.Int() > int64(max) {
-			return fmt.Errorf("%s must be less than or equal to %d", fieldName, max)
-		}
-	case strings.HasPrefix(rule, "min="):
-		minStr := strings.TrimPrefix(rule, "min=")
-		min, err := strconv.Atoi(minStr)
-		if err != nil {
-			return fmt.Errorf("invalid min value for %s", fieldName)
+// bindBody は Content-Type に合わせてボディを構造体にバインドします。ボディが空の場合は何もしません
+func bindBody(r *http.Request, v interface{}) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	defer r.Body.Close()
+
+	body := bufio.NewReader(r.Body)
+	if _, err := body.Peek(1); err != nil {
+		if err == io.EOF {
+			return nil
 		}
-		if value.Kind() == reflect.String && len(value.String()) < min {
-			return fmt.Errorf("%s must be at least %d characters", fieldName, min)
+		return readError(err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return bindJSON(body, v)
+	case hasTag(v, "form") && (mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded"):
+		r.Body = io.NopCloser(body)
+		return bindForm(r, mediaType, v)
+	}
+	return fmt.Errorf("content type %q: %w", mediaType, Errors.ErrUnsupportedMediaType)
+}
+
+// readError はボディの読み込みのエラーを返します。サイズの上限を超えた場合は Errors.ErrRequestEntityTooLarge にします
+func readError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("request body exceeds %d bytes: %w", maxBytesErr.Limit, Errors.ErrRequestEntityTooLarge)
+	}
+	return fmt.Errorf("failed to read request body: %w", err)
+}
+
+// bindJSON はJSONボディを構造体にバインドします（jsonタグを使用）
+// JSONの後に続くデータは拒否し、Strict を実装した構造体では未知のフィールドも拒否します
+func bindJSON(body io.Reader, v interface{}) error {
+	dec := json.NewDecoder(body)
+	if _, ok := v.(Strict); ok {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		if bodyErr := readError(err); errors.Is(bodyErr, Errors.ErrRequestEntityTooLarge) {
+			return bodyErr
 		}
-		if value.Kind() == reflect.Int && value.Int() > 0 && value.Int() < int64(min) {
-			return fmt.Errorf("%s must be at least %d", fieldName, min)
+		return fmt.Errorf("failed to parse JSON body: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if bodyErr := readError(err); errors.Is(bodyErr, Errors.ErrRequestEntityTooLarge) {
+			return bodyErr
 		}
+		return fmt.Errorf("failed to parse JSON body: unexpected data after JSON value")
 	}
-
 	return nil
 }
 
-// Bind はHTTPリクエストから構造体にデータをバインドします
-// JSONボディ、クエリパラメータ、URLパスパラメータをサポートします
-// タグ: json, query, path
-func Bind(r *http.Request, v interface{}) error {
-	// JSONボディのバインド（Content-TypeがapplicationまたはPOST/PUT/PATCHメソッドの場合）
-	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
-		if r.Body != nil {
-			body, err := io.ReadAll(r.Body)
-			if err != nil {
-				return fmt.Errorf("failed to read request body: %w", err)
-			}
-			defer r.Body.Close()
+// bindForm はフォームを構造体にバインドします（formタグを使用）
+// マルチパートのファイルは *multipart.FileHeader のフィールドにバインドします
+func bindForm(r *http.Request, mediaType string, v interface{}) error {
+	var err error
+	if mediaType == "multipart/form-data" {
+		err = r.ParseMultipartForm(multipartMaxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		if bodyErr := readError(err); errors.Is(bodyErr, Errors.ErrRequestEntityTooLarge) {
+			return bodyErr
+		}
+		return fmt.Errorf("failed to parse form: %w", err)
+	}
 
-			if len(body) > 0 {
-				if err := bindJSON(body, v); err != nil {
-					return err
-				}
-			}
+	if r.MultipartForm != nil {
+		if err := bindFiles(r.MultipartForm, v); err != nil {
+			return err
 		}
 	}
+	return bindTag(v, "form", r.PostForm.Get)
+}
 
-	// クエリパラメータのバインド
-	if err := bindQuery(r, v); err != nil {
+// bindFiles はマルチパートのファイルを *multipart.FileHeader のフィールドにバインドします
+func bindFiles(form *multipart.Form, v interface{}) error {
+	val, err := structValue(v)
+	if err != nil {
 		return err
 	}
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type != fileHeaderType || !val.Field(i).CanSet() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if files := form.File[name]; name != "" && name != "-" && len(files) > 0 {
+			val.Field(i).Set(reflect.ValueOf(files[0]))
+		}
+	}
+	return nil
+}
 
-	// パスパラメータのバインド（カスタムコンテキストから取得）
-	if err := bindPath(r, v); err != nil {
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
+// bindTag は tag の名前で lookup から取得した値を構造体にバインドします（form、headerタグ）
+func bindTag(v interface{}, tag string, lookup func(string) string) error {
+	val, err := structValue(v)
+	if err != nil {
 		return err
 	}
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		fieldValue := val.Field(i)
+		if !fieldValue.CanSet() || field.Type == fileHeaderType {
+			continue
+		}
 
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		value := lookup(name)
+		if value == "" {
+			continue
+		}
+		if err := setFieldValue(fieldValue, value); err != nil {
+			return fmt.Errorf("failed to set field %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
-// bindJSON はJSONボディを構造体にバインドします（jsonタグを使用）
-func bindJSON(body []byte, v interface{}) error {
-	if err := json.Unmarshal(body, v); err != nil {
-		return fmt.Errorf("failed to parse JSON body: %w", err)
+// hasTag は構造体に tag のフィールドがあるかを返します
+func hasTag(v interface{}, tag string) bool {
+	val, err := structValue(v)
+	if err != nil {
+		return false
 	}
-	return nil
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if name, _, _ := strings.Cut(typ.Field(i).Tag.Get(tag), ","); name != "" && name != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// structValue は構造体かそのポインターの値を返します
+func structValue(v interface{}) (reflect.Value, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("v must be a struct or pointer to struct")
+	}
+	return val, nil
 }
 
 // bindQuery はクエリパラメータを構造体にバインドします（queryタグを使用）
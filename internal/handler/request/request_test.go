@@ -0,0 +1,186 @@
+package request
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindRequest struct {
+	ID      string `json:"id" path:"id"`
+	Name    string `json:"name"`
+	Limit   int    `query:"limit"`
+	IfMatch string `header:"If-Match"`
+}
+
+type strictBindRequest struct {
+	Name string `json:"name"`
+}
+
+func (*strictBindRequest) DisallowUnknownFields() {}
+
+type formBindRequest struct {
+	Title string                `form:"title"`
+	Count int                   `form:"count"`
+	File  *multipart.FileHeader `form:"file"`
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bindRequest
+		wantErr     error
+		wantErrMsg  string
+	}{
+		{
+			name:        "成功ケース: JSONボディとクエリ、パス、ヘッダーをバインドする",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"taro","unknown":1}`,
+			want:        bindRequest{ID: "u1", Name: "taro", Limit: 10, IfMatch: `"v1"`},
+		},
+		{
+			name:        "成功ケース: +json の Content-Type",
+			contentType: "application/merge-patch+json",
+			body:        `{"name":"taro"}`,
+			want:        bindRequest{ID: "u1", Name: "taro", Limit: 10, IfMatch: `"v1"`},
+		},
+		{
+			name: "成功ケース: 空のボディは Content-Type を確認しない",
+			want: bindRequest{ID: "u1", Limit: 10, IfMatch: `"v1"`},
+		},
+		{
+			name:        "失敗ケース: JSONの後に続くデータ",
+			contentType: "application/json",
+			body:        `{"name":"taro"} {"name":"jiro"}`,
+			wantErrMsg:  "unexpected data after JSON value",
+		},
+		{
+			name:        "失敗ケース: JSONでない Content-Type",
+			contentType: "text/plain",
+			body:        `{"name":"taro"}`,
+			wantErr:     Errors.ErrUnsupportedMediaType,
+		},
+		{
+			name:    "失敗ケース: Content-Type がない",
+			body:    `{"name":"taro"}`,
+			wantErr: Errors.ErrUnsupportedMediaType,
+		},
+		{
+			name:        "失敗ケース: form タグのない構造体はフォームを受け付けない",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "name=taro",
+			wantErr:     Errors.ErrUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users/u1?limit=10", strings.NewReader(tt.body))
+			r.SetPathValue("id", "u1")
+			r.Header.Set("If-Match", `"v1"`)
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			var got bindRequest
+			err := Bind(r, &got)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrMsg != "":
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBind_Strict(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"taro","nmae":"jiro"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var got strictBindRequest
+	err := Bind(r, &got)
+	assert.ErrorContains(t, err, `unknown field "nmae"`)
+}
+
+func TestBind_MaxBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		v           interface{}
+	}{
+		{name: "失敗ケース: JSONボディが上限を超える", contentType: "application/json", body: `{"name":"` + strings.Repeat("a", 100) + `"}`, v: &bindRequest{}},
+		{name: "失敗ケース: JSONの後のデータが上限を超える", contentType: "application/json", body: `{"name":"a"}` + strings.Repeat(" ", 100), v: &bindRequest{}},
+		{name: "失敗ケース: フォームが上限を超える", contentType: "application/x-www-form-urlencoded", body: "title=" + strings.Repeat("a", 100), v: &formBindRequest{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 50)
+
+			err := Bind(r, tt.v)
+			assert.ErrorIs(t, err, Errors.ErrRequestEntityTooLarge)
+		})
+	}
+}
+
+func TestBind_Form(t *testing.T) {
+	t.Run("成功ケース: マルチパートのフォームとファイル", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		assert.NoError(t, mw.WriteField("title", "概要欄"))
+		assert.NoError(t, mw.WriteField("count", "3"))
+		fw, err := mw.CreateFormFile("file", "summary.txt")
+		assert.NoError(t, err)
+		_, _ = fw.Write([]byte("content"))
+		assert.NoError(t, mw.Close())
+
+		r := httptest.NewRequest(http.MethodPost, "/summaries", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+
+		var got formBindRequest
+		assert.NoError(t, Bind(r, &got))
+		assert.Equal(t, "概要欄", got.Title)
+		assert.Equal(t, 3, got.Count)
+		if assert.NotNil(t, got.File) {
+			assert.Equal(t, "summary.txt", got.File.Filename)
+			f, err := got.File.Open()
+			assert.NoError(t, err)
+			defer f.Close()
+			content, _ := io.ReadAll(f)
+			assert.Equal(t, "content", string(content))
+		}
+	})
+
+	t.Run("成功ケース: URLエンコードのフォーム", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/summaries", strings.NewReader("title=a%26b&count=2"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var got formBindRequest
+		assert.NoError(t, Bind(r, &got))
+		assert.Equal(t, formBindRequest{Title: "a&b", Count: 2}, got)
+	})
+
+	t.Run("失敗ケース: 数値でない値", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/summaries", strings.NewReader("count=many"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var got formBindRequest
+		assert.ErrorContains(t, Bind(r, &got), "failed to set field count")
+	})
+}
@@ -0,0 +1,9 @@
+package request
+
+// SummaryStreamRequest はサマリーのイベントの購読リクエストの構造体
+// LastEventID は Last-Event-ID ヘッダーを送れない最初の接続で、途中から購読するために使います
+type SummaryStreamRequest struct {
+	CategoryID  string `query:"category_id" validate:"omitempty,uuid"`
+	SummaryID   string `query:"summary_id" validate:"omitempty,uuid"`
+	LastEventID string `query:"last_event_id"`
+}
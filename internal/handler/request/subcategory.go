@@ -1,19 +1,19 @@
 package request
 
 type SubcategorySaveRequest struct {
-	ID         string `json:"id" path:"id"`
-	CategoryID string `json:"category_id" validate:"required"`
+	ID         string `json:"id" path:"id" validate:"uuid"`
+	CategoryID string `json:"category_id" validate:"required,uuid"`
 	Name       string `json:"name" validate:"required"`
 }
 
 type SubcategoryListRequest struct {
-	CategoryID string `json:"category_id" query:"category_id"`
+	CategoryID string `json:"category_id" query:"category_id" validate:"uuid"`
 }
 
 type SubcategoryDetailRequest struct {
-	ID string `json:"id" path:"id" validate:"required"`
+	ID string `json:"id" path:"id" validate:"required,uuid"`
 }
 
 type SubcategoryDeleteRequest struct {
-	ID string `json:"id" path:"id" validate:"required"`
+	ID string `json:"id" path:"id" validate:"required,uuid"`
 }
@@ -14,16 +14,16 @@ type SaveSummaryRequest struct {
 	Title         string  `json:"title" validate:"required,max=255"`
 	Description   string  `json:"description" validate:"max=5000"`
 	Content       string  `json:"content" validate:"required"`
-	CategoryID    *string `json:"category_id" validate:"omitempty"`
-	SubcategoryID *string `json:"subcategory_id" validate:"omitempty"`
+	CategoryID    *string `json:"category_id" validate:"omitempty,uuid"`
+	SubcategoryID *string `json:"subcategory_id" validate:"omitempty,uuid"`
 	UserID        string  `json:"user_id"`
 }
 
 // ListSummaryRequest はリスト取得リクエストの構造体
 type ListSummaryRequest struct {
 	Category      *string `query:"category"`
-	CategoryID    *string `query:"category_id"`
-	SubcategoryID *string `query:"subcategory_id"`
+	CategoryID    *string `query:"category_id" validate:"omitempty,uuid"`
+	SubcategoryID *string `query:"subcategory_id" validate:"omitempty,uuid"`
 	Limit         int     `query:"limit" validate:"min=1,max=100"`
 	Offset        int     `query:"offset" validate:"min=0"`
 }
@@ -38,6 +38,11 @@ type DeleteSummaryRequest struct {
 	ID string `path:"id" validate:"required"`
 }
 
+// PublishSummaryRequest は公開リクエストの構造体
+type PublishSummaryRequest struct {
+	ID string `path:"id" validate:"required"`
+}
+
 func (s *SaveSummaryRequest) ToModel() *summary.Summary {
 	id := uuid.GenerateID()
 	if s.ID != nil {
@@ -9,16 +9,19 @@ import (
 
 // SaveUserRequest は保存リクエストの構造体
 type SaveUserRequest struct {
-	ID       *string `json:"id,omitempty"`
+	ID       *string `json:"id,omitempty" path:"id"`
 	Name     string  `json:"name" validate:"required,max=100"`
-	Email    string  `json:"email" validate:"required,max=255"`
+	Email    string  `json:"email" validate:"required,max=255,email"`
 	UserType string  `json:"user_type" validate:"required"`
 }
 
 // ListUserRequest はリスト取得リクエストの構造体
 type ListUserRequest struct {
-	Page  int `query:"page" validate:"min=1"`
-	Limit int `query:"limit" validate:"min=1,max=100"`
+	Email    *string `query:"email" validate:"omitempty,email"`
+	Name     *string `query:"name"`
+	UserType *string `query:"user_type"`
+	Page     int     `query:"page" validate:"min=1"`
+	Limit    int     `query:"limit" validate:"min=1,max=100"`
 }
 
 // DetailUserRequest は詳細取得リクエストの構造体
@@ -31,6 +34,14 @@ type DeleteUserRequest struct {
 	ID string `path:"id" validate:"required"`
 }
 
+// Offset はページ番号と取得件数からオフセットを計算します
+func (s *ListUserRequest) Offset() int {
+	if s.Page <= 1 {
+		return 0
+	}
+	return (s.Page - 1) * s.Limit
+}
+
 func (s *SaveUserRequest) ToModel() *user.User {
 	id := uuid.GenerateID()
 	if s.ID != nil {
@@ -0,0 +1,522 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+// FieldError は1つのフィールドのバリデーションエラーを表します
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors はバリデーションで検出したすべてのエラーを保持します
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidatorFunc はカスタムバリデーションルールの実装です
+// value にはポインタを外した値が渡され、param には "rule=param" の param 部分が渡されます
+// 値が妥当な場合は true を返します
+type ValidatorFunc func(value reflect.Value, param string) bool
+
+var (
+	customValidatorsMu sync.RWMutex
+	customValidators   = map[string]ValidatorFunc{}
+)
+
+// RegisterValidator はカスタムバリデーションルールを登録します
+// 組み込みルールと同じ名前は登録できません
+func RegisterValidator(name string, fn ValidatorFunc) error {
+	if name == "" || fn == nil {
+		return fmt.Errorf("validator name and func are required")
+	}
+	if _, ok := builtinRules[name]; ok || name == "omitempty" || name == "dive" {
+		return fmt.Errorf("validator %s is a builtin rule", name)
+	}
+
+	customValidatorsMu.Lock()
+	defer customValidatorsMu.Unlock()
+	customValidators[name] = fn
+	return nil
+}
+
+func customValidator(name string) (ValidatorFunc, bool) {
+	customValidatorsMu.RLock()
+	defer customValidatorsMu.RUnlock()
+	fn, ok := customValidators[name]
+	return fn, ok
+}
+
+// ruleFunc は組み込みルールの実装です
+// 値が妥当な場合は true を返し、param が不正な場合は error を返します
+// エラーメッセージはルール名から messageIDOf で決まるメッセージカタログのキーで生成します
+type ruleFunc func(value reflect.Value, param string) (bool, error)
+
+var builtinRules map[string]ruleFunc
+
+func init() {
+	builtinRules = map[string]ruleFunc{
+		"required":   validateRequired,
+		"max":        validateMax,
+		"min":        validateMin,
+		"len":        validateLen,
+		"gt":         validateCompare("gt"),
+		"gte":        validateCompare("gte"),
+		"lt":         validateCompare("lt"),
+		"lte":        validateCompare("lte"),
+		"email":      validateEmail,
+		"uuid":       validateUUID,
+		"url":        validateURL,
+		"public_url": validatePublicURL,
+		"oneof":      validateOneOf,
+		"regexp":     validateRegexp,
+	}
+}
+
+// Validate はリフレクションを使用してvalidateタグに基づいたバリデーションを行います
+// 最初のエラーで中断せず、すべてのフィールドを検証してValidationErrorsとして返します
+// 1つのフィールドについては最初に違反したルールのみを報告します
+//
+//   - ポインタはnilの場合 required 以外のルールを評価せず、nilでなければ参照先の値を検証します
+//   - omitempty はゼロ値(nilポインタ、空文字、0、空スライスなど)の場合に以降のルールを省略します
+//   - dive 以降のルールはスライス・配列・マップの各要素に適用されます
+//   - 構造体のフィールドは再帰的に検証され、エラーのフィールド名は "items[0].name" のようになります
+//   - email ルールは検証に加えて、フィールドの値を正規化した値で上書きします
+//   - エラーメッセージはcontextに設定された言語で生成します
+func Validate(ctx context.Context, v interface{}) error {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("v must be a struct or pointer to struct")
+	}
+
+	vd := &validator{lang: i18n.FromContext(ctx)}
+	vd.validateStruct("", val)
+
+	if len(vd.errs) > 0 {
+		return vd.errs
+	}
+	return nil
+}
+
+// validator は1回の Validate 呼び出しの言語と検出したエラーを保持します
+type validator struct {
+	lang i18n.Lang
+	errs ValidationErrors
+}
+
+// validateStruct は構造体の各フィールドを検証します
+func (vd *validator) validateStruct(prefix string, val reflect.Value) {
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		validateTag := field.Tag.Get("validate")
+		if validateTag == "-" {
+			continue
+		}
+
+		// 埋め込み構造体はフィールド名の階層を作らずに展開する
+		name := joinFieldPath(prefix, fieldNameOf(field))
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			name = prefix
+		}
+
+		var rules []string
+		if validateTag != "" {
+			rules = splitRules(validateTag)
+		}
+		vd.validateValue(name, val.Field(i), rules)
+	}
+}
+
+// validateValue は値にルールを順に適用し、問題がなければ構造体の場合は中へ再帰します
+func (vd *validator) validateValue(name string, value reflect.Value, rules []string) {
+	for i, rule := range rules {
+		ruleName, param, _ := strings.Cut(rule, "=")
+		switch ruleName {
+		case "omitempty":
+			if isEmpty(value) {
+				return
+			}
+			continue
+		case "dive":
+			vd.validateDive(name, value, rules[i+1:])
+			return
+		}
+
+		if id, ok := applyRule(value, ruleName, param); !ok {
+			vd.errs = append(vd.errs, &FieldError{
+				Field:   name,
+				Rule:    ruleName,
+				Param:   param,
+				Message: i18n.Message(vd.lang, id, name, param),
+			})
+			return
+		}
+	}
+
+	if elem, ok := indirect(value); ok && elem.Kind() == reflect.Struct {
+		vd.validateStruct(name, elem)
+	}
+}
+
+// validateDive はスライス・配列・マップの各要素に dive 以降のルールを適用します
+func (vd *validator) validateDive(name string, value reflect.Value, rules []string) {
+	elem, ok := indirect(value)
+	if !ok {
+		return
+	}
+
+	switch elem.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < elem.Len(); i++ {
+			vd.validateValue(fmt.Sprintf("%s[%d]", name, i), elem.Index(i), rules)
+		}
+	case reflect.Map:
+		keys := elem.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, key := range keys {
+			// マップの要素はアドレスを取れないため、コピーを検証して正規化した値を書き戻す
+			item := reflect.New(elem.Type().Elem()).Elem()
+			item.Set(elem.MapIndex(key))
+			vd.validateValue(fmt.Sprintf("%s[%v]", name, key.Interface()), item, rules)
+			elem.SetMapIndex(key, item)
+		}
+	}
+}
+
+// applyRule はポインタを外した値に1つのルールを適用します
+// 違反した場合は false とエラーメッセージのキーを返します
+func applyRule(value reflect.Value, ruleName, param string) (i18n.MessageID, bool) {
+	elem, ok := indirect(value)
+	if !ok {
+		// nilポインタは required のみで検出する
+		return i18n.MsgValidationRequired, ruleName != "required"
+	}
+
+	if fn, ok := builtinRules[ruleName]; ok {
+		valid, err := fn(elem, param)
+		if err != nil {
+			return i18n.MsgValidationInvalidParam, false
+		}
+		return messageIDOf(ruleName, elem), valid
+	}
+	if fn, ok := customValidator(ruleName); ok {
+		return i18n.MsgValidationCustom, fn(elem, param)
+	}
+	return i18n.MsgValidationUnknownRule, false
+}
+
+// messageIDOf はルールに違反したときのメッセージカタログのキーを返します
+// 大小比較のルールは数値・文字数・要素数で文言が異なるため値の種類をキーに含めます
+func messageIDOf(ruleName string, value reflect.Value) i18n.MessageID {
+	switch ruleName {
+	case "max", "min", "len", "gt", "gte", "lt", "lte":
+		return i18n.MessageID("validation." + ruleName + "." + sizeKindOf(value))
+	}
+	return i18n.MessageID("validation." + ruleName)
+}
+
+// indirect はポインタとインターフェースを外した値を返します
+// nilの場合は false を返します
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
+
+// isEmpty は omitempty の判定に使うゼロ値判定です
+func isEmpty(value reflect.Value) bool {
+	elem, ok := indirect(value)
+	if !ok {
+		return true
+	}
+	switch elem.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(elem.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return elem.Len() == 0
+	default:
+		return elem.IsZero()
+	}
+}
+
+// fieldNameOf はエラーに表示するフィールド名を json, query, path タグの順に探して返します
+// いずれのタグもない場合は構造体のフィールド名を返します
+func fieldNameOf(field reflect.StructField) string {
+	for _, key := range []string{"json", "query", "path"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func joinFieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// splitRules はvalidateタグをルールごとに分割します
+// regexp= はパターンにカンマを含められるよう、タグの残り全体をパターンとして扱います
+func splitRules(tag string) []string {
+	parts := strings.Split(tag, ",")
+	rules := make([]string, 0, len(parts))
+	for i, part := range parts {
+		if strings.HasPrefix(part, "regexp=") {
+			rules = append(rules, strings.Join(parts[i:], ","))
+			break
+		}
+		rules = append(rules, part)
+	}
+	return rules
+}
+
+// sizeOf は数値はその値、文字列は文字数、スライス・配列・マップは要素数を返します
+// 比較できない型の場合は false を返します
+func sizeOf(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	}
+	return 0, false
+}
+
+func isNumber(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// sizeKindOf は sizeOf が返す値の種類(数値・文字数・要素数)を返します
+func sizeKindOf(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return "number"
+}
+
+func validateRequired(value reflect.Value, _ string) (bool, error) {
+	return !isEmpty(value) || value.Kind() == reflect.Bool, nil
+}
+
+func validateMax(value reflect.Value, param string) (bool, error) {
+	max, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false, err
+	}
+	size, ok := sizeOf(value)
+	return !ok || size <= max, nil
+}
+
+// validateMin は最小値を検証します
+// 数値のゼロ値は未指定とみなして検証しません
+func validateMin(value reflect.Value, param string) (bool, error) {
+	min, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false, err
+	}
+	if isNumber(value) && value.IsZero() {
+		return true, nil
+	}
+	size, ok := sizeOf(value)
+	return !ok || size >= min, nil
+}
+
+// validateLen は文字列の文字数(バイト数ではない)、または要素数が指定値と一致するかを検証します
+func validateLen(value reflect.Value, param string) (bool, error) {
+	length, err := strconv.Atoi(param)
+	if err != nil {
+		return false, err
+	}
+	if isNumber(value) || isEmpty(value) {
+		return true, nil
+	}
+	size, ok := sizeOf(value)
+	return !ok || int(size) == length, nil
+}
+
+// validateCompare は gt/gte/lt/lte の比較ルールを生成します
+// min/max と異なり、数値のゼロ値も比較の対象になります
+func validateCompare(op string) ruleFunc {
+	return func(value reflect.Value, param string) (bool, error) {
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false, err
+		}
+		size, ok := sizeOf(value)
+		if !ok {
+			return true, nil
+		}
+		switch op {
+		case "gt":
+			return size > limit, nil
+		case "gte":
+			return size >= limit, nil
+		case "lt":
+			return size < limit, nil
+		default:
+			return size <= limit, nil
+		}
+	}
+}
+
+func validateEmail(value reflect.Value, _ string) (bool, error) {
+	if value.Kind() != reflect.String || value.String() == "" {
+		return true, nil
+	}
+	normalized, err := normalizeEmail(value.String())
+	if err != nil {
+		return false, nil
+	}
+	if value.CanSet() {
+		value.SetString(normalized)
+	}
+	return true, nil
+}
+
+func validateUUID(value reflect.Value, _ string) (bool, error) {
+	return value.Kind() != reflect.String || value.String() == "" || uuid.IsValid(value.String()), nil
+}
+
+func validateURL(value reflect.Value, _ string) (bool, error) {
+	return value.Kind() != reflect.String || value.String() == "" || isURL(value.String()), nil
+}
+
+// validatePublicURL は値が url ルールを満たし、ホストが内部のアドレスでないかを検証します
+// サーバーから送信する宛先に使い、ループバック・プライベート・リンクローカルのアドレスと localhost を拒否します
+func validatePublicURL(value reflect.Value, _ string) (bool, error) {
+	if value.Kind() != reflect.String || value.String() == "" {
+		return true, nil
+	}
+	if !isURL(value.String()) {
+		return false, nil
+	}
+	u, _ := url.ParseRequestURI(value.String())
+	return httputil.IsPublicHost(u.Hostname()), nil
+}
+
+// validateOneOf は値がスペース区切りで指定された候補のいずれかであるかを検証します
+func validateOneOf(value reflect.Value, param string) (bool, error) {
+	if isEmpty(value) {
+		return true, nil
+	}
+	var str string
+	switch {
+	case value.Kind() == reflect.String:
+		str = value.String()
+	case isNumber(value):
+		str = fmt.Sprint(value.Interface())
+	default:
+		return true, nil
+	}
+	return slices.Contains(strings.Fields(param), str), nil
+}
+
+func validateRegexp(value reflect.Value, param string) (bool, error) {
+	re, err := compileRegexp(param)
+	if err != nil {
+		return false, err
+	}
+	return value.Kind() != reflect.String || value.String() == "" || re.MatchString(value.String()), nil
+}
+
+// normalizeEmail はメールアドレスを検証し、前後の空白除去とドメイン部の小文字化を行います
+// "Name <user@example.com>" のような表示名付きの形式は受け付けません
+func normalizeEmail(email string) (string, error) {
+	trimmed := strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(trimmed)
+	if err != nil {
+		return "", err
+	}
+	if addr.Name != "" || addr.Address != trimmed {
+		return "", fmt.Errorf("display name is not allowed: %s", email)
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	return addr.Address[:at] + "@" + strings.ToLower(addr.Address[at+1:]), nil
+}
+
+// isURL はhttp/httpsの絶対URLかどうかを判定します
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// regexpCache はregexp=ルールのコンパイル済みパターンを保持します
+var regexpCache sync.Map
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Store(pattern, re)
+	return re, nil
+}
@@ -0,0 +1,329 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// enCtx は英語のメッセージを検証するためのcontextです
+var enCtx = i18n.WithLang(context.Background(), i18n.EN)
+
+func TestValidate_Rules(t *testing.T) {
+	type emailRequest struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	type uuidRequest struct {
+		ID string `json:"id" validate:"uuid"`
+	}
+	type oneOfRequest struct {
+		Status string `json:"status" validate:"oneof=draft published"`
+	}
+	type urlRequest struct {
+		URL string `json:"url" validate:"url"`
+	}
+	type publicURLRequest struct {
+		URL string `json:"url" validate:"public_url"`
+	}
+	type regexpRequest struct {
+		Code string `json:"code" validate:"regexp=^[a-z]{2,3}$"`
+	}
+	type lenRequest struct {
+		Code string `json:"code" validate:"len=3"`
+	}
+
+	tests := []struct {
+		name    string
+		req     interface{}
+		wantErr bool
+		errMsg  string
+	}{
+		{name: "成功ケース: 正しいメールアドレス", req: &emailRequest{Email: "user@example.com"}},
+		{name: "失敗ケース: @を含まないメールアドレス", req: &emailRequest{Email: "user.example.com"}, wantErr: true, errMsg: "email must be a valid email address"},
+		{name: "失敗ケース: 表示名付きのメールアドレス", req: &emailRequest{Email: "User <user@example.com>"}, wantErr: true, errMsg: "email must be a valid email address"},
+		{name: "失敗ケース: 山括弧付きのメールアドレス", req: &emailRequest{Email: "<user@example.com>"}, wantErr: true, errMsg: "email must be a valid email address"},
+		{name: "成功ケース: 正しいUUID", req: &uuidRequest{ID: "770e8400-e29b-41d4-a716-446655440001"}},
+		{name: "成功ケース: UUIDが空の場合は検証しない", req: &uuidRequest{}},
+		{name: "失敗ケース: 不正なUUID", req: &uuidRequest{ID: "category-1"}, wantErr: true, errMsg: "id must be a valid UUID"},
+		{name: "失敗ケース: 波括弧付きのUUID", req: &uuidRequest{ID: "{770e8400-e29b-41d4-a716-446655440001}"}, wantErr: true, errMsg: "id must be a valid UUID"},
+		{name: "成功ケース: 候補に含まれる値", req: &oneOfRequest{Status: "draft"}},
+		{name: "失敗ケース: 候補に含まれない値", req: &oneOfRequest{Status: "archived"}, wantErr: true, errMsg: "status must be one of [draft published]"},
+		{name: "成功ケース: 正しいURL", req: &urlRequest{URL: "https://example.com/path"}},
+		{name: "失敗ケース: スキームがないURL", req: &urlRequest{URL: "example.com/path"}, wantErr: true, errMsg: "url must be a valid URL"},
+		{name: "失敗ケース: http/https以外のスキーム", req: &urlRequest{URL: "javascript:alert(1)"}, wantErr: true, errMsg: "url must be a valid URL"},
+		{name: "成功ケース: 外部のホストのURL", req: &publicURLRequest{URL: "https://hooks.example.com/path"}},
+		{name: "失敗ケース: ループバックのURL", req: &publicURLRequest{URL: "http://127.0.0.1:8080/hook"}, wantErr: true, errMsg: "url must be a URL reachable from the internet"},
+		{name: "失敗ケース: メタデータサーバーのURL", req: &publicURLRequest{URL: "http://169.254.169.254/latest/meta-data"}, wantErr: true, errMsg: "url must be a URL reachable from the internet"},
+		{name: "失敗ケース: localhost のURL", req: &publicURLRequest{URL: "http://localhost/hook"}, wantErr: true, errMsg: "url must be a URL reachable from the internet"},
+		{name: "失敗ケース: 形式が不正なURL", req: &publicURLRequest{URL: "example.com/path"}, wantErr: true, errMsg: "url must be a URL reachable from the internet"},
+		{name: "成功ケース: パターンに一致する値", req: &regexpRequest{Code: "ja"}},
+		{name: "失敗ケース: パターンに一致しない値", req: &regexpRequest{Code: "JA"}, wantErr: true, errMsg: "code must match the pattern"},
+		{name: "成功ケース: 文字数が一致する（マルチバイト）", req: &lenRequest{Code: "日本語"}},
+		{name: "失敗ケース: 文字数が一致しない", req: &lenRequest{Code: "ja"}, wantErr: true, errMsg: "code must be exactly 3 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(enCtx, tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidate_NormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{name: "ドメイン部を小文字に変換する", email: "User.Name@Example.COM", want: "User.Name@example.com"},
+		{name: "前後の空白を除去する", email: "  user@example.com\n", want: "user@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &SaveUserRequest{Name: "Test User", Email: tt.email, UserType: "admin"}
+			err := Validate(enCtx, req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, req.Email)
+		})
+	}
+}
+
+func TestValidate_CollectsAllErrors(t *testing.T) {
+	req := &SaveSummaryRequest{
+		Title:       strings.Repeat("a", 256),
+		Description: strings.Repeat("b", 5001),
+	}
+
+	err := Validate(enCtx, req)
+
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Equal(t, ValidationErrors{
+		{Field: "title", Rule: "max", Param: "255", Message: "title must be less than or equal to 255 characters"},
+		{Field: "description", Rule: "max", Param: "5000", Message: "description must be less than or equal to 5000 characters"},
+		{Field: "content", Rule: "required", Message: "content is required"},
+	}, verrs)
+}
+
+func TestValidate_FieldName(t *testing.T) {
+	type request struct {
+		Body  string `json:"body_field,omitempty" validate:"required"`
+		Query int    `query:"query_field" validate:"required"`
+		Path  string `path:"path_field" validate:"required"`
+		NoTag string `validate:"required"`
+	}
+
+	err := Validate(enCtx, &request{})
+
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	fields := make([]string, len(verrs))
+	for i, e := range verrs {
+		fields[i] = e.Field
+	}
+	assert.Equal(t, []string{"body_field", "query_field", "path_field", "NoTag"}, fields)
+}
+
+func TestValidate_PointerAndOmitempty(t *testing.T) {
+	type request struct {
+		Required *string `json:"required" validate:"required"`
+		Optional *string `json:"optional" validate:"omitempty,uuid"`
+		Limit    *int    `json:"limit" validate:"omitempty,gte=1,lte=100"`
+	}
+
+	tests := []struct {
+		name       string
+		req        *request
+		wantFields []string
+	}{
+		{
+			name:       "失敗ケース: requiredのポインタがnil",
+			req:        &request{},
+			wantFields: []string{"required"},
+		},
+		{
+			name: "成功ケース: omitemptyのポインタがnilの場合は検証しない",
+			req:  &request{Required: toPtr("value")},
+		},
+		{
+			name: "成功ケース: omitemptyのポインタが空文字の場合は検証しない",
+			req:  &request{Required: toPtr("value"), Optional: toPtr("")},
+		},
+		{
+			name:       "失敗ケース: omitemptyのポインタの参照先が不正",
+			req:        &request{Required: toPtr("value"), Optional: toPtr("invalid"), Limit: toPtr(101)},
+			wantFields: []string{"optional", "limit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(enCtx, tt.req)
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var verrs ValidationErrors
+			assert.True(t, errors.As(err, &verrs))
+			fields := make([]string, len(verrs))
+			for i, e := range verrs {
+				fields[i] = e.Field
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestValidate_NestedAndDive(t *testing.T) {
+	type item struct {
+		Name  string  `json:"name" validate:"required,max=5"`
+		Price float64 `json:"price" validate:"gt=0"`
+	}
+	type request struct {
+		Owner  item              `json:"owner"`
+		Items  []item            `json:"items" validate:"required,max=3,dive"`
+		Tags   []string          `json:"tags" validate:"dive,required,oneof=a b"`
+		Labels map[string]string `json:"labels" validate:"dive,max=3"`
+		Extra  *item             `json:"extra"`
+	}
+
+	req := &request{
+		Owner:  item{Name: "owner", Price: 1},
+		Items:  []item{{Name: "ok", Price: 10}, {Name: "too long", Price: 0}},
+		Tags:   []string{"a", "", "c"},
+		Labels: map[string]string{"k1": "ok", "k2": "toolong"},
+		Extra:  &item{Price: -1},
+	}
+
+	err := Validate(enCtx, req)
+
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	got := make([]string, len(verrs))
+	for i, e := range verrs {
+		got[i] = e.Field + ":" + e.Rule
+	}
+	assert.Equal(t, []string{
+		"items[1].name:max",
+		"items[1].price:gt",
+		"tags[1]:required",
+		"tags[2]:oneof",
+		"labels[k2]:max",
+		"extra.name:required",
+		"extra.price:gt",
+	}, got)
+}
+
+func TestValidate_NumericKinds(t *testing.T) {
+	type request struct {
+		Int64 int64   `json:"int64" validate:"gte=10"`
+		Uint  uint    `json:"uint" validate:"lt=5"`
+		Float float64 `json:"float" validate:"gt=0.5,lte=1"`
+		Max   uint8   `json:"max" validate:"max=3"`
+	}
+
+	err := Validate(enCtx, &request{Int64: 9, Uint: 5, Float: 0.5, Max: 4})
+
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Equal(t, ValidationErrors{
+		{Field: "int64", Rule: "gte", Param: "10", Message: "int64 must be greater than or equal to 10"},
+		{Field: "uint", Rule: "lt", Param: "5", Message: "uint must be less than 5"},
+		{Field: "float", Rule: "gt", Param: "0.5", Message: "float must be greater than 0.5"},
+		{Field: "max", Rule: "max", Param: "3", Message: "max must be less than or equal to 3"},
+	}, verrs)
+
+	assert.NoError(t, Validate(enCtx, &request{Int64: 10, Uint: 4, Float: 1, Max: 3}))
+}
+
+func TestRegisterValidator(t *testing.T) {
+	err := RegisterValidator("even", func(value reflect.Value, _ string) bool {
+		return value.Kind() == reflect.Int && value.Int()%2 == 0
+	})
+	assert.NoError(t, err)
+
+	type request struct {
+		Count int `json:"count" validate:"even"`
+	}
+
+	err = Validate(enCtx, &request{Count: 3})
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Equal(t, "count", verrs[0].Field)
+	assert.Equal(t, "even", verrs[0].Rule)
+	assert.NoError(t, Validate(enCtx, &request{Count: 4}))
+
+	assert.Error(t, RegisterValidator("required", func(reflect.Value, string) bool { return true }))
+}
+
+func toPtr[T any](v T) *T {
+	return &v
+}
+
+func TestValidate_Localize(t *testing.T) {
+	type request struct {
+		Title string   `json:"title" validate:"required,max=5"`
+		Email string   `json:"email" validate:"email"`
+		Tags  []string `json:"tags" validate:"max=1"`
+		Page  int      `json:"page" validate:"min=1,max=10"`
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *request
+		want []string
+	}{
+		{
+			name: "成功ケース: 日本語のメッセージ",
+			ctx:  i18n.WithLang(context.Background(), i18n.JA),
+			req:  &request{Title: "ながいタイトル", Email: "invalid", Tags: []string{"a", "b"}, Page: 11},
+			want: []string{
+				"titleは5文字以下で入力してください。",
+				"emailは正しいメールアドレスの形式で入力してください。",
+				"tagsは1件以下で指定してください。",
+				"pageは10以下で入力してください。",
+			},
+		},
+		{
+			name: "成功ケース: 英語のメッセージ",
+			ctx:  enCtx,
+			req:  &request{Email: "invalid"},
+			want: []string{
+				"title is required",
+				"email must be a valid email address",
+			},
+		},
+		{
+			name: "成功ケース: 言語が未設定の場合は日本語",
+			ctx:  context.Background(),
+			req:  &request{Email: "user@example.com"},
+			want: []string{"titleは必須です。"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.ctx, tt.req)
+			var verrs ValidationErrors
+			assert.True(t, errors.As(err, &verrs))
+			got := make([]string, len(verrs))
+			for i, e := range verrs {
+				got[i] = e.Message
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package request
+
+// SaveWebhookRequest は保存リクエストの構造体
+// Secret を省略した場合、新規作成では生成し、更新では登録済みの秘密鍵を使い続けます
+// EventTypes に指定できるのは event.Type の値です
+// URL はサーバーから送信するため、内部のアドレス (ループバック・プライベート・リンクローカル) を拒否します
+type SaveWebhookRequest struct {
+	ID         string   `json:"id" path:"id" validate:"omitempty,uuid"`
+	UserID     string   `json:"user_id" validate:"required,uuid"`
+	URL        string   `json:"url" validate:"required,max=2048,public_url"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"event_types" validate:"required,dive,oneof=summary.created summary.updated summary.deleted summary.published category.created category.updated category.deleted subcategory.created subcategory.updated subcategory.deleted"`
+	Active     *bool    `json:"active"`
+}
+
+// DisallowUnknownFields は event_types の綴りの誤りなどを見逃さないように、未知のフィールドを含むJSONを拒否します
+func (*SaveWebhookRequest) DisallowUnknownFields() {}
+
+// ListWebhookRequest はリスト取得リクエストの構造体
+type ListWebhookRequest struct {
+	UserID string `query:"user_id" validate:"omitempty,uuid"`
+}
+
+// DetailWebhookRequest は詳細取得リクエストの構造体
+type DetailWebhookRequest struct {
+	ID string `path:"id" validate:"required,uuid"`
+}
+
+// DeleteWebhookRequest は削除リクエストの構造体
+type DeleteWebhookRequest struct {
+	ID string `path:"id" validate:"required,uuid"`
+}
+
+// ListWebhookDeliveryRequest は配信履歴の取得リクエストの構造体
+type ListWebhookDeliveryRequest struct {
+	ID     string `path:"id" validate:"required,uuid"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Offset int    `query:"offset" validate:"min=0"`
+}
+
+// RedeliverWebhookRequest は再送リクエストの構造体
+type RedeliverWebhookRequest struct {
+	ID         string `path:"id" validate:"required,uuid"`
+	DeliveryID string `path:"delivery_id" validate:"required,uuid"`
+}
@@ -17,6 +17,9 @@ type CategoryListResponse struct {
 	Categories []CategoryResponse `json:"categories"`
 }
 
+// ListItems は NDJSON と CSV で1件ずつ返すカテゴリです
+func (l CategoryListResponse) ListItems() any { return l.Categories }
+
 func ToCategoryResponse(catRes *category.Category) *CategoryResponse {
 	return &CategoryResponse{
 		ID:        catRes.ID,
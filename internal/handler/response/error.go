@@ -1,6 +1,115 @@
 package response
 
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/o-ga09/web-ya-hime/internal/handler/request"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// Problem はRFC 7807のエラーレスポンス構造体
+// type は about:blank 固定で、エラーの種類は code で判別します
+type Problem struct {
+	Type      string                   `json:"type"`
+	Title     string                   `json:"title"`
+	Status    int                      `json:"status"`
+	Detail    string                   `json:"detail,omitempty"`
+	Instance  string                   `json:"instance,omitempty"`
+	Code      Errors.ErrCode           `json:"code"`
+	RequestID string                   `json:"request_id,omitempty"`
+	Errors    request.ValidationErrors `json:"errors,omitempty"`
+}
+
+// StatusClientClosedRequest はクライアントがレスポンスを待たずに切断した場合のステータスです
+// 標準のステータスにないため、nginx と同じ 499 を使います
+const StatusClientClosedRequest = 499
+
+// statusOf はエラーコードとHTTPステータスの対応
+var statusOf = map[Errors.ErrCode]int{
+	Errors.ErrCodeInValidArgument:  http.StatusBadRequest,
+	Errors.ErrCodeBussiness:        http.StatusBadRequest,
+	Errors.ErrCodeUnAuthorized:     http.StatusUnauthorized,
+	Errors.ErrCodeUnAuthorization:  http.StatusForbidden,
+	Errors.ErrCodeNotFound:         http.StatusNotFound,
+	Errors.ErrCodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	Errors.ErrCodeRequestTooLarge:  http.StatusRequestEntityTooLarge,
+	Errors.ErrCodeUnsupportedMedia: http.StatusUnsupportedMediaType,
+	Errors.ErrCodeConflict:         http.StatusConflict,
+	Errors.ErrCodeTooManyRequests:  http.StatusTooManyRequests,
+	Errors.ErrCodeCritical:         http.StatusInternalServerError,
+	Errors.ErrCodeTimeout:          http.StatusServiceUnavailable,
+	Errors.ErrCodeGatewayTimeout:   http.StatusGatewayTimeout,
+	Errors.ErrCodeClientClosed:     StatusClientClosedRequest,
+}
+
+// StatusOf はエラーに対応するHTTPステータスを返します
+func StatusOf(err error) int {
+	if status, ok := statusOf[Errors.GetCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error はエラーをapplication/problem+jsonで返します
+// ラップされていないエラーは Errors.Wrap でエラーコードとメッセージを決めます
+// 5xxはエラーログ、4xxは警告ログをコールスタック付きで出力します
+func Error(ctx context.Context, w http.ResponseWriter, err error) {
+	writeProblem(ctx, w, Errors.Wrap(ctx, err), nil)
+}
+
+// ValidationError はバリデーションエラーを400で返します
+// request.ValidationErrors の場合は違反したすべてのフィールドを errors で返します
+func ValidationError(ctx context.Context, w http.ResponseWriter, err error) {
+	wrapped := Errors.Wrap(ctx, Errors.ErrInvalidArgument)
+	var verrs request.ValidationErrors
+	if errors.As(err, &verrs) {
+		writeProblem(ctx, w, wrapped, verrs)
+		return
+	}
+	writeProblem(ctx, w, wrapped, nil)
+}
+
+// BindError はリクエストのバインドに失敗した場合に400を返します
+// ボディがサイズの上限を超えた場合は413、Content-Type が対応していない場合は415を返します
+func BindError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, Errors.ErrRequestEntityTooLarge) || errors.Is(err, Errors.ErrUnsupportedMediaType) {
+		Error(ctx, w, err)
+		return
+	}
+	wrapped := Errors.WrapWithMessage(ctx, errors.Join(Errors.ErrRequestNotNil, err), i18n.T(ctx, i18n.MsgInvalidRequest, err))
+	writeProblem(ctx, w, wrapped, nil)
+}
+
+// statusText は http.StatusText に StatusClientClosedRequest を加えたものです
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
+
+func writeProblem(ctx context.Context, w http.ResponseWriter, err error, verrs request.ValidationErrors) {
+	status := StatusOf(err)
+	if status >= http.StatusInternalServerError {
+		logger.Error(ctx, err.Error(), "code", Errors.GetCode(err), "callStack", Errors.GetCallstack(err))
+	} else {
+		logger.Warn(ctx, err.Error(), "code", Errors.GetCode(err), "callStack", Errors.GetCallstack(err))
+	}
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     statusText(status),
+		Status:    status,
+		Detail:    Errors.GetMessage(err),
+		Code:      Errors.GetCode(err),
+		RequestID: Ctx.GetRequestID(ctx),
+		Errors:    verrs,
+	}
+	httputil.ProblemResponse(&w, status, problem)
 }
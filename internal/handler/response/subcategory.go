@@ -19,6 +19,9 @@ type SubcategoryListResponse struct {
 	Subcategories []SubcategoryResponse `json:"subcategories"`
 }
 
+// ListItems は NDJSON と CSV で1件ずつ返すサブカテゴリです
+func (l SubcategoryListResponse) ListItems() any { return l.Subcategories }
+
 func ToSubCategoryResponse(subcatRes *subcategory.Subcategory) *SubcategoryResponse {
 	res := &SubcategoryResponse{
 		ID:         subcatRes.ID,
@@ -14,6 +14,12 @@ type ListSummary struct {
 	HasNext   bool             `json:"has_next"`
 }
 
+// ListItems は NDJSON と CSV で1件ずつ返すサマリーです
+func (l ListSummary) ListItems() any { return l.Summaries }
+
+// TotalCount は NDJSON と CSV で X-Total-Count ヘッダーに返す全件数です
+func (l ListSummary) TotalCount() int { return l.Total }
+
 // DetailSummary はサマリーの詳細構造体
 type DetailSummary struct {
 	ID          string               `json:"id"`
@@ -23,6 +29,7 @@ type DetailSummary struct {
 	Content     string               `json:"content"`
 	CreatedAt   string               `json:"created_at"`
 	UpdatedAt   string               `json:"updated_at"`
+	PublishedAt *string              `json:"published_at"`
 	Category    *CategoryResponse    `json:"category,omitempty"`
 	SubCategory *SubcategoryResponse `json:"subcategory,omitempty"`
 }
@@ -45,6 +52,10 @@ func ToSummaryResponse(s *SummaryDomain.Summary) *DetailSummary {
 		UpdatedAt:   date.FormatDefault(s.UpdatedAt),
 	}
 
+	if s.PublishedAt.Valid {
+		publishedAt := date.FormatDefault(s.PublishedAt.Time)
+		res.PublishedAt = &publishedAt
+	}
 	if s.User != nil {
 		res.User = ToUserResponse(s.User)
 	}
@@ -3,10 +3,19 @@ package response
 import UserDomain "github.com/o-ga09/web-ya-hime/internal/domain/user"
 
 type ListUser struct {
-	User  []*user `json:"users"`
-	Total int     `json:"total"`
+	User    []*user `json:"users"`
+	Total   int     `json:"total"`
+	Page    int     `json:"page"`
+	Limit   int     `json:"limit"`
+	HasNext bool    `json:"has_next"`
 }
 
+// ListItems は NDJSON と CSV で1件ずつ返すユーザーです
+func (l ListUser) ListItems() any { return l.User }
+
+// TotalCount は NDJSON と CSV で X-Total-Count ヘッダーに返す全件数です
+func (l ListUser) TotalCount() int { return l.Total }
+
 type DetailUser struct {
 	User *user `json:"user"`
 }
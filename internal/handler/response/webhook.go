@@ -0,0 +1,105 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+)
+
+// WebhookResponse はWebhookのレスポンス構造体
+// 秘密鍵は登録時に生成した場合だけ保存のレスポンスで返し、ここには含めません
+type WebhookResponse struct {
+	ID         string       `json:"id"`
+	UserID     string       `json:"user_id"`
+	URL        string       `json:"url"`
+	EventTypes []event.Type `json:"event_types"`
+	Active     bool         `json:"active"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+type WebhookListResponse struct {
+	Webhooks []*WebhookResponse `json:"webhooks"`
+}
+
+// ListItems は NDJSON と CSV で1件ずつ返すWebhookです
+func (l WebhookListResponse) ListItems() any { return l.Webhooks }
+
+// WebhookDeliveryResponse は配信履歴のレスポンス構造体
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      event.Type      `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	RedeliveryOf   string          `json:"redelivery_of,omitempty"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`
+	Total      int                        `json:"total"`
+	Limit      int                        `json:"limit"`
+	Offset     int                        `json:"offset"`
+	HasNext    bool                       `json:"has_next"`
+}
+
+// ListItems は NDJSON と CSV で1件ずつ返す配信です
+func (l WebhookDeliveryListResponse) ListItems() any { return l.Deliveries }
+
+// TotalCount は NDJSON と CSV で X-Total-Count ヘッダーに返す全件数です
+func (l WebhookDeliveryListResponse) TotalCount() int { return l.Total }
+
+func ToWebhookResponse(w *webhook.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:         w.ID,
+		UserID:     w.UserID,
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		CreatedAt:  w.CreatedAt,
+		UpdatedAt:  w.UpdatedAt,
+	}
+}
+
+func ToWebhookListResponse(webhooks []*webhook.Webhook) []*WebhookResponse {
+	res := make([]*WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		res[i] = ToWebhookResponse(w)
+	}
+	return res
+}
+
+func ToWebhookDeliveryResponse(d *webhook.Delivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		RedeliveryOf:   d.RedeliveryOf,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+func ToWebhookDeliveryListResponse(deliveries []*webhook.Delivery) []*WebhookDeliveryResponse {
+	res := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		res[i] = ToWebhookDeliveryResponse(d)
+	}
+	return res
+}
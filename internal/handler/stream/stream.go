@@ -0,0 +1,152 @@
+package stream
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/handler/request"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	"github.com/o-ga09/web-ya-hime/internal/infra/sse"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// EventReset は再送できない Last-Event-ID で接続された場合に最初に送るイベントです
+// 受け取ったクライアントは表示しているデータを取得し直してください
+const EventReset = "reset"
+
+// retryInterval は切断されたクライアントが再接続するまでの時間 (ミリ秒) です
+const retryInterval = 3000
+
+type IStreamHandler interface {
+	Summaries(w http.ResponseWriter, r *http.Request)
+}
+
+type streamHandler struct {
+	broker    *sse.Broker
+	heartbeat time.Duration
+}
+
+func New(broker *sse.Broker, heartbeat time.Duration) IStreamHandler {
+	return &streamHandler{
+		broker:    broker,
+		heartbeat: heartbeat,
+	}
+}
+
+// Summaries はサマリーの作成・更新・削除のイベントを Server-Sent Events で送信します
+// category_id と summary_id でイベントを絞り込めます
+// Last-Event-ID ヘッダー (または last_event_id) を指定すると、そのイベントより後のイベントから送信します
+// クライアントが切断するかサーバーが停止するまでレスポンスを返し続けるため、タイムアウトのミドルウェアを使わずに登録します
+func (h *streamHandler) Summaries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.SummaryStreamRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = req.LastEventID
+	}
+
+	sub, replay, ok := h.broker.Subscribe(lastEventID, summaryFilter(req.CategoryID, req.SummaryID))
+	defer h.broker.Unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	// サーバーの WriteTimeout でストリームが切断されないようにする
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// リバースプロキシでバッファリングさせない
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryInterval)
+	if !ok {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", EventReset)
+	}
+	for _, e := range replay {
+		if err := writeEvent(w, e); err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to write event: %v", err))
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Error(ctx, fmt.Sprintf("streaming is not supported: %v", err))
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, open := <-sub.C:
+			if !open {
+				// シャットダウンか処理の遅れで切断された。クライアントは Last-Event-ID で再接続する
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			// 接続を維持するためのコメント行
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// summaryFilter はサマリーのイベントのうち、指定したカテゴリとサマリーのものを選びます
+// 更新でカテゴリが変わった場合は、変更後のカテゴリで判定します
+func summaryFilter(categoryID, summaryID string) sse.Filter {
+	return func(e *event.Event) bool {
+		if summaryID != "" && e.AggregateID != summaryID {
+			return false
+		}
+		if categoryID == "" {
+			return true
+		}
+		var payload struct {
+			CategoryID sql.NullString `json:"category_id"`
+		}
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return false
+		}
+		return payload.CategoryID.Valid && payload.CategoryID.String == categoryID
+	}
+}
+
+// writeEvent はイベントを1件書き込みます。id に Last-Event-ID で使うイベントのIDを、event にイベントの種類を設定します
+func writeEvent(w io.Writer, e *event.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/infra/sse"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	summaryID1  = "11111111-1111-1111-1111-111111111111"
+	summaryID2  = "22222222-2222-2222-2222-222222222222"
+	categoryID1 = "33333333-3333-3333-3333-333333333333"
+)
+
+func newSummaryEvent(id string, typ event.Type, summaryID, categoryID string) *event.Event {
+	payload, _ := json.Marshal(map[string]any{
+		"id":          summaryID,
+		"category_id": map[string]any{"String": categoryID, "Valid": categoryID != ""},
+	})
+	return &event.Event{
+		ID:          id,
+		Type:        typ,
+		AggregateID: summaryID,
+		Payload:     payload,
+		OccurredAt:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// eventIDs はレスポンスのボディから id 行の値を取り出します
+func eventIDs(body string) []string {
+	var ids []string
+	for _, line := range strings.Split(body, "\n") {
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestStreamHandler_Summaries(t *testing.T) {
+	published := []*event.Event{
+		newSummaryEvent("e1", event.TypeSummaryCreated, summaryID1, categoryID1),
+		newSummaryEvent("e2", event.TypeSummaryUpdated, summaryID1, categoryID1),
+		newSummaryEvent("e3", event.TypeSummaryCreated, summaryID2, ""),
+		newSummaryEvent("e4", event.TypeSummaryDeleted, summaryID1, categoryID1),
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		query       string
+		lastEventID string
+		wantStatus  int
+		wantIDs     []string
+		wantReset   bool
+	}{
+		{
+			name:        "成功ケース: Last-Event-ID より後のイベントを送信する",
+			method:      http.MethodGet,
+			lastEventID: "e1",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"e2", "e3", "e4"},
+		},
+		{
+			name:       "成功ケース: last_event_id でも途中から購読できる",
+			method:     http.MethodGet,
+			query:      "?last_event_id=e2",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"e3", "e4"},
+		},
+		{
+			name:        "成功ケース: summary_id で絞り込む",
+			method:      http.MethodGet,
+			query:       "?summary_id=" + summaryID2,
+			lastEventID: "e1",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"e3"},
+		},
+		{
+			name:        "成功ケース: category_id で絞り込む",
+			method:      http.MethodGet,
+			query:       "?category_id=" + categoryID1,
+			lastEventID: "e1",
+			wantStatus:  http.StatusOK,
+			wantIDs:     []string{"e2", "e4"},
+		},
+		{
+			name:       "成功ケース: Last-Event-ID がない場合は保持しているイベントを送信しない",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:        "成功ケース: 保持していない Last-Event-ID の場合は reset を送信する",
+			method:      http.MethodGet,
+			lastEventID: "unknown",
+			wantStatus:  http.StatusOK,
+			wantReset:   true,
+		},
+		{
+			name:       "失敗ケース: category_id がUUIDではない",
+			method:     http.MethodGet,
+			query:      "?category_id=invalid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "失敗ケース: GET以外のメソッド",
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := sse.NewBroker(10)
+			for _, e := range published {
+				assert.NoError(t, broker.Publish(context.Background(), e))
+			}
+			h := New(broker, time.Minute)
+
+			// 再送した後にすぐ終了させるため、切断済みのリクエストにする
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest(tt.method, "/events/summaries"+tt.query, nil).WithContext(ctx)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			h.Summaries(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			body := w.Body.String()
+			assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+			assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+			assert.Equal(t, tt.wantIDs, eventIDs(body))
+			assert.Equal(t, tt.wantReset, strings.Contains(body, "event: reset\n"))
+		})
+	}
+}
+
+func TestStreamHandler_Summaries_Live(t *testing.T) {
+	broker := sse.NewBroker(10)
+	h := New(broker, time.Minute)
+
+	srv := httptest.NewServer(http.HandlerFunc(h.Summaries))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?summary_id=" + summaryID1)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	// ヘッダーを受け取った時点で購読は始まっている
+	assert.NoError(t, broker.Publish(context.Background(), newSummaryEvent("e1", event.TypeSummaryCreated, summaryID2, "")))
+	assert.NoError(t, broker.Publish(context.Background(), newSummaryEvent("e2", event.TypeSummaryUpdated, summaryID1, "")))
+	broker.Close()
+
+	// Close でストリームが終わるまで読む
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"e2"}, eventIDs(string(body)))
+	assert.Contains(t, string(body), "event: summary.updated\n")
+	assert.Contains(t, string(body), `"aggregate_id":"`+summaryID1+`"`)
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
 	"github.com/o-ga09/web-ya-hime/internal/handler/request"
 	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/httputil"
-	"github.com/o-ga09/web-ya-hime/pkg/logger"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/uuid"
 )
 
@@ -31,7 +33,7 @@ func New(repo subcategory.ISubcategoryRepository) ISubcategoryHandler {
 
 func (h *subcategoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -39,16 +41,16 @@ func (h *subcategoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SubcategorySaveRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
 	if r.Method == http.MethodPut && req.ID == "" {
-		http.Error(w, "Subcategory ID is required for update", http.StatusBadRequest)
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgIDRequiredForUpdate, i18n.T(ctx, i18n.ResSubcategory))))
 		return
 	}
 
@@ -67,8 +69,7 @@ func (h *subcategoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Save(ctx, model); err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to save subcategory", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -79,7 +80,7 @@ func (h *subcategoryHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 func (h *subcategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -87,14 +88,17 @@ func (h *subcategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SubcategoryListRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
 	subcategories, err := h.repo.List(ctx, req.CategoryID)
 	if err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to get subcategory list", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -121,12 +125,12 @@ func (h *subcategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 	res := response.SubcategoryListResponse{
 		Subcategories: subcategoryResponses,
 	}
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (h *subcategoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -134,11 +138,11 @@ func (h *subcategoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SubcategoryDetailRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -150,8 +154,7 @@ func (h *subcategoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 
 	subcat, err := h.repo.Detail(ctx, model)
 	if err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to get subcategory detail", http.StatusNotFound)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -170,12 +173,12 @@ func (h *subcategoryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 			UpdatedAt: subcat.Category.UpdatedAt,
 		}
 	}
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (h *subcategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -183,11 +186,11 @@ func (h *subcategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SubcategoryDeleteRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -198,12 +201,11 @@ func (h *subcategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Delete(ctx, model); err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to delete subcategory", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
 	httputil.Response(&w, http.StatusOK, map[string]string{
-		"message": "Subcategory deleted successfully",
+		"message": i18n.T(ctx, i18n.MsgDeleted, i18n.T(ctx, i18n.ResSubcategory)),
 	})
 }
@@ -1,6 +1,8 @@
 package summary
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/o-ga09/web-ya-hime/internal/domain"
@@ -8,8 +10,10 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
 	"github.com/o-ga09/web-ya-hime/internal/handler/request"
 	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/httputil"
-	"github.com/o-ga09/web-ya-hime/pkg/logger"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/ptr"
 )
 
@@ -18,24 +22,27 @@ type ISummaryHandler interface {
 	List(w http.ResponseWriter, r *http.Request)
 	Detail(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	Publish(w http.ResponseWriter, r *http.Request)
 }
 
 type summaryHandler struct {
 	repo       summary.ISummaryRepository
 	subcatRepo subcategory.ISubcategoryRepository
+	txm        domain.ITxManager
 }
 
-func New(repo summary.ISummaryRepository, subcatRepo subcategory.ISubcategoryRepository) ISummaryHandler {
+func New(repo summary.ISummaryRepository, subcatRepo subcategory.ISubcategoryRepository, txm domain.ITxManager) ISummaryHandler {
 	return &summaryHandler{
 		repo:       repo,
 		subcatRepo: subcatRepo,
+		txm:        txm,
 	}
 }
 
 func (s *summaryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -43,39 +50,42 @@ func (s *summaryHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SaveSummaryRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
-	// カテゴリとサブカテゴリの組み合わせチェック
-	if req.CategoryID != nil && req.SubcategoryID != nil {
-		subcatModel := &subcategory.Subcategory{
-			WYHBaseModel: domain.WYHBaseModel{
-				ID: *req.SubcategoryID,
-			},
-		}
-		subcat, err := s.subcatRepo.Detail(ctx, subcatModel)
-		if err != nil {
-			http.Error(w, "Invalid subcategory", http.StatusBadRequest)
-			return
-		}
-		if subcat.CategoryID != *req.CategoryID {
-			http.Error(w, "Subcategory does not belong to the specified category", http.StatusBadRequest)
-			return
-		}
-	}
-
 	// ドメインモデルに変換
 	model := req.ToModel()
 
-	// リポジトリに保存
-	if err := s.repo.Save(ctx, model); err != nil {
-		logger.Error(ctx, err.Error())
-		http.Error(w, "Failed to save summary", http.StatusInternalServerError)
+	// サブカテゴリのチェックと保存を同じトランザクションで行い、まとめてコミットする
+	// チェックではサブカテゴリをロックして読み取るため、コミットまで別のリクエストはサブカテゴリを削除・変更できず、
+	// 保存したサマリーのカテゴリとサブカテゴリの組み合わせは常にチェックした時点のものと一致する
+	err := s.txm.WithinTx(ctx, func(ctx context.Context) error {
+		// カテゴリとサブカテゴリの組み合わせチェック
+		if req.CategoryID != nil && req.SubcategoryID != nil {
+			subcatModel := &subcategory.Subcategory{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: *req.SubcategoryID,
+				},
+			}
+			subcat, err := s.subcatRepo.DetailForShare(ctx, subcatModel)
+			if err != nil {
+				return s.invalidSubcategoryError(ctx, err)
+			}
+			if subcat.CategoryID != *req.CategoryID {
+				return Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgSubcategoryCategoryMismatch))
+			}
+		}
+
+		// リポジトリに保存
+		return s.repo.Save(ctx, model)
+	})
+	if err != nil {
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -85,10 +95,19 @@ func (s *summaryHandler) Save(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// invalidSubcategoryError は指定したサブカテゴリが存在しない場合に400のエラーを返します
+// それ以外のエラーはそのまま返します
+func (s *summaryHandler) invalidSubcategoryError(ctx context.Context, err error) error {
+	if !Errors.Is(err, Errors.ErrRecordNotFound) {
+		return err
+	}
+	return Errors.WrapWithMessage(ctx, fmt.Errorf("%w: %v", Errors.ErrInvalidArgument, err), i18n.T(ctx, i18n.MsgInvalidSubcategory))
+}
+
 func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -96,7 +115,7 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	var req request.ListSummaryRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
 
@@ -107,8 +126,8 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 	if req.Offset < 0 {
 		req.Offset = 0
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -126,7 +145,7 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 		subcat, err := s.subcatRepo.Detail(ctx, subcatModel)
 		if err != nil {
-			http.Error(w, "Invalid subcategory", http.StatusBadRequest)
+			response.Error(ctx, w, s.invalidSubcategoryError(ctx, err))
 			return
 		}
 		categoryID = subcat.CategoryID
@@ -141,11 +160,11 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 		subcat, err := s.subcatRepo.Detail(ctx, subcatModel)
 		if err != nil {
-			http.Error(w, "Invalid subcategory", http.StatusBadRequest)
+			response.Error(ctx, w, s.invalidSubcategoryError(ctx, err))
 			return
 		}
 		if subcat.CategoryID != categoryID {
-			http.Error(w, "Subcategory does not belong to the specified category", http.StatusBadRequest)
+			response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgSubcategoryCategoryMismatch)))
 			return
 		}
 	}
@@ -160,8 +179,7 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	result, err := s.repo.List(ctx, opts)
 	if err != nil {
-		logger.Error(ctx, "error", err)
-		http.Error(w, "Failed to get summary list", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -173,13 +191,13 @@ func (s *summaryHandler) List(w http.ResponseWriter, r *http.Request) {
 		Offset:    result.Offset,
 		HasNext:   result.HasNext,
 	}
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (s *summaryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -188,11 +206,11 @@ func (s *summaryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	var req request.DetailSummaryRequest
 	// リクエスト構造体を作成してバリデーション
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -206,18 +224,18 @@ func (s *summaryHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	// リポジトリから詳細を取得
 	detail, err := s.repo.Detail(ctx, model)
 	if err != nil {
-		http.Error(w, "Failed to get summary detail", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
 	res := response.ToSummaryResponse(detail)
-	httputil.Response(&w, http.StatusOK, res)
+	httputil.CachedResponse(&w, r, Ctx.GetCtxCfg(ctx).HTTPCacheMaxAge, res)
 }
 
 func (s *summaryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -225,11 +243,11 @@ func (s *summaryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	var req request.DeleteSummaryRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -240,11 +258,55 @@ func (s *summaryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.repo.Delete(ctx, model); err != nil {
-		http.Error(w, "Failed to delete summary", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
 	httputil.Response(&w, http.StatusNoContent, map[string]string{
-		"message": "Summary deleted successfully",
+		"message": i18n.T(ctx, i18n.MsgDeleted, i18n.T(ctx, i18n.ResSummary)),
 	})
 }
+
+// Publish は説明を確定したサマリーを公開し、公開後のサマリーを返します
+// 公開すると summary.published のイベントが Webhook などに配信されます。公開済みの場合は409を返します
+func (s *summaryHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	// メソッドチェック
+	if r.Method != http.MethodPost {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.PublishSummaryRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	model := &summary.Summary{
+		WYHBaseModel: domain.WYHBaseModel{
+			ID: req.ID,
+		},
+	}
+
+	if err := s.repo.Publish(ctx, model); err != nil {
+		if Errors.Is(err, Errors.ErrConflict) {
+			err = Errors.WrapWithMessage(ctx, err, i18n.T(ctx, i18n.MsgSummaryAlreadyPublished))
+		}
+		response.Error(ctx, w, err)
+		return
+	}
+
+	detail, err := s.repo.Detail(ctx, model)
+	if err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.Response(&w, http.StatusOK, response.ToSummaryResponse(detail))
+}
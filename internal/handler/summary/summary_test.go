@@ -3,8 +3,10 @@ package summary
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,10 +16,20 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
 	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// fakeTxManager はdomain.ITxManagerのフェイク
+// トランザクションを開始せずに fn をそのまま実行します
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, _ ...domain.TxOption) error {
+	return fn(ctx)
+}
+
 // MockSummaryRepository はsummary.ISummaryRepositoryのモック
 type MockSummaryRepository struct {
 	mock.Mock
@@ -49,6 +61,11 @@ func (m *MockSummaryRepository) Delete(ctx context.Context, model *summary.Summa
 	return args.Error(0)
 }
 
+func (m *MockSummaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
 // MockSubcategoryRepository はsubcategory.ISubcategoryRepositoryのモック
 type MockSubcategoryRepository struct {
 	mock.Mock
@@ -75,17 +92,29 @@ func (m *MockSubcategoryRepository) Detail(ctx context.Context, model *subcatego
 	return args.Get(0).(*subcategory.Subcategory), args.Error(1)
 }
 
+func (m *MockSubcategoryRepository) DetailForShare(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*subcategory.Subcategory), args.Error(1)
+}
+
 func (m *MockSubcategoryRepository) Delete(ctx context.Context, model *subcategory.Subcategory) error {
 	args := m.Called(ctx, model)
 	return args.Error(0)
 }
 
 func TestSummaryHandler_Save(t *testing.T) {
+	categoryID := "770e8400-e29b-41d4-a716-446655440000"
+	subcategoryID := "880e8400-e29b-41d4-a716-446655440000"
+
 	tests := []struct {
 		name           string
 		method         string
 		body           map[string]interface{}
 		mockSetup      func(*MockSummaryRepository)
+		subcatSetup    func(*MockSubcategoryRepository)
 		expectedStatus int
 		checkResponse  func(t *testing.T, body string)
 	}{
@@ -110,6 +139,29 @@ func TestSummaryHandler_Save(t *testing.T) {
 				assert.NotEmpty(t, res["summary_id"])
 			},
 		},
+		{
+			name:   "成功ケース: サブカテゴリをロックして読み取ってから保存する",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"title":          "Test Title",
+				"content":        "Test Content",
+				"category_id":    categoryID,
+				"subcategory_id": subcategoryID,
+				"user_id":        "user-123",
+			},
+			mockSetup: func(m *MockSummaryRepository) {
+				m.On("Save", mock.Anything, mock.AnythingOfType("*summary.Summary")).Return(nil)
+			},
+			subcatSetup: func(m *MockSubcategoryRepository) {
+				m.On("DetailForShare", mock.Anything, mock.MatchedBy(func(s *subcategory.Subcategory) bool {
+					return s.ID == subcategoryID
+				})).Return(&subcategory.Subcategory{
+					WYHBaseModel: domain.WYHBaseModel{ID: subcategoryID},
+					CategoryID:   categoryID,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:           "失敗ケース: メソッドが不正",
 			method:         http.MethodGet,
@@ -117,6 +169,42 @@ func TestSummaryHandler_Save(t *testing.T) {
 			mockSetup:      func(m *MockSummaryRepository) {},
 			expectedStatus: http.StatusMethodNotAllowed,
 		},
+		{
+			name:   "失敗ケース: サブカテゴリが別のカテゴリに属している",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"title":          "Test Title",
+				"content":        "Test Content",
+				"category_id":    categoryID,
+				"subcategory_id": subcategoryID,
+				"user_id":        "user-123",
+			},
+			mockSetup: func(m *MockSummaryRepository) {},
+			subcatSetup: func(m *MockSubcategoryRepository) {
+				m.On("DetailForShare", mock.Anything, mock.Anything).Return(&subcategory.Subcategory{
+					WYHBaseModel: domain.WYHBaseModel{ID: subcategoryID},
+					CategoryID:   "770e8400-e29b-41d4-a716-446655440001",
+				}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "失敗ケース: 存在しないサブカテゴリを指定",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"title":          "Test Title",
+				"content":        "Test Content",
+				"category_id":    categoryID,
+				"subcategory_id": subcategoryID,
+				"user_id":        "user-123",
+			},
+			mockSetup: func(m *MockSummaryRepository) {},
+			subcatSetup: func(m *MockSubcategoryRepository) {
+				m.On("DetailForShare", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:   "失敗ケース: リクエストボディが不正",
 			method: http.MethodPost,
@@ -136,6 +224,48 @@ func TestSummaryHandler_Save(t *testing.T) {
 			mockSetup:      func(m *MockSummaryRepository) {},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:   "失敗ケース: 違反したすべてのフィールドがJSONで返される",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"description": "Test Description",
+				"user_id":     "user-123",
+			},
+			mockSetup:      func(m *MockSummaryRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body string) {
+				var res struct {
+					Errors []map[string]string `json:"errors"`
+				}
+				err := json.Unmarshal([]byte(body), &res)
+				assert.NoError(t, err)
+				assert.Len(t, res.Errors, 2)
+				assert.Equal(t, "title", res.Errors[0]["field"])
+				assert.Equal(t, "required", res.Errors[0]["rule"])
+				assert.Equal(t, "content", res.Errors[1]["field"])
+				assert.Equal(t, "required", res.Errors[1]["rule"])
+			},
+		},
+		{
+			name:   "失敗ケース: 存在しないカテゴリIDを指定",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"title":       "Test Title",
+				"content":     "Test Content",
+				"category_id": "770e8400-e29b-41d4-a716-446655440099",
+				"user_id":     "user-123",
+			},
+			mockSetup: func(m *MockSummaryRepository) {
+				m.On("Save", mock.Anything, mock.AnythingOfType("*summary.Summary")).
+					Return(fmt.Errorf("failed to save summary: %w", Errors.ErrForeignKeyConstraint))
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(body), &res))
+				assert.Equal(t, "invalid_argument", res["code"])
+			},
+		},
 		{
 			name:   "失敗ケース: リポジトリでエラー",
 			method: http.MethodPost,
@@ -158,8 +288,11 @@ func TestSummaryHandler_Save(t *testing.T) {
 			mockRepo := new(MockSummaryRepository)
 			mockSubcatRepo := new(MockSubcategoryRepository)
 			tt.mockSetup(mockRepo)
+			if tt.subcatSetup != nil {
+				tt.subcatSetup(mockSubcatRepo)
+			}
 
-			handler := New(mockRepo, mockSubcatRepo)
+			handler := New(mockRepo, mockSubcatRepo, fakeTxManager{})
 
 			bodyBytes, _ := json.Marshal(tt.body)
 			req := httptest.NewRequest(tt.method, "/summaries", bytes.NewBuffer(bodyBytes))
@@ -175,6 +308,7 @@ func TestSummaryHandler_Save(t *testing.T) {
 			}
 
 			mockRepo.AssertExpectations(t)
+			mockSubcatRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -278,7 +412,7 @@ func TestSummaryHandler_List(t *testing.T) {
 			mockSubcatRepo := new(MockSubcategoryRepository)
 			tt.mockSetup(mockRepo)
 
-			handler := New(mockRepo, mockSubcatRepo)
+			handler := New(mockRepo, mockSubcatRepo, fakeTxManager{})
 
 			req := httptest.NewRequest(tt.method, "/summaries", nil)
 			w := httptest.NewRecorder()
@@ -303,6 +437,7 @@ func TestSummaryHandler_Detail(t *testing.T) {
 		name           string
 		method         string
 		summaryID      string
+		revalidate     bool
 		mockSetup      func(*MockSummaryRepository)
 		expectedStatus int
 		checkResponse  func(t *testing.T, body string)
@@ -346,6 +481,42 @@ func TestSummaryHandler_Detail(t *testing.T) {
 				assert.Equal(t, "Content 1", res["content"])
 			},
 		},
+		{
+			name:       "成功ケース: ETagが一致する場合は304を返す",
+			method:     http.MethodGet,
+			summaryID:  "summary-1",
+			revalidate: true,
+			mockSetup: func(m *MockSummaryRepository) {
+				summaryData := &summary.Summary{
+					WYHBaseModel: domain.WYHBaseModel{
+						ID:        "summary-1",
+						CreatedAt: now,
+						UpdatedAt: now,
+					},
+					Title:       "Title 1",
+					Description: "Description 1",
+					Content:     "Content 1",
+					UserID:      "user-1",
+					User: &user.User{
+						WYHBaseModel: domain.WYHBaseModel{
+							ID:        "user-1",
+							CreatedAt: now,
+							UpdatedAt: now,
+						},
+						Name:     "User Name",
+						Email:    "user@example.com",
+						UserType: "admin",
+					},
+				}
+				m.On("Detail", mock.Anything, mock.MatchedBy(func(s *summary.Summary) bool {
+					return s.ID == "summary-1"
+				})).Return(summaryData, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+			checkResponse: func(t *testing.T, body string) {
+				assert.Empty(t, body)
+			},
+		},
 		{
 			name:           "失敗ケース: メソッドが不正",
 			method:         http.MethodPost,
@@ -379,7 +550,7 @@ func TestSummaryHandler_Detail(t *testing.T) {
 			mockSubcatRepo := new(MockSubcategoryRepository)
 			tt.mockSetup(mockRepo)
 
-			handler := New(mockRepo, mockSubcatRepo)
+			handler := New(mockRepo, mockSubcatRepo, fakeTxManager{})
 
 			// パスパラメータをシミュレートするために、Go 1.22の新しいルーティングを使用
 			url := "/summaries/{id}"
@@ -388,6 +559,14 @@ func TestSummaryHandler_Detail(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			// 1回目のレスポンスのETagを付けて再検証する
+			if tt.revalidate {
+				first := httptest.NewRecorder()
+				handler.Detail(first, req)
+				assert.Equal(t, "no-cache", first.Header().Get("Cache-Control"))
+				req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+			}
+
 			handler.Detail(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
@@ -453,7 +632,7 @@ func TestSummaryHandler_Delete(t *testing.T) {
 			mockSubcatRepo := new(MockSubcategoryRepository)
 			tt.mockSetup(mockRepo)
 
-			handler := New(mockRepo, mockSubcatRepo)
+			handler := New(mockRepo, mockSubcatRepo, fakeTxManager{})
 
 			// パスパラメータをシミュレートするために、Go 1.22の新しいルーティングを使用
 			url := "/summaries/{id}"
@@ -470,3 +649,90 @@ func TestSummaryHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestSummaryHandler_Publish(t *testing.T) {
+	publishedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		method          string
+		summaryID       string
+		mockSetup       func(*MockSummaryRepository)
+		expectedStatus  int
+		wantPublishedAt string
+	}{
+		{
+			name:      "成功ケース: サマリーを公開して公開日時を返す",
+			method:    http.MethodPost,
+			summaryID: "summary-1",
+			mockSetup: func(m *MockSummaryRepository) {
+				m.On("Publish", mock.Anything, mock.MatchedBy(func(s *summary.Summary) bool {
+					return s.ID == "summary-1"
+				})).Return(nil)
+				m.On("Detail", mock.Anything, mock.Anything).Return(&summary.Summary{
+					WYHBaseModel: domain.WYHBaseModel{ID: "summary-1"},
+					Title:        "Test Title",
+					PublishedAt:  sql.NullTime{Time: publishedAt, Valid: true},
+				}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			wantPublishedAt: "2026-10-19 12:00:00",
+		},
+		{
+			name:           "失敗ケース: メソッドが不正",
+			method:         http.MethodGet,
+			summaryID:      "summary-1",
+			mockSetup:      func(m *MockSummaryRepository) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:      "失敗ケース: 公開済み",
+			method:    http.MethodPost,
+			summaryID: "summary-1",
+			mockSetup: func(m *MockSummaryRepository) {
+				m.On("Publish", mock.Anything, mock.Anything).Return(fmt.Errorf("summary already published: %w", Errors.ErrConflict))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:      "失敗ケース: サマリーが存在しない",
+			method:    http.MethodPost,
+			summaryID: "summary-1",
+			mockSetup: func(m *MockSummaryRepository) {
+				m.On("Publish", mock.Anything, mock.Anything).Return(fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockSummaryRepository)
+			mockSubcatRepo := new(MockSubcategoryRepository)
+			tt.mockSetup(mockRepo)
+
+			handler := New(mockRepo, mockSubcatRepo, fakeTxManager{})
+
+			req := httptest.NewRequest(tt.method, "/summaries/{id}/publish", nil)
+			req.SetPathValue("id", tt.summaryID)
+
+			w := httptest.NewRecorder()
+
+			handler.Publish(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.wantPublishedAt != "" {
+				var res response.DetailSummary
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if res.PublishedAt == nil {
+					t.Fatalf("published_at is null")
+				}
+				assert.Equal(t, tt.wantPublishedAt, *res.PublishedAt)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
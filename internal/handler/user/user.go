@@ -7,7 +7,10 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
 	"github.com/o-ga09/web-ya-hime/internal/handler/request"
 	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/o-ga09/web-ya-hime/pkg/ptr"
 )
 
 type IUserHandler interface {
@@ -28,8 +31,8 @@ func New(repo user.IUserRepository) IUserHandler {
 
 func (u *userHandler) Save(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -37,25 +40,35 @@ func (u *userHandler) Save(w http.ResponseWriter, r *http.Request) {
 
 	var req request.SaveUserRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
 	if r.Method == http.MethodPut && req.ID == nil {
-		http.Error(w, "User ID is required for update", http.StatusBadRequest)
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgIDRequiredForUpdate, i18n.T(ctx, i18n.ResUser))))
 		return
 	}
 
 	// ドメインモデルに変換
 	model := req.ToModel()
 
-	// リポジトリに保存
-	if err := u.repo.Save(ctx, model); err != nil {
-		http.Error(w, "Failed to save user", http.StatusInternalServerError)
+	// PUTの場合は更新、POSTの場合は新規作成
+	var err error
+	if r.Method == http.MethodPut {
+		err = u.repo.Update(ctx, model)
+	} else {
+		err = u.repo.Save(ctx, model)
+	}
+	if err != nil {
+		// メールアドレスの重複は409、更新対象がない場合は404になる
+		if Errors.Is(err, Errors.ErrUniqueConstraint) {
+			err = Errors.WrapWithMessage(ctx, err, i18n.T(ctx, i18n.MsgEmailAlreadyExists))
+		}
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -68,30 +81,59 @@ func (u *userHandler) Save(w http.ResponseWriter, r *http.Request) {
 func (u *userHandler) List(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
 	ctx := r.Context()
 
+	var req request.ListUserRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+
+	// デフォルト値の設定
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	opts := user.ListOptions{
+		Email:    ptr.PtrToString(req.Email),
+		Name:     ptr.PtrToString(req.Name),
+		UserType: ptr.PtrToString(req.UserType),
+		Limit:    req.Limit,
+		Offset:   req.Offset(),
+	}
+
 	// リポジトリからリストを取得
-	users, err := u.repo.List(ctx)
+	result, err := u.repo.List(ctx, opts)
 	if err != nil {
-		http.Error(w, "Failed to get user list", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
 	// レスポンスを返す
-	httputil.Response(&w, http.StatusOK, response.ListUser{
-		User:  response.ToListUser(users),
-		Total: len(users),
+	httputil.NegotiatedResponse(&w, r, http.StatusOK, response.ListUser{
+		User:    response.ToListUser(result.Items),
+		Total:   result.Total,
+		Page:    req.Page,
+		Limit:   result.Limit,
+		HasNext: result.HasNext,
 	})
 }
 
 func (u *userHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -100,11 +142,11 @@ func (u *userHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	// リクエスト構造体を作成してバリデーション
 	var req request.DetailUserRequest
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -118,7 +160,7 @@ func (u *userHandler) Detail(w http.ResponseWriter, r *http.Request) {
 	// リポジトリから詳細を取得
 	detail, err := u.repo.Detail(ctx, model)
 	if err != nil {
-		http.Error(w, "Failed to get user detail", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -131,7 +173,7 @@ func (u *userHandler) Detail(w http.ResponseWriter, r *http.Request) {
 func (u *userHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// メソッドチェック
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
 		return
 	}
 
@@ -140,11 +182,11 @@ func (u *userHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	var req request.DeleteUserRequest
 	// リクエスト構造体を作成してバリデーション
 	if err := request.Bind(r, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		response.BindError(ctx, w, err)
 		return
 	}
-	if err := request.Validate(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
 		return
 	}
 
@@ -157,7 +199,7 @@ func (u *userHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	// リポジトリから削除
 	if err := u.repo.Delete(ctx, model); err != nil {
-		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
+		response.Error(ctx, w, err)
 		return
 	}
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/o-ga09/web-ya-hime/internal/domain"
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,12 +28,17 @@ func (m *MockUserRepository) Save(ctx context.Context, model *user.User) error {
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) List(ctx context.Context) (user.UserSlice, error) {
-	args := m.Called(ctx)
+func (m *MockUserRepository) Update(ctx context.Context, model *user.User) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) List(ctx context.Context, opts user.ListOptions) (*user.ListResult, error) {
+	args := m.Called(ctx, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(user.UserSlice), args.Error(1)
+	return args.Get(0).(*user.ListResult), args.Error(1)
 }
 
 func (m *MockUserRepository) Detail(ctx context.Context, model *user.User) (*user.User, error) {
@@ -51,6 +58,7 @@ func TestUserHandler_Save(t *testing.T) {
 	tests := []struct {
 		name           string
 		method         string
+		userID         string
 		body           map[string]interface{}
 		mockSetup      func(*MockUserRepository)
 		expectedStatus int
@@ -111,6 +119,32 @@ func TestUserHandler_Save(t *testing.T) {
 			mockSetup:      func(m *MockUserRepository) {},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:   "失敗ケース: バリデーションエラー（emailの形式が不正）",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"name":      "Test User",
+				"email":     "Test User <test@example.com>",
+				"user_type": "admin",
+			},
+			mockSetup:      func(m *MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "成功ケース: メールアドレスが正規化されて保存される",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"name":      "Test User",
+				"email":     " Test@Example.COM ",
+				"user_type": "admin",
+			},
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Save", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.Email == "Test@example.com"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:   "失敗ケース: リポジトリでエラー",
 			method: http.MethodPost,
@@ -124,6 +158,69 @@ func TestUserHandler_Save(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:   "失敗ケース: メールアドレスが重複",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"name":      "Test User",
+				"email":     "test@example.com",
+				"user_type": "admin",
+			},
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Save", mock.Anything, mock.AnythingOfType("*user.User")).Return(Errors.ErrUniqueConstraint)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "成功ケース: ユーザーが正常に更新される",
+			method: http.MethodPut,
+			userID: "user-1",
+			body: map[string]interface{}{
+				"name":      "Updated User",
+				"email":     "updated@example.com",
+				"user_type": "admin",
+			},
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.ID == "user-1" && u.Name == "Updated User"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]string
+				err := json.Unmarshal([]byte(body), &res)
+				assert.NoError(t, err)
+				assert.Equal(t, "user-1", res["user_id"])
+			},
+		},
+		{
+			name:   "失敗ケース: 更新対象のユーザーが存在しない",
+			method: http.MethodPut,
+			userID: "non-existent",
+			body: map[string]interface{}{
+				"name":      "Updated User",
+				"email":     "updated@example.com",
+				"user_type": "admin",
+			},
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(Errors.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "失敗ケース: 更新時にメールアドレスが重複",
+			method: http.MethodPut,
+			userID: "user-1",
+			body: map[string]interface{}{
+				"name":      "Updated User",
+				"email":     "duplicate@example.com",
+				"user_type": "admin",
+			},
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(Errors.ErrUniqueConstraint)
+			},
+			expectedStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -136,6 +233,9 @@ func TestUserHandler_Save(t *testing.T) {
 			bodyBytes, _ := json.Marshal(tt.body)
 			req := httptest.NewRequest(tt.method, "/users", bytes.NewBuffer(bodyBytes))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.userID != "" {
+				req.SetPathValue("id", tt.userID)
+			}
 			w := httptest.NewRecorder()
 
 			handler.Save(w, req)
@@ -157,6 +257,7 @@ func TestUserHandler_List(t *testing.T) {
 	tests := []struct {
 		name           string
 		method         string
+		query          string
 		mockSetup      func(*MockUserRepository)
 		expectedStatus int
 		checkResponse  func(t *testing.T, body string)
@@ -187,7 +288,14 @@ func TestUserHandler_List(t *testing.T) {
 						UserType: "user",
 					},
 				}
-				m.On("List", mock.Anything).Return(users, nil)
+				result := &user.ListResult{
+					Items:   users,
+					Total:   2,
+					Limit:   20,
+					Offset:  0,
+					HasNext: false,
+				}
+				m.On("List", mock.Anything, mock.Anything).Return(result, nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, body string) {
@@ -203,7 +311,14 @@ func TestUserHandler_List(t *testing.T) {
 			name:   "成功ケース: 空のリストを返す",
 			method: http.MethodGet,
 			mockSetup: func(m *MockUserRepository) {
-				m.On("List", mock.Anything).Return(user.UserSlice{}, nil)
+				result := &user.ListResult{
+					Items:   user.UserSlice{},
+					Total:   0,
+					Limit:   20,
+					Offset:  0,
+					HasNext: false,
+				}
+				m.On("List", mock.Anything, mock.Anything).Return(result, nil)
 			},
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, body string) {
@@ -223,10 +338,47 @@ func TestUserHandler_List(t *testing.T) {
 			name:   "失敗ケース: リポジトリでエラー",
 			method: http.MethodGet,
 			mockSetup: func(m *MockUserRepository) {
-				m.On("List", mock.Anything).Return(nil, errors.New("db error"))
+				m.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name:   "成功ケース: 検索条件とページングが反映される",
+			method: http.MethodGet,
+			query:  "?email=sato@example.com&name=佐藤&user_type=user&page=3&limit=10",
+			mockSetup: func(m *MockUserRepository) {
+				want := user.ListOptions{
+					Email:    "sato@example.com",
+					Name:     "佐藤",
+					UserType: "user",
+					Limit:    10,
+					Offset:   20,
+				}
+				result := &user.ListResult{
+					Items:   user.UserSlice{},
+					Total:   0,
+					Limit:   10,
+					Offset:  20,
+					HasNext: false,
+				}
+				m.On("List", mock.Anything, want).Return(result, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				err := json.Unmarshal([]byte(body), &res)
+				assert.NoError(t, err)
+				assert.Equal(t, float64(3), res["page"])
+				assert.Equal(t, float64(10), res["limit"])
+			},
+		},
+		{
+			name:           "失敗ケース: limitが上限を超える",
+			method:         http.MethodGet,
+			query:          "?limit=101",
+			mockSetup:      func(m *MockUserRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -236,7 +388,7 @@ func TestUserHandler_List(t *testing.T) {
 
 			handler := New(mockRepo)
 
-			req := httptest.NewRequest(tt.method, "/users", nil)
+			req := httptest.NewRequest(tt.method, "/users"+tt.query, nil)
 			w := httptest.NewRecorder()
 
 			handler.List(w, req)
@@ -316,6 +468,47 @@ func TestUserHandler_Detail(t *testing.T) {
 				})).Return(nil, errors.New("not found"))
 			},
 			expectedStatus: http.StatusInternalServerError,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(body), &res))
+				assert.Equal(t, "critical_error", res["code"])
+				assert.Equal(t, "システムエラーが発生しました。", res["detail"])
+			},
+		},
+		{
+			name:   "失敗ケース: ユーザーが存在しない",
+			method: http.MethodGet,
+			userID: "user-1",
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(body), &res))
+				assert.Equal(t, "about:blank", res["type"])
+				assert.Equal(t, "Not Found", res["title"])
+				assert.Equal(t, float64(http.StatusNotFound), res["status"])
+				assert.Equal(t, "not_found", res["code"])
+				assert.Equal(t, "データが見つかりません。", res["detail"])
+			},
+		},
+		{
+			name:   "失敗ケース: クライアントが切断した場合は499",
+			method: http.MethodGet,
+			userID: "user-1",
+			mockSetup: func(m *MockUserRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("failed to get user detail: %w", context.Canceled))
+			},
+			expectedStatus: 499,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(body), &res))
+				assert.Equal(t, "Client Closed Request", res["title"])
+				assert.Equal(t, "client_closed_request", res["code"])
+			},
 		},
 	}
 
@@ -335,6 +528,9 @@ func TestUserHandler_Detail(t *testing.T) {
 			handler.Detail(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code >= http.StatusBadRequest {
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			}
 
 			if tt.checkResponse != nil {
 				tt.checkResponse(t, w.Body.String())
@@ -0,0 +1,305 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	"github.com/o-ga09/web-ya-hime/internal/handler/request"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type IWebhookHandler interface {
+	Save(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Detail(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Deliveries(w http.ResponseWriter, r *http.Request)
+	Redeliver(w http.ResponseWriter, r *http.Request)
+}
+
+type webhookHandler struct {
+	repo       webhook.IWebhookRepository
+	deliveries webhook.IDeliveryRepository
+}
+
+func New(repo webhook.IWebhookRepository, deliveries webhook.IDeliveryRepository) IWebhookHandler {
+	return &webhookHandler{
+		repo:       repo,
+		deliveries: deliveries,
+	}
+}
+
+// Save はWebhookを登録・更新します
+// 秘密鍵を省略して登録した場合は生成し、このレスポンスでだけ返します
+// 更新では登録したユーザーは変更できず、省略した秘密鍵と有効・無効は登録済みの値を使います
+func (h *webhookHandler) Save(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.SaveWebhookRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	if r.Method == http.MethodPut && req.ID == "" {
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrInvalidArgument, i18n.T(ctx, i18n.MsgIDRequiredForUpdate, i18n.T(ctx, i18n.ResWebhook))))
+		return
+	}
+
+	model := &webhook.Webhook{
+		WYHBaseModel: domain.WYHBaseModel{
+			ID: req.ID,
+		},
+		UserID:     req.UserID,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: make([]event.Type, len(req.EventTypes)),
+		Active:     true,
+	}
+	for i, t := range req.EventTypes {
+		model.EventTypes[i] = event.Type(t)
+	}
+
+	if r.Method == http.MethodPut {
+		current, err := h.repo.Detail(ctx, model)
+		if err != nil {
+			response.Error(ctx, w, err)
+			return
+		}
+		model.UserID = current.UserID
+		model.Active = current.Active
+		if model.Secret == "" {
+			model.Secret = current.Secret
+		}
+	} else if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+	if req.Active != nil {
+		model.Active = *req.Active
+	}
+
+	res := map[string]string{}
+	if model.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			response.Error(ctx, w, err)
+			return
+		}
+		model.Secret = secret
+		res["secret"] = secret
+	}
+
+	if err := h.repo.Save(ctx, model); err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	res["webhook_id"] = model.ID
+	httputil.Response(&w, http.StatusOK, res)
+}
+
+// List はWebhookの一覧を返します。user_id を指定した場合はそのユーザーのWebhookだけを返します
+func (h *webhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.ListWebhookRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	webhooks, err := h.repo.List(ctx, req.UserID)
+	if err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.NegotiatedResponse(&w, r, http.StatusOK, response.WebhookListResponse{
+		Webhooks: response.ToWebhookListResponse(webhooks),
+	})
+}
+
+func (h *webhookHandler) Detail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.DetailWebhookRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	detail, err := h.repo.Detail(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: req.ID}})
+	if err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.Response(&w, http.StatusOK, response.ToWebhookResponse(detail))
+}
+
+// Delete はWebhookと配信履歴を削除します
+// 送信待ちの配信も削除されるため、以降は送信されません
+func (h *webhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.DeleteWebhookRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	if err := h.repo.Delete(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: req.ID}}); err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.Response(&w, http.StatusOK, map[string]string{
+		"message": i18n.T(ctx, i18n.MsgDeleted, i18n.T(ctx, i18n.ResWebhook)),
+	})
+}
+
+// Deliveries はWebhookの配信履歴を新しい順に返します
+func (h *webhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.ListWebhookDeliveryRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+
+	// デフォルト値の設定
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	// 存在しないWebhookは空の一覧ではなく404にする
+	if _, err := h.repo.Detail(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: req.ID}}); err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	result, err := h.deliveries.List(ctx, req.ID, req.Limit, req.Offset)
+	if err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.NegotiatedResponse(&w, r, http.StatusOK, response.WebhookDeliveryListResponse{
+		Deliveries: response.ToWebhookDeliveryListResponse(result.Items),
+		Total:      result.Total,
+		Limit:      result.Limit,
+		Offset:     result.Offset,
+		HasNext:    result.HasNext,
+	})
+}
+
+// Redeliver は配信と同じ内容を新しい配信として送信します
+// 送信は非同期に行うため 202 を返し、結果は配信履歴で確認します
+func (h *webhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.Error(r.Context(), w, Errors.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	var req request.RedeliverWebhookRequest
+	if err := request.Bind(r, &req); err != nil {
+		response.BindError(ctx, w, err)
+		return
+	}
+	if err := request.Validate(ctx, &req); err != nil {
+		response.ValidationError(ctx, w, err)
+		return
+	}
+
+	original, err := h.deliveries.Detail(ctx, &webhook.Delivery{ID: req.DeliveryID})
+	if err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+	// 別のWebhookの配信は存在しないものとして扱う
+	if original.WebhookID != req.ID {
+		response.Error(ctx, w, Errors.ErrRecordNotFound)
+		return
+	}
+
+	redelivery := &webhook.Delivery{
+		WebhookID:    original.WebhookID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: original.ID,
+	}
+	if err := h.deliveries.Create(ctx, redelivery); err != nil {
+		response.Error(ctx, w, err)
+		return
+	}
+
+	httputil.Response(&w, http.StatusAccepted, map[string]string{
+		"delivery_id": redelivery.ID,
+	})
+}
+
+// generateSecret は署名に使う32バイトのランダムな秘密鍵を生成します
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,510 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository はwebhook.IWebhookRepositoryのモック
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Save(ctx context.Context, model *webhook.Webhook) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context, userID string) (webhook.WebhookSlice, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(webhook.WebhookSlice), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Detail(ctx context.Context, model *webhook.Webhook) (*webhook.Webhook, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, model *webhook.Webhook) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+// MockDeliveryRepository はwebhook.IDeliveryRepositoryのモック
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) Create(ctx context.Context, model *webhook.Delivery) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) List(ctx context.Context, webhookID string, limit, offset int) (*webhook.DeliveryListResult, error) {
+	args := m.Called(ctx, webhookID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.DeliveryListResult), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Detail(ctx context.Context, model *webhook.Delivery) (*webhook.Delivery, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) UpdateResult(ctx context.Context, model *webhook.Delivery) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+const (
+	webhookID  = "0b9ae3a4-5d7c-4f4e-9a0e-2f4f3b0f6c11"
+	userID     = "7c3e1f0a-2b4d-4e6f-8a9b-0c1d2e3f4a5b"
+	deliveryID = "4f6a8c0e-1b3d-4f5a-9c7e-2d4f6a8c0e1b"
+)
+
+func TestWebhookHandler_Save(t *testing.T) {
+	current := &webhook.Webhook{
+		WYHBaseModel: domain.WYHBaseModel{ID: webhookID},
+		UserID:       userID,
+		URL:          "https://example.com/old",
+		Secret:       "registered-secret-value",
+		EventTypes:   []event.Type{event.TypeSummaryCreated},
+		Active:       false,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		id             string
+		body           map[string]interface{}
+		mockSetup      func(*MockWebhookRepository)
+		expectedStatus int
+		checkResponse  func(t *testing.T, res map[string]string)
+	}{
+		{
+			name:   "成功ケース: 秘密鍵を省略すると生成して返す",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/hook",
+				"event_types": []string{"summary.created", "summary.deleted"},
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Save", mock.Anything, mock.MatchedBy(func(w *webhook.Webhook) bool {
+					return w.ID != "" && w.UserID == userID && len(w.Secret) == 64 && w.Active &&
+						assert.ObjectsAreEqual([]event.Type{event.TypeSummaryCreated, event.TypeSummaryDeleted}, w.EventTypes)
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res map[string]string) {
+				assert.NotEmpty(t, res["webhook_id"])
+				assert.Len(t, res["secret"], 64)
+			},
+		},
+		{
+			name:   "成功ケース: サマリーの公開を購読できる",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/hook",
+				"event_types": []string{"summary.published"},
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Save", mock.Anything, mock.MatchedBy(func(w *webhook.Webhook) bool {
+					return assert.ObjectsAreEqual([]event.Type{event.TypeSummaryPublished}, w.EventTypes)
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "成功ケース: 指定した秘密鍵は返さない",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/hook",
+				"secret":      "my-own-secret-value",
+				"event_types": []string{"summary.created"},
+				"active":      false,
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Save", mock.Anything, mock.MatchedBy(func(w *webhook.Webhook) bool {
+					return w.Secret == "my-own-secret-value" && !w.Active
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res map[string]string) {
+				assert.NotContains(t, res, "secret")
+			},
+		},
+		{
+			name:   "成功ケース: 更新では秘密鍵と有効・無効を引き継ぐ",
+			method: http.MethodPut,
+			id:     webhookID,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/new",
+				"event_types": []string{"category.created"},
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(current, nil)
+				m.On("Save", mock.Anything, mock.MatchedBy(func(w *webhook.Webhook) bool {
+					return w.ID == webhookID && w.URL == "https://example.com/new" && w.Secret == "registered-secret-value" && !w.Active
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, res map[string]string) {
+				assert.Equal(t, webhookID, res["webhook_id"])
+				assert.NotContains(t, res, "secret")
+			},
+		},
+		{
+			name:   "失敗ケース: 更新対象のWebhookが存在しない",
+			method: http.MethodPut,
+			id:     webhookID,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/new",
+				"event_types": []string{"summary.created"},
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(nil, Errors.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "失敗ケース: 存在しないイベントの種類",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/hook",
+				"event_types": []string{"summary.archived"},
+			},
+			mockSetup:      func(m *MockWebhookRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "失敗ケース: 未知のフィールド",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":    userID,
+				"url":        "https://example.com/hook",
+				"event_type": []string{"summary.created"},
+			},
+			mockSetup:      func(m *MockWebhookRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "失敗ケース: http/https以外のURL",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "ftp://example.com/hook",
+				"event_types": []string{"summary.created"},
+			},
+			mockSetup:      func(m *MockWebhookRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "失敗ケース: クラウドのメタデータサーバーのURL",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "http://169.254.169.254/latest/meta-data",
+				"event_types": []string{"summary.created"},
+			},
+			mockSetup:      func(m *MockWebhookRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "失敗ケース: 存在しないユーザー",
+			method: http.MethodPost,
+			body: map[string]interface{}{
+				"user_id":     userID,
+				"url":         "https://example.com/hook",
+				"event_types": []string{"summary.created"},
+			},
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Save", mock.Anything, mock.Anything).Return(Errors.ErrForeignKeyConstraint)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "失敗ケース: メソッドが不正",
+			method:         http.MethodGet,
+			body:           map[string]interface{}{},
+			mockSetup:      func(m *MockWebhookRepository) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			tt.mockSetup(mockRepo)
+
+			handler := New(mockRepo, new(MockDeliveryRepository))
+
+			bodyBytes, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(tt.method, "/webhooks", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.id != "" {
+				req.SetPathValue("id", tt.id)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Save(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkResponse != nil {
+				var res map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				tt.checkResponse(t, res)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_Detail(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSetup      func(*MockWebhookRepository)
+		expectedStatus int
+	}{
+		{
+			name: "成功ケース: 秘密鍵を含めずに返す",
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(&webhook.Webhook{
+					WYHBaseModel: domain.WYHBaseModel{ID: webhookID},
+					Secret:       "registered-secret-value",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "失敗ケース: Webhookが存在しない",
+			mockSetup: func(m *MockWebhookRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(nil, Errors.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			tt.mockSetup(mockRepo)
+
+			handler := New(mockRepo, new(MockDeliveryRepository))
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+webhookID, nil)
+			req.SetPathValue("id", webhookID)
+			w := httptest.NewRecorder()
+
+			handler.Detail(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "registered-secret-value")
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_Deliveries(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockWebhookRepository, *MockDeliveryRepository)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body string)
+	}{
+		{
+			name:  "成功ケース: 配信履歴を取得",
+			query: "?limit=10&offset=10",
+			mockSetup: func(m *MockWebhookRepository, d *MockDeliveryRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(&webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: webhookID}}, nil)
+				d.On("List", mock.Anything, webhookID, 10, 10).Return(&webhook.DeliveryListResult{
+					Items: webhook.DeliverySlice{
+						{ID: deliveryID, WebhookID: webhookID, EventType: event.TypeSummaryCreated, Payload: []byte(`{}`), Status: webhook.DeliveryStatusDead, Attempts: 8, ResponseStatus: 500},
+					},
+					Total:   11,
+					Limit:   10,
+					Offset:  10,
+					HasNext: false,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body string) {
+				var res map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(body), &res))
+				assert.Equal(t, float64(11), res["total"])
+				deliveries := res["deliveries"].([]interface{})
+				if assert.Len(t, deliveries, 1) {
+					d := deliveries[0].(map[string]interface{})
+					assert.Equal(t, "dead", d["status"])
+					assert.Equal(t, float64(500), d["response_status"])
+				}
+			},
+		},
+		{
+			name: "成功ケース: 件数を省略すると20件",
+			mockSetup: func(m *MockWebhookRepository, d *MockDeliveryRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(&webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: webhookID}}, nil)
+				d.On("List", mock.Anything, webhookID, 20, 0).Return(&webhook.DeliveryListResult{Items: webhook.DeliverySlice{}, Limit: 20}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "失敗ケース: Webhookが存在しない",
+			mockSetup: func(m *MockWebhookRepository, d *MockDeliveryRepository) {
+				m.On("Detail", mock.Anything, mock.Anything).Return(nil, Errors.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "失敗ケース: limitが上限を超える",
+			query:          "?limit=101",
+			mockSetup:      func(m *MockWebhookRepository, d *MockDeliveryRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockWebhookRepository)
+			mockDeliveries := new(MockDeliveryRepository)
+			tt.mockSetup(mockRepo, mockDeliveries)
+
+			handler := New(mockRepo, mockDeliveries)
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+webhookID+"/deliveries"+tt.query, nil)
+			req.SetPathValue("id", webhookID)
+			w := httptest.NewRecorder()
+
+			handler.Deliveries(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, w.Body.String())
+			}
+			mockRepo.AssertExpectations(t)
+			mockDeliveries.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	original := &webhook.Delivery{
+		ID:        deliveryID,
+		WebhookID: webhookID,
+		EventID:   "event-1",
+		EventType: event.TypeSummaryCreated,
+		Payload:   []byte(`{"id":"event-1"}`),
+		DedupKey:  "event-1",
+		Status:    webhook.DeliveryStatusDead,
+		Attempts:  8,
+	}
+
+	tests := []struct {
+		name           string
+		webhookID      string
+		mockSetup      func(*MockDeliveryRepository)
+		expectedStatus int
+	}{
+		{
+			name:      "成功ケース: 同じ内容の配信を新しく保存する",
+			webhookID: webhookID,
+			mockSetup: func(d *MockDeliveryRepository) {
+				d.On("Detail", mock.Anything, mock.Anything).Return(original, nil)
+				d.On("Create", mock.Anything, mock.MatchedBy(func(nd *webhook.Delivery) bool {
+					return nd.WebhookID == webhookID && nd.EventID == "event-1" && nd.RedeliveryOf == deliveryID &&
+						nd.DedupKey == "" && nd.Attempts == 0 && string(nd.Payload) == `{"id":"event-1"}`
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*webhook.Delivery).ID = "new-delivery"
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:      "失敗ケース: 別のWebhookの配信",
+			webhookID: userID,
+			mockSetup: func(d *MockDeliveryRepository) {
+				d.On("Detail", mock.Anything, mock.Anything).Return(original, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "失敗ケース: 配信が存在しない",
+			webhookID: webhookID,
+			mockSetup: func(d *MockDeliveryRepository) {
+				d.On("Detail", mock.Anything, mock.Anything).Return(nil, Errors.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "失敗ケース: 保存に失敗",
+			webhookID: webhookID,
+			mockSetup: func(d *MockDeliveryRepository) {
+				d.On("Detail", mock.Anything, mock.Anything).Return(original, nil)
+				d.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeliveries := new(MockDeliveryRepository)
+			tt.mockSetup(mockDeliveries)
+
+			handler := New(new(MockWebhookRepository), mockDeliveries)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/"+tt.webhookID+"/deliveries/"+deliveryID+"/redeliver", nil)
+			req.SetPathValue("id", tt.webhookID)
+			req.SetPathValue("delivery_id", deliveryID)
+			w := httptest.NewRecorder()
+
+			handler.Redeliver(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				var res map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				assert.Equal(t, "new-delivery", res["delivery_id"])
+			}
+			mockDeliveries.AssertExpectations(t)
+		})
+	}
+}
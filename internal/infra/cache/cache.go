@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+)
+
+// キャッシュのタグ
+// 一覧は対象のテーブルへの書き込みですべて無効化し、
+// 詳細と一覧のエントリには結合したカテゴリ・サブカテゴリ・ユーザーのタグも付けて名前の変更や削除で無効化します
+const (
+	tagSummaryList     = "summary:list"
+	tagCategoryList    = "category:list"
+	tagSubcategoryList = "subcategory:list"
+)
+
+func summaryTag(id string) string     { return "summary:" + id }
+func categoryTag(id string) string    { return "category:" + id }
+func subcategoryTag(id string) string { return "subcategory:" + id }
+func userTag(id string) string        { return "user:" + id }
+
+// bypass はキャッシュを使わずにリポジトリから直接読み込むかを返します
+// トランザクション中はコミット前のデータを保存しないように、
+// プライマリから読む指定がある場合は他のインスタンスの書き込みを確実に読めるようにキャッシュを使いません
+func bypass(ctx context.Context) bool {
+	return Ctx.InTx(ctx) || Ctx.IsReadFromPrimary(ctx)
+}
+
+// invalidate はタグの付いたエントリを削除します
+// トランザクション中はコミット前に他のリクエストが古い値を保存する可能性があるため、コミット後にも削除します
+func invalidate(ctx context.Context, c *Cache.Cache[any], tags ...string) {
+	c.InvalidateTags(tags...)
+	Ctx.AfterCommit(ctx, func() { c.InvalidateTags(tags...) })
+}
+
+// load はキャッシュから取得し、ない場合は load で読み込んで保存します
+func load[V any](ctx context.Context, c *Cache.Cache[any], key string, fn func() (V, []string, error)) (V, error) {
+	if bypass(ctx) {
+		v, _, err := fn()
+		return v, err
+	}
+
+	v, err := c.GetOrLoad(key, func() (any, []string, error) {
+		return fn()
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+)
+
+type categoryRepository struct {
+	next  category.ICategoryRepository
+	cache *Cache.Cache[any]
+}
+
+// NewCategoryRepository は一覧と詳細の取得結果をキャッシュするカテゴリリポジトリを生成します
+// 保存と削除ではカテゴリを結合したサブカテゴリとサマリーのキャッシュも削除します
+func NewCategoryRepository(next category.ICategoryRepository, c *Cache.Cache[any]) category.ICategoryRepository {
+	return &categoryRepository{next: next, cache: c}
+}
+
+func (r *categoryRepository) Save(ctx context.Context, model *category.Category) error {
+	if err := r.next.Save(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagCategoryList, categoryTag(model.ID))
+	return nil
+}
+
+func (r *categoryRepository) List(ctx context.Context) (category.CategorySlice, error) {
+	return load(ctx, r.cache, "category:list", func() (category.CategorySlice, []string, error) {
+		categories, err := r.next.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return categories, []string{tagCategoryList}, nil
+	})
+}
+
+func (r *categoryRepository) Detail(ctx context.Context, model *category.Category) (*category.Category, error) {
+	return load(ctx, r.cache, "category:detail:"+model.ID, func() (*category.Category, []string, error) {
+		detail, err := r.next.Detail(ctx, model)
+		if err != nil {
+			return nil, nil, err
+		}
+		return detail, []string{categoryTag(model.ID)}, nil
+	})
+}
+
+// Delete はカテゴリを削除します
+// サブカテゴリも削除されるため、サブカテゴリの一覧もすべて無効化します
+func (r *categoryRepository) Delete(ctx context.Context, model *category.Category) error {
+	if err := r.next.Delete(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagCategoryList, tagSubcategoryList, categoryTag(model.ID))
+	return nil
+}
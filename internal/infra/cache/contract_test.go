@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/contract"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/memory"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+)
+
+// キャッシュを挟んでも書き込み後の読み込みで古い値を返さないことを確認する
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) (context.Context, contract.Repositories) {
+		store := memory.NewStore()
+		c := Cache.New[any](100, time.Minute)
+		return context.Background(), contract.Repositories{
+			User:        NewUserRepository(memory.NewUserRepository(store), c),
+			Summary:     NewSummaryRepository(memory.NewSummaryRepository(store), c),
+			Category:    NewCategoryRepository(memory.NewCategoryRepository(store), c),
+			Subcategory: NewSubcategoryRepository(memory.NewSubcategoryRepository(store), c),
+			Outbox:      memory.NewOutboxRepository(store),
+			Webhook:     memory.NewWebhookRepository(store),
+			Delivery:    memory.NewWebhookDeliveryRepository(store),
+		}
+	})
+}
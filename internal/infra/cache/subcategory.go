@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+)
+
+type subcategoryRepository struct {
+	next  subcategory.ISubcategoryRepository
+	cache *Cache.Cache[any]
+}
+
+// NewSubcategoryRepository は一覧と詳細の取得結果をキャッシュするサブカテゴリリポジトリを生成します
+// 保存と削除ではサブカテゴリを結合したサマリーのキャッシュも削除します
+func NewSubcategoryRepository(next subcategory.ISubcategoryRepository, c *Cache.Cache[any]) subcategory.ISubcategoryRepository {
+	return &subcategoryRepository{next: next, cache: c}
+}
+
+func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Subcategory) error {
+	if err := r.next.Save(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagSubcategoryList, subcategoryTag(model.ID))
+	return nil
+}
+
+func (r *subcategoryRepository) List(ctx context.Context, categoryID string) (subcategory.SubcategorySlice, error) {
+	return load(ctx, r.cache, "subcategory:list:"+categoryID, func() (subcategory.SubcategorySlice, []string, error) {
+		subcategories, err := r.next.List(ctx, categoryID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tags := []string{tagSubcategoryList}
+		if categoryID != "" {
+			tags = append(tags, categoryTag(categoryID))
+		}
+		for _, s := range subcategories {
+			tags = append(tags, categoryTag(s.CategoryID))
+		}
+		return subcategories, tags, nil
+	})
+}
+
+func (r *subcategoryRepository) Detail(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	return load(ctx, r.cache, "subcategory:detail:"+model.ID, func() (*subcategory.Subcategory, []string, error) {
+		detail, err := r.next.Detail(ctx, model)
+		if err != nil {
+			return nil, nil, err
+		}
+		return detail, []string{subcategoryTag(model.ID), categoryTag(detail.CategoryID)}, nil
+	})
+}
+
+// DetailForShare はロックを取るためにキャッシュを使いません
+func (r *subcategoryRepository) DetailForShare(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	return r.next.DetailForShare(ctx, model)
+}
+
+func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.Subcategory) error {
+	if err := r.next.Delete(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagSubcategoryList, subcategoryTag(model.ID))
+	return nil
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+)
+
+type summaryRepository struct {
+	next  summary.ISummaryRepository
+	cache *Cache.Cache[any]
+}
+
+// NewSummaryRepository は一覧と詳細の取得結果をキャッシュするサマリーリポジトリを生成します
+// 保存・削除・公開では対象のサマリーとすべての一覧のキャッシュを削除します
+func NewSummaryRepository(next summary.ISummaryRepository, c *Cache.Cache[any]) summary.ISummaryRepository {
+	return &summaryRepository{next: next, cache: c}
+}
+
+func (r *summaryRepository) Save(ctx context.Context, model *summary.Summary) error {
+	if err := r.next.Save(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagSummaryList, summaryTag(model.ID))
+	return nil
+}
+
+func (r *summaryRepository) List(ctx context.Context, opts summary.ListOptions) (*summary.ListResult, error) {
+	key := fmt.Sprintf("summary:list:%q:%q:%q:%d:%d", opts.Category, opts.CategoryID, opts.SubcategoryID, opts.Limit, opts.Offset)
+	return load(ctx, r.cache, key, func() (*summary.ListResult, []string, error) {
+		result, err := r.next.List(ctx, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tags := []string{tagSummaryList}
+		if opts.CategoryID != "" {
+			tags = append(tags, categoryTag(opts.CategoryID))
+		}
+		if opts.SubcategoryID != "" {
+			tags = append(tags, subcategoryTag(opts.SubcategoryID))
+		}
+		for _, item := range result.Items {
+			tags = append(tags, summaryRelationTags(item)...)
+		}
+		return result, tags, nil
+	})
+}
+
+func (r *summaryRepository) Detail(ctx context.Context, model *summary.Summary) (*summary.Summary, error) {
+	return load(ctx, r.cache, "summary:detail:"+model.ID, func() (*summary.Summary, []string, error) {
+		detail, err := r.next.Detail(ctx, model)
+		if err != nil {
+			return nil, nil, err
+		}
+		return detail, append(summaryRelationTags(detail), summaryTag(model.ID)), nil
+	})
+}
+
+func (r *summaryRepository) Delete(ctx context.Context, model *summary.Summary) error {
+	if err := r.next.Delete(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagSummaryList, summaryTag(model.ID))
+	return nil
+}
+
+func (r *summaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	if err := r.next.Publish(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tagSummaryList, summaryTag(model.ID))
+	return nil
+}
+
+// summaryRelationTags はサマリーに結合したカテゴリ・サブカテゴリ・ユーザーのタグを返します
+func summaryRelationTags(s *summary.Summary) []string {
+	tags := []string{userTag(s.UserID)}
+	if s.CategoryID.Valid {
+		tags = append(tags, categoryTag(s.CategoryID.String))
+	}
+	if s.SubcategoryID.Valid {
+		tags = append(tags, subcategoryTag(s.SubcategoryID.String))
+	}
+	return tags
+}
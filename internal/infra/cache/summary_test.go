@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/memory"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSummaryRepository はsummary.ISummaryRepositoryのモック
+type MockSummaryRepository struct {
+	mock.Mock
+}
+
+func (m *MockSummaryRepository) Save(ctx context.Context, model *summary.Summary) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockSummaryRepository) List(ctx context.Context, opts summary.ListOptions) (*summary.ListResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*summary.ListResult), args.Error(1)
+}
+
+func (m *MockSummaryRepository) Detail(ctx context.Context, model *summary.Summary) (*summary.Summary, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*summary.Summary), args.Error(1)
+}
+
+func (m *MockSummaryRepository) Delete(ctx context.Context, model *summary.Summary) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockSummaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func TestSummaryRepository_Cache(t *testing.T) {
+	detail := &summary.Summary{
+		WYHBaseModel: domain.WYHBaseModel{ID: "summary-1"},
+		CategoryID:   sql.NullString{String: "category-1", Valid: true},
+		UserID:       "user-1",
+	}
+	list := &summary.ListResult{Items: summary.SummarySlice{detail}, Total: 1, Limit: 20}
+	model := &summary.Summary{WYHBaseModel: domain.WYHBaseModel{ID: "summary-1"}}
+	opts := summary.ListOptions{Limit: 20}
+
+	tests := []struct {
+		name            string
+		run             func(ctx context.Context, repo summary.ISummaryRepository, categoryRepo category.ICategoryRepository)
+		wantDetailCalls int
+		wantListCalls   int
+		wantHits        uint64
+	}{
+		{
+			name: "成功ケース: 2回目以降の取得はキャッシュから返す",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				repo.Detail(ctx, model)
+				repo.List(ctx, opts)
+				repo.List(ctx, opts)
+			},
+			wantDetailCalls: 1,
+			wantListCalls:   1,
+			wantHits:        2,
+		},
+		{
+			name: "成功ケース: 保存すると対象の詳細と一覧を無効化する",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				repo.List(ctx, opts)
+				repo.Save(ctx, model)
+				repo.Detail(ctx, model)
+				repo.List(ctx, opts)
+			},
+			wantDetailCalls: 2,
+			wantListCalls:   2,
+		},
+		{
+			name: "成功ケース: 削除すると対象の詳細と一覧を無効化する",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				repo.Delete(ctx, model)
+				repo.Detail(ctx, model)
+			},
+			wantDetailCalls: 2,
+		},
+		{
+			name: "成功ケース: 結合したカテゴリを更新すると無効化する",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, categoryRepo category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				repo.List(ctx, opts)
+				categoryRepo.Save(ctx, &category.Category{WYHBaseModel: domain.WYHBaseModel{ID: "category-1"}, Name: "雑談"})
+				repo.Detail(ctx, model)
+				repo.List(ctx, opts)
+			},
+			wantDetailCalls: 2,
+			wantListCalls:   2,
+		},
+		{
+			name: "成功ケース: 別のカテゴリを更新しても無効化しない",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, categoryRepo category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				categoryRepo.Save(ctx, &category.Category{WYHBaseModel: domain.WYHBaseModel{ID: "category-2"}, Name: "歌枠"})
+				repo.Detail(ctx, model)
+			},
+			wantDetailCalls: 1,
+			wantHits:        1,
+		},
+		{
+			name: "成功ケース: トランザクション中はキャッシュを使わない",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				txCtx, _ := Ctx.WithAfterCommit(ctx)
+				repo.Detail(txCtx, model)
+			},
+			wantDetailCalls: 2,
+		},
+		{
+			name: "成功ケース: プライマリから読む指定がある場合はキャッシュを使わない",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				repo.Detail(ctx, model)
+				repo.Detail(Ctx.SetReadFromPrimary(ctx), model)
+			},
+			wantDetailCalls: 2,
+		},
+		{
+			name: "成功ケース: トランザクション中の保存はコミット後にも無効化する",
+			run: func(ctx context.Context, repo summary.ISummaryRepository, _ category.ICategoryRepository) {
+				txCtx, commit := Ctx.WithAfterCommit(ctx)
+				repo.Save(txCtx, model)
+				// コミット前に別のリクエストが古い値を保存する
+				repo.Detail(ctx, model)
+				commit()
+				repo.Detail(ctx, model)
+			},
+			wantDetailCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := Cache.New[any](100, time.Minute)
+
+			mockRepo := new(MockSummaryRepository)
+			mockRepo.On("Detail", mock.Anything, mock.Anything).Return(detail, nil)
+			mockRepo.On("List", mock.Anything, mock.Anything).Return(list, nil)
+			mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			mockRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+			repo := NewSummaryRepository(mockRepo, c)
+			categoryRepo := NewCategoryRepository(memory.NewCategoryRepository(memory.NewStore()), c)
+			tt.run(ctx, repo, categoryRepo)
+
+			mockRepo.AssertNumberOfCalls(t, "Detail", tt.wantDetailCalls)
+			mockRepo.AssertNumberOfCalls(t, "List", tt.wantListCalls)
+			assert.Equal(t, tt.wantHits, c.Stats().Hits)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+)
+
+type userRepository struct {
+	user.IUserRepository
+	cache *Cache.Cache[any]
+}
+
+// NewUserRepository はユーザーの更新と削除でユーザーを結合したサマリーのキャッシュを削除するリポジトリを生成します
+// ユーザー自体の取得結果はキャッシュしません
+func NewUserRepository(next user.IUserRepository, c *Cache.Cache[any]) user.IUserRepository {
+	return &userRepository{IUserRepository: next, cache: c}
+}
+
+func (r *userRepository) Update(ctx context.Context, model *user.User) error {
+	if err := r.IUserRepository.Update(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, userTag(model.ID))
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, model *user.User) error {
+	if err := r.IUserRepository.Delete(ctx, model); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, userTag(model.ID))
+	return nil
+}
@@ -0,0 +1,1149 @@
+// Package contract はリポジトリの実装が共通して満たすべき仕様をテストします
+// MySQLとインメモリなど、実装ごとのテストから Run を呼び出して同じ振る舞いであることを確認します
+package contract
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Repositories はテスト対象のリポジトリ一式です
+type Repositories struct {
+	User        user.IUserRepository
+	Summary     summary.ISummaryRepository
+	Category    category.ICategoryRepository
+	Subcategory subcategory.ISubcategoryRepository
+	Outbox      event.IOutboxRepository
+	Webhook     webhook.IWebhookRepository
+	Delivery    webhook.IDeliveryRepository
+	// RateLimit はレート制限のストアです。リポジトリを包むだけの実装のテストでは nil にして省略できます
+	RateLimit ratelimit.Store
+}
+
+// SetupFunc は空のデータストアに接続したcontextとリポジトリを返します
+// テストケースごとに呼び出されるため、前のテストケースのデータを残さないようにしてください
+type SetupFunc func(t *testing.T) (context.Context, Repositories)
+
+// Run はすべてのリポジトリのコントラクトテストを実行します
+func Run(t *testing.T, setup SetupFunc) {
+	t.Run("User", func(t *testing.T) { testUserRepository(t, setup) })
+	t.Run("Category", func(t *testing.T) { testCategoryRepository(t, setup) })
+	t.Run("Subcategory", func(t *testing.T) { testSubcategoryRepository(t, setup) })
+	t.Run("Summary", func(t *testing.T) { testSummaryRepository(t, setup) })
+	t.Run("Outbox", func(t *testing.T) { testOutboxRepository(t, setup) })
+	t.Run("Webhook", func(t *testing.T) { testWebhookRepository(t, setup) })
+	t.Run("WebhookDelivery", func(t *testing.T) { testWebhookDeliveryRepository(t, setup) })
+	t.Run("RateLimit", func(t *testing.T) { testRateLimitStore(t, setup) })
+}
+
+func testUserRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: 保存したユーザーを取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+
+				got, err := repos.User.Detail(ctx, &user.User{WYHBaseModel: domain.WYHBaseModel{ID: u.ID}})
+				assert.NoError(t, err)
+				assert.Equal(t, u.ID, got.ID)
+				assert.Equal(t, "Alice", got.Name)
+				assert.Equal(t, "alice@example.com", got.Email)
+				assert.Equal(t, "admin", got.UserType)
+				assert.False(t, got.CreatedAt.IsZero())
+			},
+		},
+		{
+			name: "成功ケース: ユーザーを更新できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				u.Name = "Alice Updated"
+				u.Email = "alice.updated@example.com"
+				assert.NoError(t, repos.User.Update(ctx, u))
+
+				got, err := repos.User.Detail(ctx, u)
+				assert.NoError(t, err)
+				assert.Equal(t, "Alice Updated", got.Name)
+				assert.Equal(t, "alice.updated@example.com", got.Email)
+			},
+		},
+		{
+			name: "成功ケース: 絞り込みとページネーション",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				saveUser(t, ctx, repos, "Bob", "bob@example.com")
+				saveUser(t, ctx, repos, "Alicia", "alicia@example.com")
+
+				got, err := repos.User.List(ctx, user.ListOptions{Name: "ali", Limit: 1})
+				assert.NoError(t, err)
+				assert.Equal(t, 2, got.Total)
+				assert.Len(t, got.Items, 1)
+				assert.True(t, got.HasNext)
+
+				got, err = repos.User.List(ctx, user.ListOptions{Name: "ali", Limit: 1, Offset: 1})
+				assert.NoError(t, err)
+				assert.Len(t, got.Items, 1)
+				assert.False(t, got.HasNext)
+
+				got, err = repos.User.List(ctx, user.ListOptions{Email: "BOB@example.com"})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, got.Total)
+				assert.Equal(t, 20, got.Limit)
+			},
+		},
+		{
+			name: "成功ケース: 論理削除したユーザーは取得できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				assert.NoError(t, repos.User.Delete(ctx, u))
+
+				_, err := repos.User.Detail(ctx, u)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+
+				got, err := repos.User.List(ctx, user.ListOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, got.Total)
+
+				assert.ErrorIs(t, repos.User.Delete(ctx, u), Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.User.Update(ctx, u), Errors.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "失敗ケース: 大文字小文字のみが異なるメールアドレスは登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				saveUser(t, ctx, repos, "Alice", "alice@example.com")
+
+				err := repos.User.Save(ctx, newUser("Alice2", "ALICE@example.com"))
+				assert.ErrorIs(t, err, Errors.ErrUniqueConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 論理削除したユーザーのメールアドレスは登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				assert.NoError(t, repos.User.Delete(ctx, u))
+
+				err := repos.User.Save(ctx, newUser("Alice2", "alice@example.com"))
+				assert.ErrorIs(t, err, Errors.ErrUniqueConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 他のユーザーのメールアドレスには更新できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				bob := saveUser(t, ctx, repos, "Bob", "bob@example.com")
+				bob.Email = "alice@example.com"
+
+				assert.ErrorIs(t, repos.User.Update(ctx, bob), Errors.ErrUniqueConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないユーザー",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				missing := newUser("Nobody", "nobody@example.com")
+
+				_, err := repos.User.Detail(ctx, missing)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.User.Update(ctx, missing), Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.User.Delete(ctx, missing), Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testCategoryRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: IDを指定しない場合は採番される",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := &category.Category{Name: "雑談"}
+				assert.NoError(t, repos.Category.Save(ctx, c))
+				assert.True(t, uuid.IsValid(c.ID))
+
+				got, err := repos.Category.Detail(ctx, c)
+				assert.NoError(t, err)
+				assert.Equal(t, "雑談", got.Name)
+			},
+		},
+		{
+			name: "成功ケース: 同じIDで保存すると名前を更新する",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "雑談")
+				c.Name = "ゲーム"
+				assert.NoError(t, repos.Category.Save(ctx, c))
+
+				got, err := repos.Category.List(ctx)
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+				assert.Equal(t, "ゲーム", got[0].Name)
+			},
+		},
+		{
+			name: "成功ケース: 削除するとサブカテゴリも削除し、サマリーのカテゴリを未設定にする",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				sc := saveSubcategory(t, ctx, repos, c.ID, "APEX")
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, c.ID, sc.ID)
+
+				assert.NoError(t, repos.Category.Delete(ctx, c))
+
+				_, err := repos.Category.Detail(ctx, c)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				_, err = repos.Subcategory.Detail(ctx, sc)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+
+				got, err := repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.False(t, got.CategoryID.Valid)
+				assert.False(t, got.SubcategoryID.Valid)
+				assert.Nil(t, got.Category)
+				assert.Nil(t, got.Subcategory)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないカテゴリ",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				missing := &category.Category{WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()}}
+
+				_, err := repos.Category.Detail(ctx, missing)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.Category.Delete(ctx, missing), Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testSubcategoryRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: カテゴリを結合して取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				sc := saveSubcategory(t, ctx, repos, c.ID, "APEX")
+
+				got, err := repos.Subcategory.Detail(ctx, sc)
+				assert.NoError(t, err)
+				assert.Equal(t, "APEX", got.Name)
+				assert.Equal(t, c.ID, got.CategoryID)
+				if assert.NotNil(t, got.Category) {
+					assert.Equal(t, "ゲーム", got.Category.Name)
+				}
+			},
+		},
+		{
+			name: "成功ケース: ロックを取って取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				sc := saveSubcategory(t, ctx, repos, c.ID, "APEX")
+
+				got, err := repos.Subcategory.DetailForShare(ctx, sc)
+				assert.NoError(t, err)
+				assert.Equal(t, "APEX", got.Name)
+				assert.Equal(t, c.ID, got.CategoryID)
+			},
+		},
+		{
+			name: "成功ケース: カテゴリで絞り込める",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				game := saveCategory(t, ctx, repos, "ゲーム")
+				talk := saveCategory(t, ctx, repos, "雑談")
+				saveSubcategory(t, ctx, repos, game.ID, "APEX")
+				saveSubcategory(t, ctx, repos, game.ID, "スプラ")
+				saveSubcategory(t, ctx, repos, talk.ID, "朝活")
+
+				got, err := repos.Subcategory.List(ctx, game.ID)
+				assert.NoError(t, err)
+				assert.Len(t, got, 2)
+
+				got, err = repos.Subcategory.List(ctx, "")
+				assert.NoError(t, err)
+				assert.Len(t, got, 3)
+			},
+		},
+		{
+			name: "成功ケース: 削除するとサマリーのサブカテゴリを未設定にする",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				sc := saveSubcategory(t, ctx, repos, c.ID, "APEX")
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, c.ID, sc.ID)
+
+				assert.NoError(t, repos.Subcategory.Delete(ctx, sc))
+
+				got, err := repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.Equal(t, c.ID, got.CategoryID.String)
+				assert.False(t, got.SubcategoryID.Valid)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないカテゴリには登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				err := repos.Subcategory.Save(ctx, &subcategory.Subcategory{CategoryID: uuid.GenerateID(), Name: "APEX"})
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 同じカテゴリに同じ名前のサブカテゴリは登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				saveSubcategory(t, ctx, repos, c.ID, "APEX")
+
+				err := repos.Subcategory.Save(ctx, &subcategory.Subcategory{CategoryID: c.ID, Name: "APEX"})
+				assert.ErrorIs(t, err, Errors.ErrUniqueConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないサブカテゴリ",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				missing := &subcategory.Subcategory{WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()}}
+
+				_, err := repos.Subcategory.Detail(ctx, missing)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				_, err = repos.Subcategory.DetailForShare(ctx, missing)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.Subcategory.Delete(ctx, missing), Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testSummaryRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: ユーザーとカテゴリを結合して取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				c := saveCategory(t, ctx, repos, "ゲーム")
+				sc := saveSubcategory(t, ctx, repos, c.ID, "APEX")
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, c.ID, sc.ID)
+
+				got, err := repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.Equal(t, s.Title, got.Title)
+				assert.Equal(t, u.ID, got.UserID)
+				if assert.NotNil(t, got.User) {
+					assert.Equal(t, "Alice", got.User.Name)
+				}
+				if assert.NotNil(t, got.Category) {
+					assert.Equal(t, "ゲーム", got.Category.Name)
+				}
+				if assert.NotNil(t, got.Subcategory) {
+					assert.Equal(t, "APEX", got.Subcategory.Name)
+				}
+			},
+		},
+		{
+			name: "成功ケース: カテゴリなしで保存できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, "", "")
+
+				got, err := repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.False(t, got.CategoryID.Valid)
+				assert.Nil(t, got.Category)
+				assert.Nil(t, got.Subcategory)
+			},
+		},
+		{
+			name: "成功ケース: 絞り込みとページネーション",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				game := saveCategory(t, ctx, repos, "ゲーム")
+				apex := saveSubcategory(t, ctx, repos, game.ID, "APEX")
+				splatoon := saveSubcategory(t, ctx, repos, game.ID, "スプラ")
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				saveSummary(t, ctx, repos, u.ID, game.ID, apex.ID)
+				saveSummary(t, ctx, repos, u.ID, game.ID, apex.ID)
+				saveSummary(t, ctx, repos, u.ID, game.ID, splatoon.ID)
+				saveSummary(t, ctx, repos, u.ID, "", "")
+
+				got, err := repos.Summary.List(ctx, summary.ListOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, 4, got.Total)
+				assert.Equal(t, 20, got.Limit)
+				assert.False(t, got.HasNext)
+
+				got, err = repos.Summary.List(ctx, summary.ListOptions{CategoryID: game.ID, Limit: 2})
+				assert.NoError(t, err)
+				assert.Equal(t, 3, got.Total)
+				assert.Len(t, got.Items, 2)
+				assert.True(t, got.HasNext)
+
+				got, err = repos.Summary.List(ctx, summary.ListOptions{CategoryID: game.ID, SubcategoryID: apex.ID})
+				assert.NoError(t, err)
+				assert.Equal(t, 2, got.Total)
+				for _, item := range got.Items {
+					assert.Equal(t, apex.ID, item.SubcategoryID.String)
+				}
+
+				got, err = repos.Summary.List(ctx, summary.ListOptions{Offset: 4})
+				assert.NoError(t, err)
+				assert.Empty(t, got.Items)
+				assert.Equal(t, 4, got.Total)
+			},
+		},
+		{
+			name: "成功ケース: 論理削除したサマリーは取得できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, "", "")
+				assert.NoError(t, repos.Summary.Delete(ctx, s))
+
+				_, err := repos.Summary.Detail(ctx, s)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+
+				got, err := repos.Summary.List(ctx, summary.ListOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, got.Total)
+
+				assert.ErrorIs(t, repos.Summary.Delete(ctx, s), Errors.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "成功ケース: 公開すると公開日時を記録し、二重には公開できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+				s := saveSummary(t, ctx, repos, u.ID, "", "")
+
+				got, err := repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.False(t, got.PublishedAt.Valid)
+
+				assert.NoError(t, repos.Summary.Publish(ctx, s))
+				got, err = repos.Summary.Detail(ctx, s)
+				assert.NoError(t, err)
+				assert.True(t, got.PublishedAt.Valid)
+
+				assert.ErrorIs(t, repos.Summary.Publish(ctx, s), Errors.ErrConflict)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないユーザーのサマリーは登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				err := repos.Summary.Save(ctx, newSummary(uuid.GenerateID(), "", ""))
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないサブカテゴリのサマリーは登録できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "Alice", "alice@example.com")
+
+				err := repos.Summary.Save(ctx, newSummary(u.ID, "", uuid.GenerateID()))
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないサマリー",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				missing := newSummary(uuid.GenerateID(), "", "")
+
+				_, err := repos.Summary.Detail(ctx, missing)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.Summary.Delete(ctx, missing), Errors.ErrRecordNotFound)
+				assert.ErrorIs(t, repos.Summary.Publish(ctx, missing), Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testOutboxRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: 保存したイベントを保存した順に取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				first := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-2*time.Second))
+				second := appendEvent(t, ctx, repos, event.TypeSummaryDeleted, time.Now().Add(-time.Second))
+
+				got, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 2) {
+					assert.Equal(t, first.ID, got[0].ID)
+					assert.Equal(t, event.TypeSummaryCreated, got[0].Type)
+					assert.Equal(t, first.AggregateID, got[0].AggregateID)
+					assert.JSONEq(t, `{"title":"Title"}`, string(got[0].Payload))
+					assert.Equal(t, event.StatusPending, got[0].Status)
+					assert.Equal(t, 0, got[0].Attempts)
+					assert.Equal(t, second.ID, got[1].ID)
+					assert.Less(t, got[0].Seq, got[1].Seq)
+				}
+			},
+		},
+		{
+			name: "成功ケース: 件数の上限を指定できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				first := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-2*time.Second))
+				appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-time.Second))
+
+				got, err := repos.Outbox.Claim(ctx, 1, time.Minute)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, first.ID, got[0].ID)
+				}
+			},
+		},
+		{
+			name: "成功ケース: 取得したイベントはリースの間取得されない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-time.Second))
+
+				got, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+
+				got, err = repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+			},
+		},
+		{
+			name: "成功ケース: ListAfter は配信状態に関わらず指定した Seq より後のイベントを取得する",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				last, err := repos.Outbox.LastSeq(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(0), last)
+
+				first := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-2*time.Second))
+				second := appendEvent(t, ctx, repos, event.TypeSummaryUpdated, time.Now().Add(-time.Second))
+				third := appendEvent(t, ctx, repos, event.TypeSummaryDeleted, time.Now())
+				assert.NoError(t, repos.Outbox.MarkDelivered(ctx, first.ID))
+				_, err = repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+
+				all, err := repos.Outbox.ListAfter(ctx, 0, 10)
+				assert.NoError(t, err)
+				if !assert.Len(t, all, 3) {
+					return
+				}
+				assert.Equal(t, first.ID, all[0].ID)
+				assert.JSONEq(t, `{"title":"Title"}`, string(all[0].Payload))
+
+				got, err := repos.Outbox.ListAfter(ctx, all[0].Seq, 1)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, second.ID, got[0].ID)
+				}
+
+				last, err = repos.Outbox.LastSeq(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, all[2].Seq, last)
+				assert.Equal(t, third.ID, all[2].ID)
+
+				got, err = repos.Outbox.ListAfter(ctx, last, 10)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+			},
+		},
+		{
+			name: "成功ケース: 配信済みのイベントは取得されない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				e := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-time.Second))
+				assert.NoError(t, repos.Outbox.MarkDelivered(ctx, e.ID))
+
+				got, err := repos.Outbox.Claim(ctx, 10, 0)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+			},
+		},
+		{
+			name: "成功ケース: 失敗したイベントは次の配信予定時刻を過ぎると再度取得される",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				e := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-time.Second))
+				got, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				if !assert.Len(t, got, 1) {
+					return
+				}
+
+				rec := got[0]
+				rec.Attempts = 1
+				rec.LastError = "subscriber failed"
+				rec.NextAttemptAt = time.Now().Add(time.Hour)
+				assert.NoError(t, repos.Outbox.MarkFailed(ctx, rec))
+				got, err = repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				rec.NextAttemptAt = time.Now().Add(-time.Second)
+				assert.NoError(t, repos.Outbox.MarkFailed(ctx, rec))
+				got, err = repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, e.ID, got[0].ID)
+					assert.Equal(t, 1, got[0].Attempts)
+					assert.Equal(t, "subscriber failed", got[0].LastError)
+				}
+			},
+		},
+		{
+			name: "成功ケース: デッドレターになったイベントは取得されない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				e := appendEvent(t, ctx, repos, event.TypeSummaryCreated, time.Now().Add(-time.Second))
+				rec := &event.Record{Event: *e, Status: event.StatusDead, Attempts: 5, LastError: "gave up", NextAttemptAt: time.Now().Add(-time.Second)}
+				assert.NoError(t, repos.Outbox.MarkFailed(ctx, rec))
+
+				got, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testWebhookRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: 保存したWebhookを取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated, event.TypeSummaryDeleted)
+
+				got, err := repos.Webhook.Detail(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: w.ID}})
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) {
+					assert.Equal(t, u.ID, got.UserID)
+					assert.Equal(t, "https://example.com/hook", got.URL)
+					assert.Equal(t, "secret", got.Secret)
+					assert.Equal(t, []event.Type{event.TypeSummaryCreated, event.TypeSummaryDeleted}, got.EventTypes)
+					assert.True(t, got.Active)
+				}
+			},
+		},
+		{
+			name: "成功ケース: 同じIDで保存すると登録したユーザー以外が更新される",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				other := saveUser(t, ctx, repos, "other", "other@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+
+				w.UserID = other.ID
+				w.URL = "https://example.com/updated"
+				w.Secret = "rotated"
+				w.EventTypes = []event.Type{event.TypeCategoryCreated}
+				w.Active = false
+				assert.NoError(t, repos.Webhook.Save(ctx, w))
+
+				got, err := repos.Webhook.Detail(ctx, w)
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) {
+					assert.Equal(t, u.ID, got.UserID)
+					assert.Equal(t, "https://example.com/updated", got.URL)
+					assert.Equal(t, "rotated", got.Secret)
+					assert.Equal(t, []event.Type{event.TypeCategoryCreated}, got.EventTypes)
+					assert.False(t, got.Active)
+				}
+			},
+		},
+		{
+			name: "成功ケース: ユーザーで絞り込める",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				other := saveUser(t, ctx, repos, "other", "other@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				saveWebhook(t, ctx, repos, other.ID, event.TypeSummaryCreated)
+
+				got, err := repos.Webhook.List(ctx, u.ID)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, w.ID, got[0].ID)
+				}
+
+				got, err = repos.Webhook.List(ctx, "")
+				assert.NoError(t, err)
+				assert.Len(t, got, 2)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないユーザーのWebhookは保存できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				w := newWebhook(uuid.GenerateID(), event.TypeSummaryCreated)
+				err := repos.Webhook.Save(ctx, w)
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+			},
+		},
+		{
+			name: "成功ケース: 削除すると配信履歴も削除される",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				d := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				assert.NoError(t, repos.Webhook.Delete(ctx, w))
+
+				_, err := repos.Webhook.Detail(ctx, w)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+				_, err = repos.Delivery.Detail(ctx, d)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないWebhookは削除できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				err := repos.Webhook.Delete(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()}})
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testWebhookDeliveryRepository(t *testing.T, setup SetupFunc) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: 保存した配信を取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				d := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				got, err := repos.Delivery.Detail(ctx, &webhook.Delivery{ID: d.ID})
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) {
+					assert.Equal(t, w.ID, got.WebhookID)
+					assert.Equal(t, d.EventID, got.EventID)
+					assert.Equal(t, event.TypeSummaryCreated, got.EventType)
+					assert.JSONEq(t, `{"id":"1"}`, string(got.Payload))
+					assert.Equal(t, d.EventID, got.DedupKey)
+					assert.Equal(t, webhook.DeliveryStatusPending, got.Status)
+					assert.Equal(t, 0, got.Attempts)
+					assert.Nil(t, got.DeliveredAt)
+				}
+			},
+		},
+		{
+			name: "失敗ケース: 同じWebhookに同じキーの配信は保存できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				other := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				d := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				err := repos.Delivery.Create(ctx, newDelivery(w.ID, d.EventID))
+				assert.ErrorIs(t, err, Errors.ErrUniqueConstraint)
+
+				// 別のWebhookであれば同じキーでも保存できる
+				assert.NoError(t, repos.Delivery.Create(ctx, newDelivery(other.ID, d.EventID)))
+			},
+		},
+		{
+			name: "成功ケース: 再送はキーなしで何度でも保存できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				d := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				for range 2 {
+					redelivery := newDelivery(w.ID, "")
+					redelivery.EventID = d.EventID
+					redelivery.RedeliveryOf = d.ID
+					assert.NoError(t, repos.Delivery.Create(ctx, redelivery))
+				}
+
+				got, err := repos.Delivery.List(ctx, w.ID, 10, 0)
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) && assert.Len(t, got.Items, 3) {
+					assert.Equal(t, d.ID, got.Items[0].RedeliveryOf)
+				}
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないWebhookの配信は保存できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				err := repos.Delivery.Create(ctx, newDelivery(uuid.GenerateID(), uuid.GenerateID()))
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+			},
+		},
+		{
+			name: "成功ケース: 配信履歴を新しい順にページングして取得できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				first := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+				second := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+				third := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				got, err := repos.Delivery.List(ctx, w.ID, 2, 0)
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) && assert.Len(t, got.Items, 2) {
+					assert.Equal(t, third.ID, got.Items[0].ID)
+					assert.Equal(t, second.ID, got.Items[1].ID)
+					assert.Equal(t, 3, got.Total)
+					assert.True(t, got.HasNext)
+				}
+
+				got, err = repos.Delivery.List(ctx, w.ID, 2, 2)
+				assert.NoError(t, err)
+				if assert.NotNil(t, got) && assert.Len(t, got.Items, 1) {
+					assert.Equal(t, first.ID, got.Items[0].ID)
+					assert.False(t, got.HasNext)
+				}
+			},
+		},
+		{
+			name: "成功ケース: 取得した配信はリースの間取得されない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				first := createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+				createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				got, err := repos.Delivery.Claim(ctx, 1, time.Minute)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, first.ID, got[0].ID)
+				}
+
+				got, err = repos.Delivery.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Len(t, got, 1)
+
+				got, err = repos.Delivery.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+			},
+		},
+		{
+			name: "成功ケース: 送信の結果を保存できる",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				u := saveUser(t, ctx, repos, "user", "user@example.com")
+				w := saveWebhook(t, ctx, repos, u.ID, event.TypeSummaryCreated)
+				createDelivery(t, ctx, repos, w.ID, uuid.GenerateID())
+
+				got, err := repos.Delivery.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				if !assert.Len(t, got, 1) {
+					return
+				}
+
+				// 失敗して再試行を待つ配信は、次の配信予定時刻を過ぎると再度取得される
+				d := got[0]
+				d.Attempts = 1
+				d.ResponseStatus = 500
+				d.LastError = "unexpected status code: 500"
+				d.NextAttemptAt = time.Now().Add(-time.Second)
+				assert.NoError(t, repos.Delivery.UpdateResult(ctx, d))
+
+				got, err = repos.Delivery.Claim(ctx, 10, time.Minute)
+				assert.NoError(t, err)
+				if assert.Len(t, got, 1) {
+					assert.Equal(t, 1, got[0].Attempts)
+					assert.Equal(t, 500, got[0].ResponseStatus)
+					assert.Equal(t, "unexpected status code: 500", got[0].LastError)
+				}
+
+				// 送信に成功した配信は取得されない
+				deliveredAt := time.Now().UTC().Truncate(time.Microsecond)
+				d.Status = webhook.DeliveryStatusSucceeded
+				d.Attempts = 2
+				d.ResponseStatus = 200
+				d.LastError = ""
+				d.NextAttemptAt = time.Now().Add(-time.Second)
+				d.DeliveredAt = &deliveredAt
+				assert.NoError(t, repos.Delivery.UpdateResult(ctx, d))
+
+				got, err = repos.Delivery.Claim(ctx, 10, 0)
+				assert.NoError(t, err)
+				assert.Empty(t, got)
+
+				detail, err := repos.Delivery.Detail(ctx, d)
+				assert.NoError(t, err)
+				if assert.NotNil(t, detail) {
+					assert.Equal(t, webhook.DeliveryStatusSucceeded, detail.Status)
+					assert.Equal(t, 2, detail.Attempts)
+					if assert.NotNil(t, detail.DeliveredAt) {
+						assert.WithinDuration(t, deliveredAt, *detail.DeliveredAt, time.Millisecond)
+					}
+				}
+			},
+		},
+		{
+			name: "失敗ケース: 存在しない配信の結果は保存できない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				d := newDelivery(uuid.GenerateID(), "")
+				d.ID = uuid.GenerateID()
+				d.Status = webhook.DeliveryStatusDead
+				err := repos.Delivery.UpdateResult(ctx, d)
+				assert.ErrorIs(t, err, Errors.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func testRateLimitStore(t *testing.T, setup SetupFunc) {
+	limit := ratelimit.Limit{Requests: 3, Period: time.Minute}
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, repos Repositories)
+	}{
+		{
+			name: "成功ケース: 上限の回数まで許可し、超えたら拒否する",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				for i := range 3 {
+					result, err := repos.RateLimit.Take(ctx, "list:ip:192.0.2.1", limit)
+					assert.NoError(t, err)
+					assert.True(t, result.Allowed)
+					assert.Equal(t, 3, result.Limit)
+					assert.Equal(t, 2-i, result.Remaining)
+				}
+
+				result, err := repos.RateLimit.Take(ctx, "list:ip:192.0.2.1", limit)
+				assert.NoError(t, err)
+				assert.False(t, result.Allowed)
+				assert.Equal(t, 0, result.Remaining)
+				assert.InDelta(t, 20*time.Second, result.RetryAfter, float64(time.Second))
+			},
+		},
+		{
+			name: "成功ケース: キーごとに数える",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				for range 3 {
+					_, err := repos.RateLimit.Take(ctx, "list:ip:192.0.2.1", limit)
+					assert.NoError(t, err)
+				}
+
+				result, err := repos.RateLimit.Take(ctx, "list:ip:192.0.2.2", limit)
+				assert.NoError(t, err)
+				assert.True(t, result.Allowed)
+				result, err = repos.RateLimit.Take(ctx, "write:ip:192.0.2.1", limit)
+				assert.NoError(t, err)
+				assert.True(t, result.Allowed)
+			},
+		},
+		{
+			name: "成功ケース: 同時に消費しても上限を超えて許可しない",
+			fn: func(t *testing.T, ctx context.Context, repos Repositories) {
+				var (
+					wg      sync.WaitGroup
+					allowed atomic.Int32
+				)
+				for range 10 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						result, err := repos.RateLimit.Take(ctx, "list:ip:192.0.2.1", limit)
+						if err == nil && result.Allowed {
+							allowed.Add(1)
+						}
+					}()
+				}
+				wg.Wait()
+				assert.LessOrEqual(t, allowed.Load(), int32(3))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repos := setup(t)
+			if repos.RateLimit == nil {
+				t.Skip("rate limit store is not set")
+			}
+			tt.fn(t, ctx, repos)
+		})
+	}
+}
+
+func newWebhook(userID string, types ...event.Type) *webhook.Webhook {
+	return &webhook.Webhook{
+		WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()},
+		UserID:       userID,
+		URL:          "https://example.com/hook",
+		Secret:       "secret",
+		EventTypes:   types,
+		Active:       true,
+	}
+}
+
+func saveWebhook(t *testing.T, ctx context.Context, repos Repositories, userID string, types ...event.Type) *webhook.Webhook {
+	t.Helper()
+	w := newWebhook(userID, types...)
+	if err := repos.Webhook.Save(ctx, w); err != nil {
+		t.Fatalf("failed to save webhook: %v", err)
+	}
+	return w
+}
+
+// newDelivery は配信予定時刻を過去にした配信を返します
+func newDelivery(webhookID, dedupKey string) *webhook.Delivery {
+	eventID := dedupKey
+	if eventID == "" {
+		eventID = uuid.GenerateID()
+	}
+	return &webhook.Delivery{
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     event.TypeSummaryCreated,
+		Payload:       []byte(`{"id":"1"}`),
+		DedupKey:      dedupKey,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	}
+}
+
+func createDelivery(t *testing.T, ctx context.Context, repos Repositories, webhookID, dedupKey string) *webhook.Delivery {
+	t.Helper()
+	d := newDelivery(webhookID, dedupKey)
+	if err := repos.Delivery.Create(ctx, d); err != nil {
+		t.Fatalf("failed to create webhook delivery: %v", err)
+	}
+	return d
+}
+
+func appendEvent(t *testing.T, ctx context.Context, repos Repositories, typ event.Type, occurredAt time.Time) *event.Event {
+	t.Helper()
+	e, err := event.New(typ, uuid.GenerateID(), map[string]string{"title": "Title"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	e.OccurredAt = occurredAt.UTC().Truncate(time.Microsecond)
+	if !assert.NoError(t, repos.Outbox.Append(ctx, e)) {
+		t.FailNow()
+	}
+	return e
+}
+
+func newUser(name, email string) *user.User {
+	return &user.User{
+		WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()},
+		Name:         name,
+		Email:        email,
+		UserType:     "admin",
+	}
+}
+
+func saveUser(t *testing.T, ctx context.Context, repos Repositories, name, email string) *user.User {
+	t.Helper()
+	u := newUser(name, email)
+	if err := repos.User.Save(ctx, u); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	return u
+}
+
+func saveCategory(t *testing.T, ctx context.Context, repos Repositories, name string) *category.Category {
+	t.Helper()
+	c := &category.Category{Name: name}
+	if err := repos.Category.Save(ctx, c); err != nil {
+		t.Fatalf("failed to save category: %v", err)
+	}
+	return c
+}
+
+func saveSubcategory(t *testing.T, ctx context.Context, repos Repositories, categoryID, name string) *subcategory.Subcategory {
+	t.Helper()
+	sc := &subcategory.Subcategory{CategoryID: categoryID, Name: name}
+	if err := repos.Subcategory.Save(ctx, sc); err != nil {
+		t.Fatalf("failed to save subcategory: %v", err)
+	}
+	return sc
+}
+
+// newSummary は空文字のカテゴリIDとサブカテゴリIDを未設定として扱います
+func newSummary(userID, categoryID, subcategoryID string) *summary.Summary {
+	id := uuid.GenerateID()
+	return &summary.Summary{
+		WYHBaseModel:  domain.WYHBaseModel{ID: id},
+		Title:         "title-" + strings.Split(id, "-")[0],
+		Description:   "description",
+		Content:       "content",
+		CategoryID:    sql.NullString{String: categoryID, Valid: categoryID != ""},
+		SubcategoryID: sql.NullString{String: subcategoryID, Valid: subcategoryID != ""},
+		UserID:        userID,
+	}
+}
+
+func saveSummary(t *testing.T, ctx context.Context, repos Repositories, userID, categoryID, subcategoryID string) *summary.Summary {
+	t.Helper()
+	s := newSummary(userID, categoryID, subcategoryID)
+	if err := repos.Summary.Save(ctx, s); err != nil {
+		t.Fatalf("failed to save summary: %v", err)
+	}
+	return s
+}
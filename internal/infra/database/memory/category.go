@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type categoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) category.ICategoryRepository {
+	return &categoryRepository{store: store}
+}
+
+// Save はカテゴリを登録し、同じIDのカテゴリが存在する場合は名前を更新します
+func (r *categoryRepository) Save(ctx context.Context, model *category.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+
+	for _, c := range r.store.categories {
+		if c.ID != model.ID && c.Name == model.Name {
+			return fmt.Errorf("failed to save category: %w", Errors.ErrUniqueConstraint)
+		}
+	}
+
+	t := now()
+	c := category.Category{Name: model.Name}
+	c.ID = model.ID
+	c.CreatedAt = t
+	c.UpdatedAt = t
+	if current, ok := r.store.categories[model.ID]; ok {
+		c.CreatedAt = current.CreatedAt
+	}
+	r.store.categories[c.ID] = &c
+
+	return nil
+}
+
+func (r *categoryRepository) List(ctx context.Context) (category.CategorySlice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var categories category.CategorySlice
+	for _, c := range r.store.categories {
+		result := *c
+		categories = append(categories, &result)
+	}
+	sortByCreatedAtDesc(categories, func(c *category.Category) time.Time { return c.CreatedAt }, func(c *category.Category) string { return c.ID })
+
+	return categories, nil
+}
+
+func (r *categoryRepository) Detail(ctx context.Context, model *category.Category) (*category.Category, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	c, ok := r.store.categories[model.ID]
+	if !ok {
+		return nil, fmt.Errorf("category not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	result := *c
+	return &result, nil
+}
+
+// Delete はカテゴリを物理削除します
+// 外部キー制約と同じく、サブカテゴリは削除し、サマリーのカテゴリとサブカテゴリは未設定にします
+func (r *categoryRepository) Delete(ctx context.Context, model *category.Category) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.categories[model.ID]; !ok {
+		return fmt.Errorf("category not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	for id, sc := range r.store.subcategories {
+		if sc.CategoryID == model.ID {
+			r.store.deleteSubcategory(id)
+		}
+	}
+	for id, s := range r.store.summaries {
+		if s.CategoryID.Valid && s.CategoryID.String == model.ID {
+			c := *s
+			c.CategoryID.String, c.CategoryID.Valid = "", false
+			r.store.summaries[id] = &c
+		}
+	}
+	delete(r.store.categories, model.ID)
+
+	return nil
+}
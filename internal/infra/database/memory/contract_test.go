@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/contract"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) (context.Context, contract.Repositories) {
+		store := NewStore()
+		return context.Background(), contract.Repositories{
+			User:        NewUserRepository(store),
+			Summary:     NewSummaryRepository(store),
+			Category:    NewCategoryRepository(store),
+			Subcategory: NewSubcategoryRepository(store),
+			Outbox:      NewOutboxRepository(store),
+			Webhook:     NewWebhookRepository(store),
+			Delivery:    NewWebhookDeliveryRepository(store),
+			RateLimit:   ratelimit.NewMemoryStore(),
+		}
+	})
+}
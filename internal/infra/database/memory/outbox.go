@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type outboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) event.IOutboxRepository {
+	return &outboxRepository{store: store}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...*event.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, e := range events {
+		if e.ID == "" {
+			e.ID = uuid.GenerateID()
+		}
+		if e.OccurredAt.IsZero() {
+			e.OccurredAt = now().UTC()
+		}
+		r.store.outboxSeq++
+		r.store.outbox[e.ID] = &event.Record{
+			Event:         *e,
+			Seq:           r.store.outboxSeq,
+			Status:        event.StatusPending,
+			NextAttemptAt: e.OccurredAt,
+		}
+	}
+	return nil
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*event.Record, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current := now()
+	var due []*event.Record
+	for _, rec := range r.store.outbox {
+		if rec.Status == event.StatusPending && !rec.NextAttemptAt.After(current) {
+			due = append(due, rec)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Seq < due[j].Seq })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	records := make([]*event.Record, 0, len(due))
+	for _, rec := range due {
+		claimed := *rec
+		claimed.NextAttemptAt = current.Add(lease)
+		r.store.outbox[rec.ID] = &claimed
+
+		out := claimed
+		records = append(records, &out)
+	}
+	return records, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rec, ok := r.store.outbox[id]
+	if !ok {
+		return fmt.Errorf("event not found: %w", Errors.ErrRecordNotFound)
+	}
+	delivered := *rec
+	deliveredAt := now()
+	delivered.Status = event.StatusDelivered
+	delivered.DeliveredAt = &deliveredAt
+	r.store.outbox[id] = &delivered
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, record *event.Record) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rec, ok := r.store.outbox[record.ID]
+	if !ok {
+		return fmt.Errorf("event not found: %w", Errors.ErrRecordNotFound)
+	}
+	failed := *rec
+	failed.Status = record.Status
+	failed.Attempts = record.Attempts
+	failed.LastError = record.LastError
+	failed.NextAttemptAt = record.NextAttemptAt
+	r.store.outbox[record.ID] = &failed
+	return nil
+}
+
+func (r *outboxRepository) ListAfter(ctx context.Context, seq int64, limit int) ([]*event.Record, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var records []*event.Record
+	for _, rec := range r.store.outbox {
+		if rec.Seq > seq {
+			out := *rec
+			records = append(records, &out)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Seq < records[j].Seq })
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+func (r *outboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.outboxSeq, nil
+}
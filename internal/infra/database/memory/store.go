@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+)
+
+// Store はインメモリのリポジトリが共有するデータストアです
+// テーブルをまたぐ結合や外部キー制約を再現するため、すべてのリポジトリで1つの Store を共有します
+// MySQLを使わないテストやローカルのデモ用で、データはプロセスの終了とともに失われます
+type Store struct {
+	mu sync.RWMutex
+	// txMu はトランザクションを直列に実行するためのロックです
+	txMu sync.Mutex
+
+	users         map[string]*user.User
+	summaries     map[string]*summary.Summary
+	categories    map[string]*category.Category
+	subcategories map[string]*subcategory.Subcategory
+	outbox        map[string]*event.Record
+	// outboxSeq はアウトボックスの AUTO_INCREMENT に相当する番号です
+	outboxSeq  int64
+	webhooks   map[string]*webhook.Webhook
+	deliveries map[string]*webhook.Delivery
+	// deliverySeq は配信履歴の AUTO_INCREMENT に相当する番号です
+	deliverySeq int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:         map[string]*user.User{},
+		summaries:     map[string]*summary.Summary{},
+		categories:    map[string]*category.Category{},
+		subcategories: map[string]*subcategory.Subcategory{},
+		outbox:        map[string]*event.Record{},
+		webhooks:      map[string]*webhook.Webhook{},
+		deliveries:    map[string]*webhook.Delivery{},
+	}
+}
+
+// snapshot はトランザクションのロールバック用に現在のデータを保存したものです
+// レコードは更新時に常に新しい値で置き換えるため、マップのコピーだけで復元できます
+type snapshot struct {
+	users         map[string]*user.User
+	summaries     map[string]*summary.Summary
+	categories    map[string]*category.Category
+	subcategories map[string]*subcategory.Subcategory
+	outbox        map[string]*event.Record
+	webhooks      map[string]*webhook.Webhook
+	deliveries    map[string]*webhook.Delivery
+}
+
+func (s *Store) snapshot() snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return snapshot{
+		users:         maps.Clone(s.users),
+		summaries:     maps.Clone(s.summaries),
+		categories:    maps.Clone(s.categories),
+		subcategories: maps.Clone(s.subcategories),
+		outbox:        maps.Clone(s.outbox),
+		webhooks:      maps.Clone(s.webhooks),
+		deliveries:    maps.Clone(s.deliveries),
+	}
+}
+
+func (s *Store) restore(snap snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = snap.users
+	s.summaries = snap.summaries
+	s.categories = snap.categories
+	s.subcategories = snap.subcategories
+	s.outbox = snap.outbox
+	s.webhooks = snap.webhooks
+	s.deliveries = snap.deliveries
+}
+
+// now はMySQLの CURRENT_TIMESTAMP(6) に合わせてマイクロ秒に丸めた現在時刻を返します
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// sortByCreatedAtDesc は ORDER BY created_at DESC に相当する並び替えを行います
+// 作成日時が同じ場合はIDの順に並べ、結果が毎回同じになるようにします
+func sortByCreatedAtDesc[T any](items []T, createdAt func(T) time.Time, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ci, cj := createdAt(items[i]), createdAt(items[j])
+		if !ci.Equal(cj) {
+			return ci.After(cj)
+		}
+		return id(items[i]) < id(items[j])
+	})
+}
+
+// paginate は LIMIT / OFFSET を適用し、次のページがあるかを返します
+func paginate[T any](items []T, limit, offset int) ([]T, bool) {
+	if offset >= len(items) {
+		return nil, false
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
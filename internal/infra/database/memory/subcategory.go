@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type subcategoryRepository struct {
+	store *Store
+}
+
+func NewSubcategoryRepository(store *Store) subcategory.ISubcategoryRepository {
+	return &subcategoryRepository{store: store}
+}
+
+// Save はサブカテゴリを登録し、同じIDのサブカテゴリが存在する場合は名前を更新します
+// MySQLの実装と同じく、登録後にカテゴリは変更しません
+func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Subcategory) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+
+	sc := subcategory.Subcategory{CategoryID: model.CategoryID, Name: model.Name}
+	sc.ID = model.ID
+	sc.CreatedAt = now()
+	sc.UpdatedAt = sc.CreatedAt
+	if current, ok := r.store.subcategories[model.ID]; ok {
+		sc.CategoryID = current.CategoryID
+		sc.CreatedAt = current.CreatedAt
+	} else if _, ok := r.store.categories[sc.CategoryID]; !ok {
+		return fmt.Errorf("failed to save subcategory: %w", Errors.ErrForeignKeyConstraint)
+	}
+
+	// カテゴリ内でのサブカテゴリ名の重複チェック
+	for _, other := range r.store.subcategories {
+		if other.ID != sc.ID && other.CategoryID == sc.CategoryID && other.Name == sc.Name {
+			return fmt.Errorf("failed to save subcategory: %w", Errors.ErrUniqueConstraint)
+		}
+	}
+	r.store.subcategories[sc.ID] = &sc
+
+	return nil
+}
+
+func (r *subcategoryRepository) List(ctx context.Context, categoryID string) (subcategory.SubcategorySlice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var subcategories subcategory.SubcategorySlice
+	for _, sc := range r.store.subcategories {
+		if categoryID != "" && sc.CategoryID != categoryID {
+			continue
+		}
+		result, ok := r.store.joinSubcategory(sc)
+		if !ok {
+			continue
+		}
+		subcategories = append(subcategories, result)
+	}
+	sortByCreatedAtDesc(subcategories, func(sc *subcategory.Subcategory) time.Time { return sc.CreatedAt }, func(sc *subcategory.Subcategory) string { return sc.ID })
+
+	return subcategories, nil
+}
+
+func (r *subcategoryRepository) Detail(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sc, ok := r.store.subcategories[model.ID]
+	if !ok {
+		return nil, fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound)
+	}
+	result, ok := r.store.joinSubcategory(sc)
+	if !ok {
+		return nil, fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	return result, nil
+}
+
+// DetailForShare は Detail と同じです
+// トランザクション同士は直列に実行するため、コミットまでに他のトランザクションがサブカテゴリを変更することはありません
+func (r *subcategoryRepository) DetailForShare(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	return r.Detail(ctx, model)
+}
+
+// Delete はサブカテゴリを物理削除し、サマリーのサブカテゴリを未設定にします
+func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.Subcategory) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.subcategories[model.ID]; !ok {
+		return fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound)
+	}
+	r.store.deleteSubcategory(model.ID)
+
+	return nil
+}
+
+// joinSubcategory は subcategories と categories の INNER JOIN に相当します
+// 呼び出し元で mu のロックを取得している必要があります
+func (s *Store) joinSubcategory(sc *subcategory.Subcategory) (*subcategory.Subcategory, bool) {
+	c, ok := s.categories[sc.CategoryID]
+	if !ok {
+		return nil, false
+	}
+	result := *sc
+	cat := *c
+	result.Category = &cat
+	return &result, true
+}
+
+// deleteSubcategory はサブカテゴリを削除し、参照しているサマリーのサブカテゴリを未設定にします
+// 呼び出し元で mu のロックを取得している必要があります
+func (s *Store) deleteSubcategory(id string) {
+	for summaryID, sm := range s.summaries {
+		if sm.SubcategoryID.Valid && sm.SubcategoryID.String == id {
+			c := *sm
+			c.SubcategoryID.String, c.SubcategoryID.Valid = "", false
+			s.summaries[summaryID] = &c
+		}
+	}
+	delete(s.subcategories, id)
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
+
+type summaryRepository struct {
+	store *Store
+}
+
+func NewSummaryRepository(store *Store) summary.ISummaryRepository {
+	return &summaryRepository{store: store}
+}
+
+func (r *summaryRepository) Save(ctx context.Context, model *summary.Summary) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.summaries[model.ID]; ok {
+		return fmt.Errorf("failed to save summary: %w", Errors.ErrUniqueConstraint)
+	}
+
+	// 外部キー制約のチェック
+	// MySQLと同じく、論理削除済みのユーザーも参照できます
+	if _, ok := r.store.users[model.UserID]; !ok {
+		return fmt.Errorf("failed to save summary: %w", Errors.ErrForeignKeyConstraint)
+	}
+	if model.CategoryID.Valid {
+		if _, ok := r.store.categories[model.CategoryID.String]; !ok {
+			return fmt.Errorf("failed to save summary: %w", Errors.ErrForeignKeyConstraint)
+		}
+	}
+	if model.SubcategoryID.Valid {
+		if _, ok := r.store.subcategories[model.SubcategoryID.String]; !ok {
+			return fmt.Errorf("failed to save summary: %w", Errors.ErrForeignKeyConstraint)
+		}
+	}
+
+	t := now()
+	s := *model
+	s.CreatedAt = t
+	s.UpdatedAt = t
+	s.DeletedAt = sql.NullTime{}
+	s.PublishedAt = sql.NullTime{}
+	s.User, s.Category, s.Subcategory = nil, nil, nil
+	r.store.summaries[s.ID] = &s
+
+	return nil
+}
+
+// List はサマリーの一覧を取得します
+// Category はサマリーに紐づくカテゴリの名前で絞り込みます
+func (r *summaryRepository) List(ctx context.Context, opts summary.ListOptions) (*summary.ListResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// デフォルト値の設定
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	var summaries summary.SummarySlice
+	for _, s := range r.store.summaries {
+		if s.DeletedAt.Valid {
+			continue
+		}
+		result := r.store.joinSummary(s)
+		if opts.Category != "" && (result.Category == nil || result.Category.Name != opts.Category) {
+			continue
+		}
+		if opts.CategoryID != "" && s.CategoryID.String != opts.CategoryID {
+			continue
+		}
+		if opts.SubcategoryID != "" && s.SubcategoryID.String != opts.SubcategoryID {
+			continue
+		}
+		summaries = append(summaries, result)
+	}
+	sortByCreatedAtDesc(summaries, func(s *summary.Summary) time.Time { return s.CreatedAt }, func(s *summary.Summary) string { return s.ID })
+
+	total := len(summaries)
+	summaries, hasNext := paginate(summaries, opts.Limit, opts.Offset)
+
+	return &summary.ListResult{
+		Items:   summaries,
+		Total:   total,
+		Limit:   opts.Limit,
+		Offset:  opts.Offset,
+		HasNext: hasNext,
+	}, nil
+}
+
+func (r *summaryRepository) Detail(ctx context.Context, model *summary.Summary) (*summary.Summary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.summaries[model.ID]
+	if !ok || s.DeletedAt.Valid {
+		return nil, fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	return r.store.joinSummary(s), nil
+}
+
+func (r *summaryRepository) Delete(ctx context.Context, model *summary.Summary) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.summaries[model.ID]
+	if !ok || current.DeletedAt.Valid {
+		return fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	s := *current
+	s.DeletedAt = sql.NullTime{Time: now(), Valid: true}
+	r.store.summaries[s.ID] = &s
+
+	return nil
+}
+
+func (r *summaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.summaries[model.ID]
+	if !ok || current.DeletedAt.Valid {
+		return fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
+	}
+	if current.PublishedAt.Valid {
+		return fmt.Errorf("summary already published: %w", Errors.ErrConflict)
+	}
+
+	t := now()
+	s := *current
+	s.PublishedAt = sql.NullTime{Time: t, Valid: true}
+	s.UpdatedAt = t
+	r.store.summaries[s.ID] = &s
+
+	return nil
+}
+
+// joinSummary はユーザー、カテゴリ、サブカテゴリの LEFT JOIN に相当します
+// ユーザーは論理削除済みの場合は結合しません
+// 呼び出し元で mu のロックを取得している必要があります
+func (s *Store) joinSummary(sm *summary.Summary) *summary.Summary {
+	result := *sm
+
+	if u, ok := s.users[sm.UserID]; ok && !u.DeletedAt.Valid {
+		c := *u
+		result.User = &c
+	}
+	if sm.CategoryID.Valid {
+		if cat, ok := s.categories[sm.CategoryID.String]; ok {
+			c := *cat
+			result.Category = &c
+		}
+	}
+	if sm.SubcategoryID.Valid {
+		if sc, ok := s.subcategories[sm.SubcategoryID.String]; ok {
+			c := *sc
+			result.Subcategory = &c
+		}
+	}
+
+	return &result
+}
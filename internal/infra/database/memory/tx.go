@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+)
+
+type txKey struct{}
+
+type txManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) domain.ITxManager {
+	return &txManager{store: store}
+}
+
+// WithinTx は fn 実行前のデータを保存し、fn がエラーを返した場合やパニックした場合に元に戻します
+// トランザクション同士は直列に実行しますが、トランザクション外の書き込みとは分離しないため、
+// ロールバック時にはトランザクション中に行われたトランザクション外の書き込みも取り消されます
+// 分離レベルなどのオプションは無視します
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...domain.TxOption) error {
+	// ネストしたトランザクションはロックを取り直さず、セーブポイントと同じく内側の変更だけを元に戻す
+	runAfterCommit := func() {}
+	if ctx.Value(txKey{}) == nil {
+		m.store.txMu.Lock()
+		defer m.store.txMu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, struct{}{})
+		ctx, runAfterCommit = Ctx.WithAfterCommit(ctx)
+	}
+
+	snap := m.store.snapshot()
+	defer func() {
+		if p := recover(); p != nil {
+			m.store.restore(snap)
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		m.store.restore(snap)
+		return err
+	}
+	runAfterCommit()
+	return nil
+}
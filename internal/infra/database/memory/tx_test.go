@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxManager_WithinTx(t *testing.T) {
+	errFn := errors.New("fn error")
+
+	tests := []struct {
+		name      string
+		fn        func(ctx context.Context, txm txManager, repo category.ICategoryRepository) error
+		wantErr   error
+		wantNames []string
+	}{
+		{
+			name: "成功ケース: エラーがなければ変更が残る",
+			fn: func(ctx context.Context, txm txManager, repo category.ICategoryRepository) error {
+				return repo.Save(ctx, &category.Category{Name: "雑談"})
+			},
+			wantNames: []string{"既存", "雑談"},
+		},
+		{
+			name: "成功ケース: ネストしたトランザクションの失敗は内側の変更だけを元に戻す",
+			fn: func(ctx context.Context, txm txManager, repo category.ICategoryRepository) error {
+				if err := repo.Save(ctx, &category.Category{Name: "雑談"}); err != nil {
+					return err
+				}
+				_ = txm.WithinTx(ctx, func(ctx context.Context) error {
+					if err := repo.Save(ctx, &category.Category{Name: "ゲーム"}); err != nil {
+						return err
+					}
+					return errFn
+				})
+				return nil
+			},
+			wantNames: []string{"既存", "雑談"},
+		},
+		{
+			name: "失敗ケース: エラーの場合は変更を元に戻す",
+			fn: func(ctx context.Context, txm txManager, repo category.ICategoryRepository) error {
+				if err := repo.Save(ctx, &category.Category{Name: "雑談"}); err != nil {
+					return err
+				}
+				if err := repo.Delete(ctx, &category.Category{}); err != nil {
+					return err
+				}
+				return nil
+			},
+			wantErr:   Errors.ErrRecordNotFound,
+			wantNames: []string{"既存"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewStore()
+			repo := NewCategoryRepository(store)
+			txm := txManager{store: store}
+			assert.NoError(t, repo.Save(ctx, &category.Category{Name: "既存"}))
+
+			err := txm.WithinTx(ctx, func(ctx context.Context) error {
+				return tt.fn(ctx, txm, repo)
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			got, err := repo.List(ctx)
+			assert.NoError(t, err)
+			var names []string
+			for _, c := range got {
+				names = append(names, c.Name)
+			}
+			assert.ElementsMatch(t, tt.wantNames, names)
+		})
+	}
+}
+
+func TestTxManager_WithinTx_Panic(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repo := NewCategoryRepository(store)
+	txm := NewTxManager(store)
+
+	assert.Panics(t, func() {
+		_ = txm.WithinTx(ctx, func(ctx context.Context) error {
+			_ = repo.Save(ctx, &category.Category{Name: "雑談"})
+			panic("boom")
+		})
+	})
+
+	got, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) user.IUserRepository {
+	return &userRepository{store: store}
+}
+
+func (r *userRepository) Save(ctx context.Context, model *user.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[model.ID]; ok {
+		return fmt.Errorf("failed to save user: %w", Errors.ErrUniqueConstraint)
+	}
+	if r.emailExists(model) {
+		return fmt.Errorf("failed to save user: %w", Errors.ErrUniqueConstraint)
+	}
+
+	t := now()
+	u := *model
+	u.CreatedAt = t
+	u.UpdatedAt = t
+	u.DeletedAt = sql.NullTime{}
+	r.store.users[u.ID] = &u
+
+	return nil
+}
+
+func (r *userRepository) Update(ctx context.Context, model *user.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.emailExists(model) {
+		return fmt.Errorf("failed to update user: %w", Errors.ErrUniqueConstraint)
+	}
+
+	current, ok := r.store.users[model.ID]
+	if !ok || current.DeletedAt.Valid {
+		return fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	u := *current
+	u.Name = model.Name
+	u.Email = model.Email
+	u.UserType = model.UserType
+	u.UpdatedAt = now()
+	r.store.users[u.ID] = &u
+
+	return nil
+}
+
+func (r *userRepository) List(ctx context.Context, opts user.ListOptions) (*user.ListResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// デフォルト値の設定
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	// MySQLの照合順序に合わせて大文字小文字を区別せずに絞り込む
+	var users user.UserSlice
+	for _, u := range r.store.users {
+		if u.DeletedAt.Valid {
+			continue
+		}
+		if opts.Email != "" && !strings.EqualFold(u.Email, opts.Email) {
+			continue
+		}
+		if opts.Name != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(opts.Name)) {
+			continue
+		}
+		if opts.UserType != "" && !strings.EqualFold(u.UserType, opts.UserType) {
+			continue
+		}
+		c := *u
+		users = append(users, &c)
+	}
+	sortByCreatedAtDesc(users, func(u *user.User) time.Time { return u.CreatedAt }, func(u *user.User) string { return u.ID })
+
+	total := len(users)
+	users, hasNext := paginate(users, opts.Limit, opts.Offset)
+
+	return &user.ListResult{
+		Items:   users,
+		Total:   total,
+		Limit:   opts.Limit,
+		Offset:  opts.Offset,
+		HasNext: hasNext,
+	}, nil
+}
+
+func (r *userRepository) Detail(ctx context.Context, model *user.User) (*user.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[model.ID]
+	if !ok || u.DeletedAt.Valid {
+		return nil, fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	result := *u
+	return &result, nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, model *user.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.users[model.ID]
+	if !ok || current.DeletedAt.Valid {
+		return fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	u := *current
+	u.DeletedAt = sql.NullTime{Time: now(), Valid: true}
+	r.store.users[u.ID] = &u
+
+	return nil
+}
+
+// emailExists は大文字小文字のみが異なるメールアドレスを含めて重複を検出します
+// MySQLのユニーク制約と同じく、論理削除済みのユーザーも対象にします
+func (r *userRepository) emailExists(model *user.User) bool {
+	for _, u := range r.store.users {
+		if u.ID != model.ID && strings.EqualFold(u.Email, model.Email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type webhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) webhook.IWebhookRepository {
+	return &webhookRepository{store: store}
+}
+
+// Save はWebhookを登録し、同じIDのWebhookが存在する場合は登録したユーザー以外を更新します
+func (r *webhookRepository) Save(ctx context.Context, model *webhook.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+
+	t := now()
+	w := *model
+	w.EventTypes = slices.Clone(model.EventTypes)
+	w.CreatedAt = t
+	w.UpdatedAt = t
+	if current, ok := r.store.webhooks[model.ID]; ok {
+		w.UserID = current.UserID
+		w.CreatedAt = current.CreatedAt
+	} else if _, ok := r.store.users[model.UserID]; !ok {
+		// 外部キー制約のチェック
+		return fmt.Errorf("failed to save webhook: %w", Errors.ErrForeignKeyConstraint)
+	}
+	r.store.webhooks[w.ID] = &w
+
+	return nil
+}
+
+func (r *webhookRepository) List(ctx context.Context, userID string) (webhook.WebhookSlice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var webhooks webhook.WebhookSlice
+	for _, w := range r.store.webhooks {
+		if userID != "" && w.UserID != userID {
+			continue
+		}
+		webhooks = append(webhooks, copyWebhook(w))
+	}
+	sortByCreatedAtDesc(webhooks, func(w *webhook.Webhook) time.Time { return w.CreatedAt }, func(w *webhook.Webhook) string { return w.ID })
+
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Detail(ctx context.Context, model *webhook.Webhook) (*webhook.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	w, ok := r.store.webhooks[model.ID]
+	if !ok {
+		return nil, fmt.Errorf("webhook not found: %w", Errors.ErrRecordNotFound)
+	}
+	return copyWebhook(w), nil
+}
+
+// Delete はWebhookを物理削除します
+// 外部キー制約と同じく、配信履歴も削除します
+func (r *webhookRepository) Delete(ctx context.Context, model *webhook.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[model.ID]; !ok {
+		return fmt.Errorf("webhook not found: %w", Errors.ErrRecordNotFound)
+	}
+	delete(r.store.webhooks, model.ID)
+	for id, d := range r.store.deliveries {
+		if d.WebhookID == model.ID {
+			delete(r.store.deliveries, id)
+		}
+	}
+	return nil
+}
+
+func copyWebhook(w *webhook.Webhook) *webhook.Webhook {
+	result := *w
+	result.EventTypes = slices.Clone(w.EventTypes)
+	return &result
+}
+
+type webhookDeliveryRepository struct {
+	store *Store
+}
+
+func NewWebhookDeliveryRepository(store *Store) webhook.IDeliveryRepository {
+	return &webhookDeliveryRepository{store: store}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, model *webhook.Delivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[model.WebhookID]; !ok {
+		return fmt.Errorf("failed to create webhook delivery: %w", Errors.ErrForeignKeyConstraint)
+	}
+	if model.DedupKey != "" {
+		for _, d := range r.store.deliveries {
+			if d.WebhookID == model.WebhookID && d.DedupKey == model.DedupKey {
+				return fmt.Errorf("failed to create webhook delivery: %w", Errors.ErrUniqueConstraint)
+			}
+		}
+	}
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+	if model.Status == "" {
+		model.Status = webhook.DeliveryStatusPending
+	}
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now().UTC()
+	}
+	if model.NextAttemptAt.IsZero() {
+		model.NextAttemptAt = model.CreatedAt
+	}
+
+	r.store.deliverySeq++
+	d := *model
+	d.Seq = r.store.deliverySeq
+	d.Attempts = 0
+	r.store.deliveries[d.ID] = &d
+
+	return nil
+}
+
+func (r *webhookDeliveryRepository) List(ctx context.Context, webhookID string, limit, offset int) (*webhook.DeliveryListResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var deliveries webhook.DeliverySlice
+	for _, d := range r.store.deliveries {
+		if d.WebhookID == webhookID {
+			result := *d
+			deliveries = append(deliveries, &result)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].Seq > deliveries[j].Seq })
+
+	total := len(deliveries)
+	items, hasNext := paginate(deliveries, limit, offset)
+
+	return &webhook.DeliveryListResult{
+		Items:   items,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasNext: hasNext,
+	}, nil
+}
+
+func (r *webhookDeliveryRepository) Detail(ctx context.Context, model *webhook.Delivery) (*webhook.Delivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	d, ok := r.store.deliveries[model.ID]
+	if !ok {
+		return nil, fmt.Errorf("webhook delivery not found: %w", Errors.ErrRecordNotFound)
+	}
+	result := *d
+	return &result, nil
+}
+
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current := now()
+	var due []*webhook.Delivery
+	for _, d := range r.store.deliveries {
+		if d.Status == webhook.DeliveryStatusPending && !d.NextAttemptAt.After(current) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Seq < due[j].Seq })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]*webhook.Delivery, 0, len(due))
+	for _, d := range due {
+		claimed := *d
+		claimed.NextAttemptAt = current.Add(lease)
+		r.store.deliveries[d.ID] = &claimed
+
+		out := claimed
+		deliveries = append(deliveries, &out)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) UpdateResult(ctx context.Context, model *webhook.Delivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.deliveries[model.ID]
+	if !ok {
+		return fmt.Errorf("webhook delivery not found: %w", Errors.ErrRecordNotFound)
+	}
+	updated := *d
+	updated.Status = model.Status
+	updated.Attempts = model.Attempts
+	updated.ResponseStatus = model.ResponseStatus
+	updated.LastError = model.LastError
+	updated.NextAttemptAt = model.NextAttemptAt
+	updated.DeliveredAt = model.DeliveredAt
+	r.store.deliveries[model.ID] = &updated
+	return nil
+}
@@ -0,0 +1,79 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/o-ga09/web-ya-hime/db"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	"github.com/o-ga09/web-ya-hime/pkg/health"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// table は sql-migrate が適用済みのマイグレーションを記録するテーブルです
+const table = "gorp_migrations"
+
+// Source は driver のマイグレーションをバイナリに埋め込んだファイルから読み込みます
+func Source(driver string) (migrate.MigrationSource, error) {
+	switch driver {
+	case config.DBDriverMySQL:
+		return migrate.EmbedFileSystemMigrationSource{FileSystem: db.MySQLMigrations, Root: "migrations"}, nil
+	case config.DBDriverSQLite:
+		return migrate.EmbedFileSystemMigrationSource{FileSystem: db.SQLiteMigrations, Root: "migrations/sqlite"}, nil
+	default:
+		return nil, fmt.Errorf("migrations are not supported with DB_DRIVER=%s", driver)
+	}
+}
+
+// pending は migrations のうち、conn に適用されていないもののIDを返します
+// 適用済みのマイグレーションを読み取るだけで、sql-migrate のように記録用のテーブルを作成しません
+// 新しいバージョンが先に適用したマイグレーションは、古いバージョンの動作を妨げないため未適用として扱いません
+func pending(ctx context.Context, conn *sql.DB, migrations []*migrate.Migration) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT id FROM "+table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	var ids []string
+	for _, m := range migrations {
+		if !applied[m.Id] {
+			ids = append(ids, m.Id)
+		}
+	}
+	return ids, nil
+}
+
+// NewCheck は未適用のマイグレーションがある場合に失敗する /readyz のチェックを生成します
+// マイグレーションファイルは生成時に1度だけ読み込みます
+func NewCheck(conn *sql.DB, source migrate.MigrationSource) (health.CheckFunc, error) {
+	migrations, err := source.FindMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find migrations: %w", err)
+	}
+
+	return func(ctx context.Context) error {
+		ids, err := pending(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			return fmt.Errorf("%d pending migrations: %s", len(ids), strings.Join(ids, ", "))
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/sqlite"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		wantErr bool
+	}{
+		{name: "成功ケース: MySQL", driver: config.DBDriverMySQL},
+		{name: "成功ケース: SQLite", driver: config.DBDriverSQLite},
+		{name: "失敗ケース: インメモリはマイグレーションがない", driver: config.DBDriverMemory, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := Source(tt.driver)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// 埋め込んだファイルがリポジトリのマイグレーションと一致する
+			dir := "../../../../db/migrations"
+			if tt.driver == config.DBDriverSQLite {
+				dir += "/sqlite"
+			}
+			want, err := (&migrate.FileMigrationSource{Dir: dir}).FindMigrations()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := source.FindMigrations()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, ids(want), ids(got))
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	ctx := context.Background()
+	conn, err := sqlite.Open(sqlite.Scheme + filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	source, err := Source(config.DBDriverSQLite)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	migrations, err := source.FindMigrations()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check, err := NewCheck(conn, source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// sql-migrate の記録用のテーブルがない場合は失敗する
+	_, err = pending(ctx, conn, migrations)
+	assert.Error(t, err)
+	assert.Error(t, check(ctx))
+
+	// 1つだけ適用すると残りが未適用になる
+	_, err = migrate.ExecMax(conn, sqlite.Dialect.Name(), source, migrate.Up, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := pending(ctx, conn, migrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, ids(migrations[1:]), got)
+	assert.ErrorContains(t, check(ctx), "pending migrations: "+migrations[1].Id)
+
+	_, err = migrate.Exec(conn, sqlite.Dialect.Name(), source, migrate.Up)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err = pending(ctx, conn, migrations)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Empty(t, got)
+	assert.NoError(t, check(ctx))
+}
+
+func ids(migrations []*migrate.Migration) []string {
+	var ids []string
+	for _, m := range migrations {
+		ids = append(ids, m.Id)
+	}
+	return ids
+}
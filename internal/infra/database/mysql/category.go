@@ -3,17 +3,26 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/o-ga09/web-ya-hime/internal/domain/category"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/uuid"
 )
 
-type categoryRepository struct{}
+type categoryRepository struct {
+	dialect Dialect
+}
 
 func NewCategoryRepository() category.ICategoryRepository {
-	return &categoryRepository{}
+	return NewCategoryRepositoryWithDialect(MySQL)
+}
+
+// NewCategoryRepositoryWithDialect は MySQL 以外の方言を使うカテゴリリポジトリを生成します
+func NewCategoryRepositoryWithDialect(d Dialect) category.ICategoryRepository {
+	return &categoryRepository{dialect: d}
 }
 
 func (r *categoryRepository) Save(ctx context.Context, model *category.Category) error {
@@ -26,15 +35,14 @@ func (r *categoryRepository) Save(ctx context.Context, model *category.Category)
 		model.ID = uuid.GenerateID()
 	}
 
-	query := `
+	now := r.dialect.Now()
+	query := fmt.Sprintf(`
 		INSERT INTO categories (id, name, created_at, updated_at)
-		VALUES (?, ?, CURRENT_TIMESTAMP(6), CURRENT_TIMESTAMP(6))
-		ON DUPLICATE KEY UPDATE
-			name = VALUES(name),
-			updated_at = CURRENT_TIMESTAMP(6)
-	`
+		VALUES (?, ?, %s, %s)
+		%s
+	`, now, now, r.dialect.Upsert("id", "name"))
 
-	_, err := db.ExecContext(ctx, query, model.ID, model.Name)
+	_, err := execContext(ctx, r.dialect, db, query, model.ID, model.Name)
 	if err != nil {
 		return fmt.Errorf("failed to save category: %w", err)
 	}
@@ -43,7 +51,7 @@ func (r *categoryRepository) Save(ctx context.Context, model *category.Category)
 }
 
 func (r *categoryRepository) List(ctx context.Context) (category.CategorySlice, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection is not set in context")
 	}
@@ -77,7 +85,7 @@ func (r *categoryRepository) List(ctx context.Context) (category.CategorySlice,
 }
 
 func (r *categoryRepository) Detail(ctx context.Context, model *category.Category) (*category.Category, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection is not set in context")
 	}
@@ -93,8 +101,8 @@ func (r *categoryRepository) Detail(ctx context.Context, model *category.Categor
 		&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("category not found: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("category not found: %w", Errors.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("failed to get category detail: %w", err)
 	}
@@ -110,7 +118,7 @@ func (r *categoryRepository) Delete(ctx context.Context, model *category.Categor
 
 	query := `DELETE FROM categories WHERE id = ?`
 
-	result, err := db.ExecContext(ctx, query, model.ID)
+	result, err := execContext(ctx, r.dialect, db, query, model.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
@@ -121,7 +129,7 @@ func (r *categoryRepository) Delete(ctx context.Context, model *category.Categor
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("category not found")
+		return fmt.Errorf("category not found: %w", Errors.ErrRecordNotFound)
 	}
 
 	return nil
@@ -10,49 +10,51 @@ import (
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
 )
 
-const (
-	maxRetries      = 5
-	retryInterval   = 2 * time.Second
-	maxIdleConns    = 10
-	maxOpenConns    = 100
-	connMaxLifetime = 1 * time.Hour
-)
-
+// Connect はコネクションプールを作成し、接続できるまでリトライします
+// プールはアプリケーションで1つだけ作成し、すべてのリクエストで共有します
+// プールサイズ、コネクションの寿命、リトライ回数と間隔は config.Config で設定します
 func Connect(ctx context.Context) (*sql.DB, error) {
-	var db *sql.DB
-	var err error
 	env := Ctx.GetCtxCfg(ctx)
-	dsn := env.Database_url
-
-	// リトライ処理
-	for i := 0; i < maxRetries; i++ {
-		db, err = sql.Open("mysql", dsn)
-		if err != nil {
-			if i == maxRetries-1 {
-				return nil, fmt.Errorf("failed to open database after %d retries: %w", maxRetries, err)
-			}
-			time.Sleep(retryInterval)
-			continue
-		}
-
-		// 接続確認
-		if err = db.PingContext(ctx); err != nil {
-			db.Close()
-			if i == maxRetries-1 {
-				return nil, fmt.Errorf("failed to ping database after %d retries: %w", maxRetries, err)
-			}
-			time.Sleep(retryInterval)
-			continue
-		}
 
-		// 接続成功
-		break
+	db, err := sql.Open("mysql", env.Database_url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// コネクションプール設定
-	db.SetMaxIdleConns(maxIdleConns)
-	db.SetMaxOpenConns(maxOpenConns)
-	db.SetConnMaxLifetime(connMaxLifetime)
+	db.SetMaxOpenConns(env.DBMaxOpenConns)
+	db.SetMaxIdleConns(env.DBMaxIdleConns)
+	db.SetConnMaxLifetime(env.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(env.DBConnMaxIdleTime)
+
+	if err := ping(ctx, db, env.DBConnectMaxRetries, env.DBConnectRetryInterval); err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	return db, nil
 }
+
+// ping は接続確認を最大 maxRetries 回、interval 間隔で行います
+func ping(ctx context.Context, db *sql.DB, maxRetries int, interval time.Duration) error {
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
+
+	var err error
+	for i := 0; i < maxRetries; i++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if i == maxRetries-1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to ping database: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+	return fmt.Errorf("failed to ping database after %d retries: %w", maxRetries, err)
+}
@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/contract"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+)
+
+// TestContract は TEST_DATABASE_URL にマイグレーション済みのMySQLを指定した場合のみ実行します
+// テストケースごとにすべてのテーブルを空にするため、開発用のデータベースは指定しないでください
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping database: %v", err)
+	}
+
+	contract.Run(t, func(t *testing.T) (context.Context, contract.Repositories) {
+		ctx := Ctx.SetDB(context.Background(), db)
+		truncate(t, ctx, db)
+		return ctx, contract.Repositories{
+			User:        NewUserRepository(),
+			Summary:     NewSummaryRepository(),
+			Category:    NewCategoryRepository(),
+			Subcategory: NewSubcategoryRepository(),
+			Outbox:      NewOutboxRepository(),
+			Webhook:     NewWebhookRepository(),
+			Delivery:    NewWebhookDeliveryRepository(),
+			RateLimit:   NewRateLimitStore(),
+		}
+	})
+}
+
+func truncate(t *testing.T, ctx context.Context, db *sql.DB) {
+	t.Helper()
+
+	// TRUNCATE は外部キー制約のチェックを無効にした同じ接続で実行する必要がある
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	queries := []string{
+		"SET FOREIGN_KEY_CHECKS = 0",
+		"TRUNCATE TABLE summaries",
+		"TRUNCATE TABLE subcategories",
+		"TRUNCATE TABLE categories",
+		"TRUNCATE TABLE users",
+		"TRUNCATE TABLE outbox",
+		"TRUNCATE TABLE webhook_deliveries",
+		"TRUNCATE TABLE webhooks",
+		"TRUNCATE TABLE rate_limits",
+		"SET FOREIGN_KEY_CHECKS = 1",
+	}
+	for _, q := range queries {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			t.Fatalf("failed to truncate tables: %v", err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Dialect はデータベースごとのSQLの方言の違いを吸収します
+// リポジトリは方言に依存する部分のSQLとエラーの変換を Dialect を通して行います
+type Dialect interface {
+	// Name は sql-migrate で使う方言の名前です
+	Name() string
+	// Now は現在時刻を返すSQLの式です
+	Now() string
+	// Upsert は主キー key が重複した場合に columns を挿入しようとした値で更新し、
+	// updated_at を現在時刻にする INSERT 文の末尾の句を返します
+	Upsert(key string, columns ...string) string
+	// Like は escapeLike でエスケープした値を column と部分一致で比較するSQLの式です
+	Like(column string) string
+	// ForShare は SELECT 文の末尾に付け、読み取った行をトランザクションの終わりまで共有ロックする句を返します
+	ForShare() string
+	// TranslateError はドライバーのエラーをドメインエラーでラップします
+	// 対応するドメインエラーがない場合はそのまま返します
+	TranslateError(err error) error
+}
+
+// MySQL はMySQLの方言です
+var MySQL Dialect = mysqlDialect{}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Now() string {
+	return "CURRENT_TIMESTAMP(6)"
+}
+
+// Upsert はMySQLでは key に関わらず、いずれかの一意キーが重複した場合に更新します
+func (d mysqlDialect) Upsert(key string, columns ...string) string {
+	sets := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", c, c))
+	}
+	sets = append(sets, "updated_at = "+d.Now())
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+// Like はMySQLのデフォルトのエスケープ文字がバックスラッシュのため ESCAPE 句を省略します
+func (mysqlDialect) Like(column string) string {
+	return column + " LIKE ?"
+}
+
+func (mysqlDialect) ForShare() string {
+	return "FOR SHARE"
+}
+
+func (mysqlDialect) TranslateError(err error) error {
+	return translateError(err)
+}
+
+// utcTimeFormat はアプリケーションで計算した日時をSQLに渡す形式です
+// アウトボックスの配信予定時刻のようにアプリケーションで計算して比較する日時は、
+// タイムゾーンの設定に左右されないようにMySQLとSQLiteで同じ形式のUTCの文字列として渡します
+const utcTimeFormat = "2006-01-02 15:04:05.000000"
+
+func utcTime(t time.Time) string {
+	return t.UTC().Format(utcTimeFormat)
+}
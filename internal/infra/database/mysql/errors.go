@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
+
+// MySQLのエラー番号
+const (
+	errNumDuplicateEntry     uint16 = 1062
+	errNumRowIsReferenced    uint16 = 1451
+	errNumNoReferencedRow    uint16 = 1452
+	errNumLockWaitTimeout    uint16 = 1205
+	errNumDeadlock           uint16 = 1213
+	errNumRowIsReferencedOld uint16 = 1217
+	errNumNoReferencedRowOld uint16 = 1216
+)
+
+// errorTranslations はMySQLのエラー番号とドメインエラーの対応です
+var errorTranslations = map[uint16]error{
+	errNumDuplicateEntry:     Errors.ErrUniqueConstraint,
+	errNumRowIsReferenced:    Errors.ErrForeignKeyConstraint,
+	errNumNoReferencedRow:    Errors.ErrForeignKeyConstraint,
+	errNumRowIsReferencedOld: Errors.ErrForeignKeyConstraint,
+	errNumNoReferencedRowOld: Errors.ErrForeignKeyConstraint,
+	errNumLockWaitTimeout:    Errors.ErrRetryable,
+	errNumDeadlock:           Errors.ErrRetryable,
+}
+
+// 再試行の設定
+// デッドロックとロック待ちタイムアウトは retryBaseDelay から倍々に待ち時間を延ばして再試行します
+var (
+	retryMaxAttempts = 3
+	retryBaseDelay   = 50 * time.Millisecond
+)
+
+// translateError はMySQLのエラーをドメインエラーでラップして返します
+// 元のエラーもラップしたままにするため、errors.As で *mysql.MySQLError を取り出せます
+func translateError(err error) error {
+	var mysqlErr *driver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	if sentinel, ok := errorTranslations[mysqlErr.Number]; ok {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return err
+}
+
+// withRetry は fn が再試行可能なエラーを返した場合にバックオフしながら再実行します
+// contextがキャンセルされた場合は待機を中断して最後のエラーを返します
+func withRetry(ctx context.Context, fn func() error) error {
+	delay := retryBaseDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !errors.Is(err, Errors.ErrRetryable) || attempt >= retryMaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// execContext は方言に応じたエラーの変換と再試行を行う ExecContext です
+// 書き込みのクエリはこの関数を通して実行します
+// トランザクション中はデッドロックでトランザクション全体がロールバックされるため、
+// 文単位では再試行せず WithinTx がトランザクション全体を再試行します
+func execContext(ctx context.Context, d Dialect, db Ctx.Querier, query string, args ...any) (sql.Result, error) {
+	if Ctx.GetTx(ctx) != nil {
+		result, err := db.ExecContext(ctx, query, args...)
+		return result, d.TranslateError(err)
+	}
+
+	var result sql.Result
+	err := withRetry(ctx, func() error {
+		var err error
+		result, err = db.ExecContext(ctx, query, args...)
+		return d.TranslateError(err)
+	})
+	return result, err
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		wantIs error
+	}{
+		{name: "成功ケース: 1062はユニーク制約違反", err: &driver.MySQLError{Number: 1062}, wantIs: Errors.ErrUniqueConstraint},
+		{name: "成功ケース: 1451は外部キー制約違反", err: &driver.MySQLError{Number: 1451}, wantIs: Errors.ErrForeignKeyConstraint},
+		{name: "成功ケース: 1452は外部キー制約違反", err: &driver.MySQLError{Number: 1452}, wantIs: Errors.ErrForeignKeyConstraint},
+		{name: "成功ケース: 1213は再試行可能", err: &driver.MySQLError{Number: 1213}, wantIs: Errors.ErrRetryable},
+		{name: "成功ケース: 1205は再試行可能", err: &driver.MySQLError{Number: 1205}, wantIs: Errors.ErrRetryable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			assert.ErrorIs(t, got, tt.wantIs)
+
+			// 元のドライバーのエラーも取り出せる
+			var mysqlErr *driver.MySQLError
+			assert.True(t, errors.As(got, &mysqlErr))
+		})
+	}
+
+	t.Run("成功ケース: 対応のないエラーはそのまま返す", func(t *testing.T) {
+		err := &driver.MySQLError{Number: 1146}
+		assert.Same(t, err, translateError(err))
+		assert.NoError(t, translateError(nil))
+	})
+}
+
+func TestWithRetry(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = 50 * time.Millisecond })
+
+	t.Run("成功ケース: 再試行できないエラーは再試行しない", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), func() error {
+			calls++
+			return Errors.ErrUniqueConstraint
+		})
+		assert.ErrorIs(t, err, Errors.ErrUniqueConstraint)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("失敗ケース: contextがキャンセルされたら再試行を中断する", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		err := withRetry(ctx, func() error {
+			calls++
+			return Errors.ErrRetryable
+		})
+		assert.ErrorIs(t, err, Errors.ErrRetryable)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type outboxRepository struct {
+	dialect Dialect
+}
+
+func NewOutboxRepository() event.IOutboxRepository {
+	return NewOutboxRepositoryWithDialect(MySQL)
+}
+
+// NewOutboxRepositoryWithDialect は MySQL 以外の方言を使うアウトボックスのリポジトリを生成します
+func NewOutboxRepositoryWithDialect(d Dialect) event.IOutboxRepository {
+	return &outboxRepository{dialect: d}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...*event.Event) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	query := `INSERT INTO outbox (id, event_type, aggregate_id, payload, status, attempts, occurred_at, next_attempt_at) VALUES (?, ?, ?, ?, ?, 0, ?, ?)`
+	for _, e := range events {
+		if e.ID == "" {
+			e.ID = uuid.GenerateID()
+		}
+		if e.OccurredAt.IsZero() {
+			e.OccurredAt = time.Now().UTC().Truncate(time.Microsecond)
+		}
+		occurredAt := utcTime(e.OccurredAt)
+		if _, err := execContext(ctx, r.dialect, db, query, e.ID, e.Type, e.AggregateID, string(e.Payload), event.StatusPending, occurredAt, occurredAt); err != nil {
+			return fmt.Errorf("failed to append event: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*event.Record, error) {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection not found in context")
+	}
+
+	// 対象の行に取得用のトークンを設定してから、トークンで取得する
+	// 外側の WHERE でも状態と時刻を確認し、同時に実行された他の配信処理が取得した行は取得しない
+	// MySQLは更新対象のテーブルを直接サブクエリで参照できないため、派生テーブルで囲む
+	token := uuid.GenerateID()
+	now := time.Now()
+	query := `
+		UPDATE outbox SET claim_token = ?, next_attempt_at = ?
+		WHERE status = ? AND next_attempt_at <= ? AND id IN (
+			SELECT id FROM (
+				SELECT id FROM outbox
+				WHERE status = ? AND next_attempt_at <= ?
+				ORDER BY seq
+				LIMIT ?
+			) AS due
+		)
+	`
+	_, err := execContext(ctx, r.dialect, db, query,
+		token, utcTime(now.Add(lease)),
+		event.StatusPending, utcTime(now),
+		event.StatusPending, utcTime(now), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+outboxColumns+` FROM outbox WHERE claim_token = ? ORDER BY seq`, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claimed events: %w", err)
+	}
+	defer rows.Close()
+	return scanRecords(rows)
+}
+
+func (r *outboxRepository) ListAfter(ctx context.Context, seq int64, limit int) ([]*event.Record, error) {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection not found in context")
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+outboxColumns+` FROM outbox WHERE seq > ? ORDER BY seq LIMIT ?`, seq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+	return scanRecords(rows)
+}
+
+func (r *outboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return 0, fmt.Errorf("database connection not found in context")
+	}
+
+	var seq sql.NullInt64
+	if err := db.QueryRowContext(ctx, `SELECT MAX(seq) FROM outbox`).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to get last event seq: %w", err)
+	}
+	return seq.Int64, nil
+}
+
+const outboxColumns = `seq, id, event_type, aggregate_id, payload, status, attempts, last_error, occurred_at, next_attempt_at, delivered_at`
+
+func scanRecords(rows *sql.Rows) ([]*event.Record, error) {
+	var records []*event.Record
+	for rows.Next() {
+		var (
+			rec         event.Record
+			payload     []byte
+			lastError   sql.NullString
+			deliveredAt sql.NullTime
+		)
+		if err := rows.Scan(&rec.Seq, &rec.ID, &rec.Type, &rec.AggregateID, &payload, &rec.Status, &rec.Attempts, &lastError, &rec.OccurredAt, &rec.NextAttemptAt, &deliveredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		rec.Payload = payload
+		rec.LastError = lastError.String
+		if deliveredAt.Valid {
+			rec.DeliveredAt = &deliveredAt.Time
+		}
+		records = append(records, &rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate events: %w", err)
+	}
+	return records, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id string) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	query := `UPDATE outbox SET status = ?, delivered_at = ?, claim_token = NULL WHERE id = ?`
+	if _, err := execContext(ctx, r.dialect, db, query, event.StatusDelivered, utcTime(time.Now()), id); err != nil {
+		return fmt.Errorf("failed to mark event delivered: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, record *event.Record) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	query := `UPDATE outbox SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, claim_token = NULL WHERE id = ?`
+	if _, err := execContext(ctx, r.dialect, db, query, record.Status, record.Attempts, record.LastError, utcTime(record.NextAttemptAt), record.ID); err != nil {
+		return fmt.Errorf("failed to mark event failed: %w", err)
+	}
+	return nil
+}
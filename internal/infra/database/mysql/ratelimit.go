@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+)
+
+// レート制限のバケットの更新の設定
+// 同じキーの同時の更新で競合した場合は rateLimitMaxAttempts 回まで読み直します
+// 満杯まで回復した行は rateLimitSweepInterval ごとにまとめて削除します
+var (
+	rateLimitMaxAttempts   = 5
+	rateLimitSweepInterval = time.Minute
+)
+
+type rateLimitStore struct {
+	dialect Dialect
+	// lastSweep は最後に満杯の行を削除した時刻 (UNIX時間のナノ秒) です
+	lastSweep atomic.Int64
+}
+
+// NewRateLimitStore は rate_limits テーブルにバケットを保存するストアを生成します
+// 複数のインスタンスで同じ上限を共有します
+func NewRateLimitStore() ratelimit.Store {
+	return NewRateLimitStoreWithDialect(MySQL)
+}
+
+// NewRateLimitStoreWithDialect は MySQL 以外の方言を使うレート制限のストアを生成します
+func NewRateLimitStoreWithDialect(d Dialect) ratelimit.Store {
+	return &rateLimitStore{dialect: d}
+}
+
+// Take はバケットを読み取って計算した値で、読み取った値から変わっていない場合だけ更新します
+// 行ロックを使わないため、SELECT ... FOR UPDATE のないSQLiteでも同じSQLで動作します
+// 拒否する場合は更新しません
+func (s *rateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	db := Ctx.GetPool(ctx)
+	if db == nil {
+		return ratelimit.Result{}, fmt.Errorf("database connection is not set in context")
+	}
+
+	now := time.Now()
+	s.sweep(ctx, db, now)
+
+	for range rateLimitMaxAttempts {
+		var stored int64
+		err := db.QueryRowContext(ctx, `SELECT tat FROM rate_limits WHERE bucket_key = ?`, key).Scan(&stored)
+		exists := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return ratelimit.Result{}, fmt.Errorf("failed to get rate limit: %w", s.dialect.TranslateError(err))
+		}
+
+		var tat time.Time
+		if exists {
+			tat = time.UnixMicro(stored)
+		}
+		next, result := ratelimit.Take(tat, now, limit)
+		if !result.Allowed {
+			return result, nil
+		}
+
+		var res sql.Result
+		if exists {
+			res, err = execContext(ctx, s.dialect, db, `UPDATE rate_limits SET tat = ? WHERE bucket_key = ? AND tat = ?`, next.UnixMicro(), key, stored)
+		} else {
+			res, err = execContext(ctx, s.dialect, db, `INSERT INTO rate_limits (bucket_key, tat) VALUES (?, ?)`, key, next.UnixMicro())
+			if Errors.Is(err, Errors.ErrUniqueConstraint) {
+				continue
+			}
+		}
+		if err != nil {
+			return ratelimit.Result{}, fmt.Errorf("failed to save rate limit: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 1 {
+			return result, nil
+		}
+		// 読み取った後に他のリクエストが更新したため読み直す
+		now = time.Now()
+	}
+	return ratelimit.Result{}, fmt.Errorf("failed to save rate limit: %w", Errors.ErrConflict)
+}
+
+// sweep は満杯まで回復した行を削除します
+// 満杯のバケットは行がない場合と同じ結果になるため、削除しても上限は変わりません
+// 削除に失敗してもリクエストは処理を続けます
+func (s *rateLimitStore) sweep(ctx context.Context, db *sql.DB, now time.Time) {
+	last := s.lastSweep.Load()
+	if now.UnixNano()-last < int64(rateLimitSweepInterval) || !s.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	_, _ = execContext(ctx, s.dialect, db, `DELETE FROM rate_limits WHERE tat <= ?`, now.UnixMicro())
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// ReplicaSet は読み取り専用のレプリカのコネクションプールの集まりです
+// 読み取りクエリごとにラウンドロビンでレプリカを選び、ヘルスチェックに失敗したレプリカは復帰するまで選びません
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	index   int
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewReplicaSet はレプリカのコネクションプールから ReplicaSet を生成します
+// 最初のヘルスチェックまではすべてのレプリカを正常として扱います
+func NewReplicaSet(dbs ...*sql.DB) *ReplicaSet {
+	r := &ReplicaSet{}
+	for i, db := range dbs {
+		rep := &replica{index: i, db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r
+}
+
+// ConnectReplicas は DATABASE_REPLICA_URLS のレプリカのコネクションプールを作成します
+// レプリカが指定されていない場合は nil を返します
+// レプリカに接続できなくても起動は続け、ヘルスチェックで復帰するまでプライマリから読み取ります
+func ConnectReplicas(ctx context.Context) (*ReplicaSet, error) {
+	env := Ctx.GetCtxCfg(ctx)
+	if len(env.DatabaseReplicaURLs) == 0 {
+		return nil, nil
+	}
+
+	dbs := make([]*sql.DB, 0, len(env.DatabaseReplicaURLs))
+	for i, dsn := range env.DatabaseReplicaURLs {
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			for _, opened := range dbs {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("failed to open replica %d: %w", i, err)
+		}
+
+		// コネクションプール設定はプライマリと同じ
+		db.SetMaxOpenConns(env.DBMaxOpenConns)
+		db.SetMaxIdleConns(env.DBMaxIdleConns)
+		db.SetConnMaxLifetime(env.DBConnMaxLifetime)
+		db.SetConnMaxIdleTime(env.DBConnMaxIdleTime)
+		dbs = append(dbs, db)
+	}
+
+	r := NewReplicaSet(dbs...)
+	r.CheckHealth(ctx)
+	return r, nil
+}
+
+// Pick はラウンドロビンで正常なレプリカを選びます
+// 正常なレプリカがない場合は nil を返し、呼び出し元はプライマリを使います
+func (r *ReplicaSet) Pick() *sql.DB {
+	if r == nil || len(r.replicas) == 0 {
+		return nil
+	}
+
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1) - 1
+	for i := uint64(0); i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return nil
+}
+
+// DBs はレプリカのコネクションプールを指定した順に返します。接続数などの統計情報の出力に使います
+func (r *ReplicaSet) DBs() []*sql.DB {
+	if r == nil {
+		return nil
+	}
+	dbs := make([]*sql.DB, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		dbs = append(dbs, rep.db)
+	}
+	return dbs
+}
+
+// CheckHealth はすべてのレプリカに接続確認を行い、状態が変わったレプリカをログに出力します
+func (r *ReplicaSet) CheckHealth(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, time.Second)
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			logger.Info(ctx, fmt.Sprintf("replica %d is healthy again", rep.index))
+		} else {
+			logger.Warn(ctx, fmt.Sprintf("replica %d is unhealthy: %v", rep.index, err))
+		}
+	}
+}
+
+// Watch は ctx がキャンセルされるまで interval ごとにヘルスチェックを行います
+func (r *ReplicaSet) Watch(ctx context.Context, interval time.Duration) {
+	if r == nil || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckHealth(ctx)
+		}
+	}
+}
+
+// Close はすべてのレプリカのコネクションプールを閉じます
+func (r *ReplicaSet) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
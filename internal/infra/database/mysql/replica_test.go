@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPingMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func TestReplicaSet_Pick(t *testing.T) {
+	db1, _ := newPingMock(t)
+	db2, _ := newPingMock(t)
+	db3, _ := newPingMock(t)
+
+	tests := []struct {
+		name      string
+		replicas  *ReplicaSet
+		unhealthy []int
+		want      []*sql.DB
+	}{
+		{
+			name:     "成功ケース: ラウンドロビンで選ぶ",
+			replicas: NewReplicaSet(db1, db2, db3),
+			want:     []*sql.DB{db1, db2, db3, db1},
+		},
+		{
+			name:      "成功ケース: 異常なレプリカは選ばない",
+			replicas:  NewReplicaSet(db1, db2, db3),
+			unhealthy: []int{1},
+			want:      []*sql.DB{db1, db3, db3, db1},
+		},
+		{
+			name:      "失敗ケース: すべてのレプリカが異常な場合はnil",
+			replicas:  NewReplicaSet(db1, db2),
+			unhealthy: []int{0, 1},
+			want:      []*sql.DB{nil, nil},
+		},
+		{
+			name:     "失敗ケース: レプリカがない場合はnil",
+			replicas: nil,
+			want:     []*sql.DB{nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, i := range tt.unhealthy {
+				tt.replicas.replicas[i].healthy.Store(false)
+			}
+
+			var got []*sql.DB
+			for range tt.want {
+				got = append(got, tt.replicas.Pick())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplicaSet_CheckHealth(t *testing.T) {
+	db, mock := newPingMock(t)
+	replicas := NewReplicaSet(db)
+	ctx := context.Background()
+
+	// 接続確認に失敗したレプリカは選ばない
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	replicas.CheckHealth(ctx)
+	assert.Nil(t, replicas.Pick())
+
+	// 接続確認に成功すると復帰する
+	mock.ExpectPing()
+	replicas.CheckHealth(ctx)
+	assert.Same(t, db, replicas.Pick())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadRouting(t *testing.T) {
+	repo := NewUserRepository()
+	now := time.Now()
+	input := &user.User{WYHBaseModel: domain.WYHBaseModel{ID: "user-1"}}
+
+	expectDetail := func(mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "user_type", "created_at", "updated_at"}).
+			AddRow("user-1", "User Name 1", "user1@example.com", "admin", now, now)
+		mock.ExpectQuery("SELECT (.+) FROM users WHERE id = ?").WithArgs("user-1").WillReturnRows(rows)
+	}
+
+	tests := []struct {
+		name            string
+		readFromPrimary bool
+		inTx            bool
+		wantOnReplica   bool
+	}{
+		{
+			name:          "成功ケース: 読み取りはレプリカで行う",
+			wantOnReplica: true,
+		},
+		{
+			name:            "成功ケース: 書き込み後はプライマリで読み取る",
+			readFromPrimary: true,
+		},
+		{
+			name: "成功ケース: トランザクション中はプライマリで読み取る",
+			inTx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, primaryMock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer primary.Close()
+			replica, replicaMock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer replica.Close()
+
+			ctx := Ctx.SetReplicas(Ctx.SetDB(context.Background(), primary), NewReplicaSet(replica))
+			if tt.readFromPrimary {
+				ctx = Ctx.SetReadFromPrimary(ctx)
+			}
+			if tt.inTx {
+				primaryMock.ExpectBegin()
+				tx, err := primary.Begin()
+				assert.NoError(t, err)
+				defer tx.Rollback() //nolint:errcheck
+				ctx = Ctx.SetTx(ctx, tx)
+			}
+			if tt.wantOnReplica {
+				expectDetail(replicaMock)
+			} else {
+				expectDetail(primaryMock)
+			}
+
+			result, err := repo.Detail(ctx, input)
+			assert.NoError(t, err)
+			assert.Equal(t, "user-1", result.ID)
+			assert.NoError(t, primaryMock.ExpectationsWereMet())
+			assert.NoError(t, replicaMock.ExpectationsWereMet())
+		})
+	}
+}
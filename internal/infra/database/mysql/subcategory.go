@@ -3,18 +3,27 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/o-ga09/web-ya-hime/internal/domain/category"
 	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/o-ga09/web-ya-hime/pkg/uuid"
 )
 
-type subcategoryRepository struct{}
+type subcategoryRepository struct {
+	dialect Dialect
+}
 
 func NewSubcategoryRepository() subcategory.ISubcategoryRepository {
-	return &subcategoryRepository{}
+	return NewSubcategoryRepositoryWithDialect(MySQL)
+}
+
+// NewSubcategoryRepositoryWithDialect は MySQL 以外の方言を使うサブカテゴリリポジトリを生成します
+func NewSubcategoryRepositoryWithDialect(d Dialect) subcategory.ISubcategoryRepository {
+	return &subcategoryRepository{dialect: d}
 }
 
 func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Subcategory) error {
@@ -27,15 +36,14 @@ func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Sub
 		model.ID = uuid.GenerateID()
 	}
 
-	query := `
+	now := r.dialect.Now()
+	query := fmt.Sprintf(`
 		INSERT INTO subcategories (id, category_id, name, created_at, updated_at)
-		VALUES (?, ?, ?, CURRENT_TIMESTAMP(6), CURRENT_TIMESTAMP(6))
-		ON DUPLICATE KEY UPDATE
-			name = VALUES(name),
-			updated_at = CURRENT_TIMESTAMP(6)
-	`
+		VALUES (?, ?, ?, %s, %s)
+		%s
+	`, now, now, r.dialect.Upsert("id", "name"))
 
-	_, err := db.ExecContext(ctx, query, model.ID, model.CategoryID, model.Name)
+	_, err := execContext(ctx, r.dialect, db, query, model.ID, model.CategoryID, model.Name)
 	if err != nil {
 		return fmt.Errorf("failed to save subcategory: %w", err)
 	}
@@ -44,7 +52,7 @@ func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Sub
 }
 
 func (r *subcategoryRepository) List(ctx context.Context, categoryID string) (subcategory.SubcategorySlice, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection is not set in context")
 	}
@@ -99,18 +107,32 @@ func (r *subcategoryRepository) List(ctx context.Context, categoryID string) (su
 }
 
 func (r *subcategoryRepository) Detail(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	db := Ctx.GetReadDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+	return r.detail(ctx, db, model, "")
+}
+
+// DetailForShare はプライマリから読み取り、サブカテゴリの行を共有ロックします
+// ロックはトランザクション外では読み取りの間だけで解放されます
+func (r *subcategoryRepository) DetailForShare(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
 	db := Ctx.GetDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection is not set in context")
 	}
+	return r.detail(ctx, db, model, r.dialect.ForShare())
+}
 
-	query := `
+func (r *subcategoryRepository) detail(ctx context.Context, db Ctx.Querier, model *subcategory.Subcategory, lock string) (*subcategory.Subcategory, error) {
+	query := fmt.Sprintf(`
 		SELECT s.id, s.category_id, s.name, s.created_at, s.updated_at,
 			   c.id, c.name, c.created_at, c.updated_at
 		FROM subcategories s
 		INNER JOIN categories c ON s.category_id = c.id
 		WHERE s.id = ?
-	`
+		%s
+	`, lock)
 
 	var s subcategory.Subcategory
 	s.Category = &category.Category{}
@@ -119,8 +141,8 @@ func (r *subcategoryRepository) Detail(ctx context.Context, model *subcategory.S
 		&s.Category.ID, &s.Category.Name, &s.Category.CreatedAt, &s.Category.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("subcategory not found: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("failed to get subcategory detail: %w", err)
 	}
@@ -136,7 +158,7 @@ func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.S
 
 	query := `DELETE FROM subcategories WHERE id = ?`
 
-	result, err := db.ExecContext(ctx, query, model.ID)
+	result, err := execContext(ctx, r.dialect, db, query, model.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete subcategory: %w", err)
 	}
@@ -147,7 +169,7 @@ func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.S
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("subcategory not found")
+		return fmt.Errorf("subcategory not found: %w", Errors.ErrRecordNotFound)
 	}
 
 	return nil
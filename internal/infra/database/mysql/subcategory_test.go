@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubcategoryRepository_DetailForShare(t *testing.T) {
+	repo := NewSubcategoryRepository()
+	now := time.Now()
+	query := "SELECT (.+) FROM subcategories s INNER JOIN categories c ON s.category_id = c.id WHERE s.id = \\? FOR SHARE"
+
+	tests := []struct {
+		name      string
+		mockFn    func(mock sqlmock.Sqlmock)
+		wantErrIs error
+	}{
+		{
+			name: "成功ケース: プライマリでサブカテゴリをロックして取得する",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{
+					"id", "category_id", "name", "created_at", "updated_at",
+					"id", "name", "created_at", "updated_at",
+				}).AddRow("subcat-1", "cat-1", "APEX", now, now, "cat-1", "ゲーム", now, now)
+				mock.ExpectQuery(query).WithArgs("subcat-1").WillReturnRows(rows)
+			},
+		},
+		{
+			name: "失敗ケース: サブカテゴリが見つからない",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs("subcat-1").WillReturnError(sql.ErrNoRows)
+			},
+			wantErrIs: Errors.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer primary.Close()
+			replica, replicaMock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to create sqlmock: %v", err)
+			}
+			defer replica.Close()
+
+			tt.mockFn(mock)
+
+			// レプリカが設定されていてもロックを取るためにプライマリから読み取る
+			ctx := Ctx.SetReplicas(Ctx.SetDB(context.Background(), primary), NewReplicaSet(replica))
+			got, err := repo.DetailForShare(ctx, &subcategory.Subcategory{WYHBaseModel: domain.WYHBaseModel{ID: "subcat-1"}})
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "cat-1", got.CategoryID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+			assert.NoError(t, replicaMock.ExpectationsWereMet())
+		})
+	}
+}
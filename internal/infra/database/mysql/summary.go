@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/o-ga09/web-ya-hime/internal/domain"
@@ -11,12 +12,20 @@ import (
 	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 )
 
-type summaryRepository struct{}
+type summaryRepository struct {
+	dialect Dialect
+}
 
 func NewSummaryRepository() summary.ISummaryRepository {
-	return &summaryRepository{}
+	return NewSummaryRepositoryWithDialect(MySQL)
+}
+
+// NewSummaryRepositoryWithDialect は MySQL 以外の方言を使うサマリーリポジトリを生成します
+func NewSummaryRepositoryWithDialect(d Dialect) summary.ISummaryRepository {
+	return &summaryRepository{dialect: d}
 }
 
 func (s *summaryRepository) Save(ctx context.Context, model *summary.Summary) error {
@@ -25,8 +34,9 @@ func (s *summaryRepository) Save(ctx context.Context, model *summary.Summary) er
 		return fmt.Errorf("database connection not found in context")
 	}
 
-	query := `INSERT INTO summaries (id, title, description, content, category_id, subcategory_id, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`
-	_, err := db.ExecContext(ctx, query, model.ID, model.Title, model.Description, model.Content, model.CategoryID, model.SubcategoryID, model.UserID)
+	now := s.dialect.Now()
+	query := fmt.Sprintf(`INSERT INTO summaries (id, title, description, content, category_id, subcategory_id, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, %s, %s)`, now, now)
+	_, err := execContext(ctx, s.dialect, db, query, model.ID, model.Title, model.Description, model.Content, model.CategoryID, model.SubcategoryID, model.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to save summary: %w", err)
 	}
@@ -35,7 +45,7 @@ func (s *summaryRepository) Save(ctx context.Context, model *summary.Summary) er
 }
 
 func (s *summaryRepository) List(ctx context.Context, opts summary.ListOptions) (*summary.ListResult, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection not found in context")
 	}
@@ -74,7 +84,7 @@ func (s *summaryRepository) List(ctx context.Context, opts summary.ListOptions)
 	// データ取得
 	query := fmt.Sprintf(`
 		SELECT 
-			s.id, s.title, s.description, s.content, s.category_id, s.subcategory_id, s.user_id, s.created_at, s.updated_at,
+			s.id, s.title, s.description, s.content, s.category_id, s.subcategory_id, s.user_id, s.published_at, s.created_at, s.updated_at,
 			u.id, u.name, u.email, u.user_type, u.created_at, u.updated_at,
 			c.id, c.name, c.created_at, c.updated_at,
 			sc.id, sc.category_id, sc.name, sc.created_at, sc.updated_at
@@ -104,7 +114,7 @@ func (s *summaryRepository) List(ctx context.Context, opts summary.ListOptions)
 		var subCreatedAt, subUpdatedAt sql.NullTime
 
 		if err := rows.Scan(
-			&s.ID, &s.Title, &s.Description, &s.Content, &s.CategoryID, &s.SubcategoryID, &s.UserID, &s.CreatedAt, &s.UpdatedAt,
+			&s.ID, &s.Title, &s.Description, &s.Content, &s.CategoryID, &s.SubcategoryID, &s.UserID, &s.PublishedAt, &s.CreatedAt, &s.UpdatedAt,
 			&u.ID, &u.Name, &u.Email, &u.UserType, &u.CreatedAt, &u.UpdatedAt,
 			&catID, &catName, &catCreatedAt, &catUpdatedAt,
 			&subID, &subCategoryID, &subName, &subCreatedAt, &subUpdatedAt,
@@ -169,14 +179,14 @@ func (s *summaryRepository) List(ctx context.Context, opts summary.ListOptions)
 }
 
 func (s *summaryRepository) Detail(ctx context.Context, model *summary.Summary) (*summary.Summary, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection not found in context")
 	}
 
 	query := `
 		SELECT 
-			s.id, s.title, s.description, s.content, s.category_id, s.subcategory_id, s.user_id, s.created_at, s.updated_at,
+			s.id, s.title, s.description, s.content, s.category_id, s.subcategory_id, s.user_id, s.published_at, s.created_at, s.updated_at,
 			u.id, u.name, u.email, u.user_type, u.created_at, u.updated_at,
 			c.id, c.name, c.created_at, c.updated_at,
 			sc.id, sc.category_id, sc.name, sc.created_at, sc.updated_at
@@ -202,6 +212,7 @@ func (s *summaryRepository) Detail(ctx context.Context, model *summary.Summary)
 		&result.CategoryID,
 		&result.SubcategoryID,
 		&result.UserID,
+		&result.PublishedAt,
 		&result.CreatedAt,
 		&result.UpdatedAt,
 		&userID,
@@ -221,8 +232,8 @@ func (s *summaryRepository) Detail(ctx context.Context, model *summary.Summary)
 		&subUpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("summary not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("failed to get summary detail: %w", err)
 	}
@@ -272,8 +283,8 @@ func (s *summaryRepository) Delete(ctx context.Context, model *summary.Summary)
 		return fmt.Errorf("database connection not found in context")
 	}
 
-	query := `UPDATE summaries SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL`
-	result, err := db.ExecContext(ctx, query, model.ID)
+	query := fmt.Sprintf(`UPDATE summaries SET deleted_at = %s WHERE id = ? AND deleted_at IS NULL`, s.dialect.Now())
+	result, err := execContext(ctx, s.dialect, db, query, model.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete summary: %w", err)
 	}
@@ -284,8 +295,41 @@ func (s *summaryRepository) Delete(ctx context.Context, model *summary.Summary)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("summary not found")
+		return fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
 	}
 
 	return nil
 }
+
+// Publish は未公開のサマリーに公開日時を記録します
+// 更新できなかった場合は、サマリーが存在するかで公開済みか存在しないかを判定します
+func (s *summaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	now := s.dialect.Now()
+	query := fmt.Sprintf(`UPDATE summaries SET published_at = %s, updated_at = %s WHERE id = ? AND deleted_at IS NULL AND published_at IS NULL`, now, now)
+	result, err := execContext(ctx, s.dialect, db, query, model.ID)
+	if err != nil {
+		return fmt.Errorf("failed to publish summary: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM summaries WHERE id = ? AND deleted_at IS NULL`, model.ID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to publish summary: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("summary not found: %w", Errors.ErrRecordNotFound)
+	}
+	return fmt.Errorf("summary already published: %w", Errors.ErrConflict)
+}
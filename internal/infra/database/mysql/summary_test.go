@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/o-ga09/web-ya-hime/internal/domain"
 	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,11 +20,12 @@ func TestSummaryRepository_Save(t *testing.T) {
 	repo := NewSummaryRepository()
 
 	tests := []struct {
-		name    string
-		summary *summary.Summary
-		mockFn  func(mock sqlmock.Sqlmock)
-		wantErr bool
-		errMsg  string
+		name      string
+		summary   *summary.Summary
+		mockFn    func(mock sqlmock.Sqlmock)
+		wantErr   bool
+		errMsg    string
+		wantErrIs error
 	}{
 		{
 			name: "成功ケース: サマリーが正常に保存される",
@@ -76,8 +79,72 @@ func TestSummaryRepository_Save(t *testing.T) {
 			wantErr: true,
 			errMsg:  "failed to save summary",
 		},
+		{
+			name: "失敗ケース: 存在しないカテゴリIDを指定",
+			summary: &summary.Summary{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "test-id-4",
+				},
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+				CategoryID:  sql.NullString{String: "missing-category", Valid: true},
+				UserID:      "user-id-1",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO summaries").
+					WillReturnError(&driver.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails"})
+			},
+			wantErr:   true,
+			errMsg:    "failed to save summary",
+			wantErrIs: Errors.ErrForeignKeyConstraint,
+		},
+		{
+			name: "成功ケース: デッドロックを再試行して保存される",
+			summary: &summary.Summary{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "test-id-5",
+				},
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+				UserID:      "user-id-1",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO summaries").
+					WillReturnError(&driver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+				mock.ExpectExec("INSERT INTO summaries").
+					WillReturnError(&driver.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"})
+				mock.ExpectExec("INSERT INTO summaries").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "失敗ケース: デッドロックが再試行の上限まで続く",
+			summary: &summary.Summary{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "test-id-6",
+				},
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+				UserID:      "user-id-1",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				for i := 0; i < 3; i++ {
+					mock.ExpectExec("INSERT INTO summaries").
+						WillReturnError(&driver.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+				}
+			},
+			wantErr:   true,
+			errMsg:    "failed to save summary",
+			wantErrIs: Errors.ErrRetryable,
+		},
 	}
 
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = 50 * time.Millisecond })
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			db, mock, err := sqlmock.New()
@@ -96,6 +163,9 @@ func TestSummaryRepository_Save(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -127,16 +197,16 @@ func TestSummaryRepository_List(t *testing.T) {
 
 				// SELECT query
 				rows := sqlmock.NewRows([]string{
-					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "created_at", "updated_at",
+					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "published_at", "created_at", "updated_at",
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 					"id", "name", "created_at", "updated_at",
 					"id", "category_id", "name", "created_at", "updated_at",
 				}).
-					AddRow("summary-1", "Title 1", "Description 1", "Content 1", nil, nil, "user-1", now, now,
+					AddRow("summary-1", "Title 1", "Description 1", "Content 1", nil, nil, "user-1", nil, now, now,
 						"user-1", "User Name 1", "user1@example.com", "admin", now, now,
 						nil, nil, nil, nil,
 						nil, nil, nil, nil, nil).
-					AddRow("summary-2", "Title 2", "Description 2", "Content 2", nil, nil, "user-2", now, now,
+					AddRow("summary-2", "Title 2", "Description 2", "Content 2", nil, nil, "user-2", nil, now, now,
 						"user-2", "User Name 2", "user2@example.com", "user", now, now,
 						nil, nil, nil, nil,
 						nil, nil, nil, nil, nil)
@@ -155,7 +225,7 @@ func TestSummaryRepository_List(t *testing.T) {
 				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
 
 				rows := sqlmock.NewRows([]string{
-					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "created_at", "updated_at",
+					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "published_at", "created_at", "updated_at",
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 					"id", "name", "created_at", "updated_at",
 					"id", "category_id", "name", "created_at", "updated_at",
@@ -240,12 +310,12 @@ func TestSummaryRepository_Detail(t *testing.T) {
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{
-					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "created_at", "updated_at",
+					"id", "title", "description", "content", "category_id", "subcategory_id", "user_id", "published_at", "created_at", "updated_at",
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 					"id", "name", "created_at", "updated_at",
 					"id", "category_id", "name", "created_at", "updated_at",
 				}).
-					AddRow("summary-1", "Title 1", "Description 1", "Content 1", nil, nil, "user-1", now, now,
+					AddRow("summary-1", "Title 1", "Description 1", "Content 1", nil, nil, "user-1", nil, now, now,
 						"user-1", "User Name 1", "user1@example.com", "admin", now, now,
 						"cat-1", "雑談", now, now,
 						nil, nil, nil, nil, nil)
@@ -362,7 +432,7 @@ func TestSummaryRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE summaries SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE summaries SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("summary-1").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -376,7 +446,7 @@ func TestSummaryRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE summaries SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE summaries SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("non-existent").
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
@@ -402,7 +472,7 @@ func TestSummaryRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE summaries SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE summaries SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("summary-1").
 					WillReturnError(fmt.Errorf("delete error"))
 			},
@@ -439,3 +509,97 @@ func TestSummaryRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestSummaryRepository_Publish(t *testing.T) {
+	repo := NewSummaryRepository()
+	publishQuery := "UPDATE summaries SET published_at = CURRENT_TIMESTAMP\\(6\\), updated_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL AND published_at IS NULL"
+	countQuery := "SELECT COUNT\\(\\*\\) FROM summaries WHERE id = \\? AND deleted_at IS NULL"
+
+	tests := []struct {
+		name      string
+		id        string
+		mockFn    func(mock sqlmock.Sqlmock)
+		wantErr   bool
+		errMsg    string
+		wantErrIs error
+	}{
+		{
+			name: "成功ケース: サマリーが公開される",
+			id:   "summary-1",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(publishQuery).
+					WithArgs("summary-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "失敗ケース: サマリーが見つからない",
+			id:   "non-existent",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(publishQuery).
+					WithArgs("non-existent").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(countQuery).
+					WithArgs("non-existent").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			wantErr:   true,
+			errMsg:    "summary not found",
+			wantErrIs: Errors.ErrRecordNotFound,
+		},
+		{
+			name: "失敗ケース: すでに公開されている",
+			id:   "summary-1",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(publishQuery).
+					WithArgs("summary-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(countQuery).
+					WithArgs("summary-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			wantErr:   true,
+			errMsg:    "summary already published",
+			wantErrIs: Errors.ErrConflict,
+		},
+		{
+			name:    "失敗ケース: データベース接続がcontextに存在しない",
+			id:      "summary-1",
+			mockFn:  func(mock sqlmock.Sqlmock) {},
+			wantErr: true,
+			errMsg:  "database connection not found in context",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.mockFn(mock)
+
+			ctx := context.Background()
+			if tt.name != "失敗ケース: データベース接続がcontextに存在しない" {
+				ctx = Ctx.SetDB(ctx, db)
+			}
+
+			err = repo.Publish(ctx, &summary.Summary{WYHBaseModel: domain.WYHBaseModel{ID: tt.id}})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.name != "失敗ケース: データベース接続がcontextに存在しない" {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}
+		})
+	}
+}
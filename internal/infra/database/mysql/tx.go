@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+)
+
+type savepointDepthKey struct{}
+
+type txManager struct {
+	dialect Dialect
+}
+
+func NewTxManager() domain.ITxManager {
+	return NewTxManagerWithDialect(MySQL)
+}
+
+// NewTxManagerWithDialect は MySQL 以外の方言を使うトランザクションマネージャーを生成します
+func NewTxManagerWithDialect(d Dialect) domain.ITxManager {
+	return &txManager{dialect: d}
+}
+
+// WithinTx は fn を1つのトランザクションで実行します
+// デッドロックやロック待ちタイムアウトで失敗した場合は、トランザクション全体を最初からやり直します
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...domain.TxOption) error {
+	if tx := Ctx.GetTx(ctx); tx != nil {
+		return m.withinSavepoint(ctx, tx, fn)
+	}
+
+	var o domain.TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return withRetry(ctx, func() error {
+		return m.withinNewTx(ctx, fn, o)
+	})
+}
+
+func (m *txManager) withinNewTx(ctx context.Context, fn func(ctx context.Context) error, o domain.TxOptions) (err error) {
+	db := Ctx.GetPool(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", m.dialect.TranslateError(err))
+	}
+
+	// fn がパニックした場合もロールバックしてからパニックを伝播させる
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	ctx, runAfterCommit := Ctx.WithAfterCommit(Ctx.SetTx(ctx, tx))
+	if err := fn(ctx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", m.dialect.TranslateError(err))
+	}
+	runAfterCommit()
+	return nil
+}
+
+// withinSavepoint はトランザクション中に呼ばれた WithinTx をセーブポイントで実行します
+// セーブポイント名はネストの深さで決めるため、同じ深さのセーブポイントは解放後に同じ名前を再利用します
+func (m *txManager) withinSavepoint(ctx context.Context, tx *sql.Tx, fn func(ctx context.Context) error) error {
+	depth, _ := ctx.Value(savepointDepthKey{}).(int)
+	depth++
+	name := fmt.Sprintf("sp_%d", depth)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", m.dialect.TranslateError(err))
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, savepointDepthKey{}, depth)); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback to savepoint: %w", rbErr))
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", m.dialect.TranslateError(err))
+	}
+	return nil
+}
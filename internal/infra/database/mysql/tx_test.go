@@ -0,0 +1,193 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxManager_WithinTx(t *testing.T) {
+	orig := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = orig })
+
+	txm := NewTxManager()
+	errFn := errors.New("fn error")
+
+	tests := []struct {
+		name      string
+		mockFn    func(mock sqlmock.Sqlmock)
+		fn        func(ctx context.Context) error
+		opts      []domain.TxOption
+		wantErr   bool
+		errMsg    string
+		wantErrIs error
+	}{
+		{
+			name: "成功ケース: コミットされる",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO summaries").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				// トランザクション中は Ctx.GetDB がトランザクションを返す
+				if Ctx.GetTx(ctx) == nil {
+					return errors.New("tx not found in context")
+				}
+				_, err := execContext(ctx, MySQL, Ctx.GetDB(ctx), "INSERT INTO summaries (id) VALUES (?)", "id")
+				return err
+			},
+		},
+		{
+			name: "成功ケース: 分離レベルを指定できる",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			fn:   func(ctx context.Context) error { return nil },
+			opts: []domain.TxOption{domain.WithIsolation(sql.LevelSerializable)},
+		},
+		{
+			name: "成功ケース: ネストしたトランザクションはセーブポイントを解放する",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				return txm.WithinTx(ctx, func(ctx context.Context) error {
+					return txm.WithinTx(ctx, func(ctx context.Context) error { return nil })
+				})
+			},
+		},
+		{
+			name: "成功ケース: ネストしたトランザクションの失敗はセーブポイントまでロールバックする",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				// 内側のエラーを握りつぶした場合、外側のトランザクションはコミットされる
+				_ = txm.WithinTx(ctx, func(ctx context.Context) error { return errFn })
+				return nil
+			},
+		},
+		{
+			name: "成功ケース: デッドロックの場合はトランザクション全体をリトライする",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO summaries").WillReturnError(&driver.MySQLError{Number: 1213, Message: "Deadlock found"})
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO summaries").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context) error {
+				_, err := execContext(ctx, MySQL, Ctx.GetDB(ctx), "INSERT INTO summaries (id) VALUES (?)", "id")
+				return err
+			},
+		},
+		{
+			name: "失敗ケース: fnがエラーを返した場合はロールバックする",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn:        func(ctx context.Context) error { return errFn },
+			wantErr:   true,
+			errMsg:    "fn error",
+			wantErrIs: errFn,
+		},
+		{
+			name: "失敗ケース: 一意制約違反はリトライしない",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO users").WillReturnError(&driver.MySQLError{Number: 1062, Message: "Duplicate entry"})
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context) error {
+				_, err := execContext(ctx, MySQL, Ctx.GetDB(ctx), "INSERT INTO users (id) VALUES (?)", "id")
+				return err
+			},
+			wantErr:   true,
+			errMsg:    "Duplicate entry",
+			wantErrIs: Errors.ErrUniqueConstraint,
+		},
+		{
+			name: "失敗ケース: トランザクションを開始できない",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+			},
+			fn:      func(ctx context.Context) error { return nil },
+			wantErr: true,
+			errMsg:  "failed to begin transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.mockFn(mock)
+			ctx := Ctx.SetDB(context.Background(), db)
+
+			err = txm.WithinTx(ctx, tt.fn, tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTxManager_WithinTx_Panic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	txm := NewTxManager()
+	ctx := Ctx.SetDB(context.Background(), db)
+
+	// パニックした場合もロールバックしてからパニックを伝播させる
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = txm.WithinTx(ctx, func(ctx context.Context) error {
+			return txm.WithinTx(ctx, func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_WithinTx_NoDB(t *testing.T) {
+	err := NewTxManager().WithinTx(context.Background(), func(ctx context.Context) error { return nil })
+	assert.ErrorContains(t, err, "database connection not found in context")
+}
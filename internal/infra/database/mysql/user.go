@@ -3,10 +3,13 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 )
 
 type User struct {
@@ -15,10 +18,17 @@ type User struct {
 	Name     string
 	Email    string
 	UserType string
+
+	dialect Dialect
 }
 
 func NewUserRepository() user.IUserRepository {
-	return &User{}
+	return NewUserRepositoryWithDialect(MySQL)
+}
+
+// NewUserRepositoryWithDialect は MySQL 以外の方言を使うユーザーリポジトリを生成します
+func NewUserRepositoryWithDialect(d Dialect) user.IUserRepository {
+	return &User{dialect: d}
 }
 
 func (u *User) Save(ctx context.Context, model *user.User) error {
@@ -27,8 +37,13 @@ func (u *User) Save(ctx context.Context, model *user.User) error {
 		return fmt.Errorf("database connection not found in context")
 	}
 
-	query := `INSERT INTO users (id, name, email, user_type, created_at, updated_at) VALUES (?, ?, ?, ?, NOW(), NOW())`
-	_, err := db.ExecContext(ctx, query, model.ID, model.Name, model.Email, model.UserType)
+	if err := u.checkEmailDuplicate(ctx, db, model); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	now := u.dialect.Now()
+	query := fmt.Sprintf(`INSERT INTO users (id, name, email, user_type, created_at, updated_at) VALUES (?, ?, ?, ?, %s, %s)`, now, now)
+	_, err := execContext(ctx, u.dialect, db, query, model.ID, model.Name, model.Email, model.UserType)
 	if err != nil {
 		return fmt.Errorf("failed to save user: %w", err)
 	}
@@ -36,14 +51,75 @@ func (u *User) Save(ctx context.Context, model *user.User) error {
 	return nil
 }
 
-func (u *User) List(ctx context.Context) (user.UserSlice, error) {
+func (u *User) Update(ctx context.Context, model *user.User) error {
 	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection not found in context")
+	}
+
+	if err := u.checkEmailDuplicate(ctx, db, model); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	query := fmt.Sprintf(`UPDATE users SET name = ?, email = ?, user_type = ?, updated_at = %s WHERE id = ? AND deleted_at IS NULL`, u.dialect.Now())
+	result, err := execContext(ctx, u.dialect, db, query, model.Name, model.Email, model.UserType, model.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+func (u *User) List(ctx context.Context, opts user.ListOptions) (*user.ListResult, error) {
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection not found in context")
 	}
 
-	query := `SELECT id, name, email, user_type, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY created_at DESC`
-	rows, err := db.QueryContext(ctx, query)
+	// デフォルト値の設定
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	// WHERE句の構築
+	whereClause := "WHERE deleted_at IS NULL"
+	args := []interface{}{}
+	if opts.Email != "" {
+		whereClause += " AND email = ?"
+		args = append(args, opts.Email)
+	}
+	if opts.Name != "" {
+		whereClause += " AND " + u.dialect.Like("name")
+		args = append(args, "%"+escapeLike(opts.Name)+"%")
+	}
+	if opts.UserType != "" {
+		whereClause += " AND user_type = ?"
+		args = append(args, opts.UserType)
+	}
+
+	// 総件数を取得
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM users %s`, whereClause)
+	var total int
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	// データ取得
+	query := fmt.Sprintf(`SELECT id, name, email, user_type, created_at, updated_at FROM users %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
+	queryArgs := append(args, opts.Limit+1, opts.Offset) // +1で次のページの有無を判定
+	rows, err := db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user list: %w", err)
 	}
@@ -62,11 +138,23 @@ func (u *User) List(ctx context.Context) (user.UserSlice, error) {
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
-	return users, nil
+	// 次のページがあるか判定
+	hasNext := len(users) > opts.Limit
+	if hasNext {
+		users = users[:opts.Limit]
+	}
+
+	return &user.ListResult{
+		Items:   users,
+		Total:   total,
+		Limit:   opts.Limit,
+		Offset:  opts.Offset,
+		HasNext: hasNext,
+	}, nil
 }
 
 func (u *User) Detail(ctx context.Context, model *user.User) (*user.User, error) {
-	db := Ctx.GetDB(ctx)
+	db := Ctx.GetReadDB(ctx)
 	if db == nil {
 		return nil, fmt.Errorf("database connection not found in context")
 	}
@@ -82,8 +170,8 @@ func (u *User) Detail(ctx context.Context, model *user.User) (*user.User, error)
 		&result.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("failed to get user detail: %w", err)
 	}
@@ -97,8 +185,8 @@ func (u *User) Delete(ctx context.Context, model *user.User) error {
 		return fmt.Errorf("database connection not found in context")
 	}
 
-	query := `UPDATE users SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL`
-	result, err := db.ExecContext(ctx, query, model.ID)
+	query := fmt.Sprintf(`UPDATE users SET deleted_at = %s WHERE id = ? AND deleted_at IS NULL`, u.dialect.Now())
+	result, err := execContext(ctx, u.dialect, db, query, model.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -109,8 +197,29 @@ func (u *User) Delete(ctx context.Context, model *user.User) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("user not found: %w", Errors.ErrRecordNotFound)
 	}
 
 	return nil
 }
+
+// checkEmailDuplicate は大文字小文字のみが異なるメールアドレスを含めて重複を検出します
+// email カラムの照合順序 (MySQL は _ci、SQLite は NOCASE) が大文字小文字を区別しないため、インデックスを使える = で比較します
+// ユニーク制約は論理削除済みのユーザーにも掛かるため、削除済みの行も対象にします
+func (u *User) checkEmailDuplicate(ctx context.Context, db Ctx.Querier, model *user.User) error {
+	query := `SELECT COUNT(*) FROM users WHERE email = ? AND id <> ?`
+	var count int
+	if err := db.QueryRowContext(ctx, query, model.Email, model.ID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check email duplicate: %w", err)
+	}
+	if count > 0 {
+		return Errors.ErrUniqueConstraint
+	}
+	return nil
+}
+
+// escapeLike はLIKE句のワイルドカード文字をエスケープします
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/o-ga09/web-ya-hime/internal/domain"
 	"github.com/o-ga09/web-ya-hime/internal/domain/user"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,11 +20,12 @@ func TestUserRepository_Save(t *testing.T) {
 	repo := NewUserRepository()
 
 	tests := []struct {
-		name    string
-		user    *user.User
-		mockFn  func(mock sqlmock.Sqlmock)
-		wantErr bool
-		errMsg  string
+		name      string
+		user      *user.User
+		mockFn    func(mock sqlmock.Sqlmock)
+		wantErr   bool
+		errMsg    string
+		wantErrIs error
 	}{
 		{
 			name: "成功ケース: ユーザーが正常に保存される",
@@ -35,6 +38,9 @@ func TestUserRepository_Save(t *testing.T) {
 				UserType: "admin",
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("test@example.com", "test-user-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 				mock.ExpectExec("INSERT INTO users").
 					WithArgs("test-user-1", "Test User", "test@example.com", "admin").
 					WillReturnResult(sqlmock.NewResult(1, 1))
@@ -66,6 +72,9 @@ func TestUserRepository_Save(t *testing.T) {
 				UserType: "admin",
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("test@example.com", "test-user-3").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 				mock.ExpectExec("INSERT INTO users").
 					WithArgs("test-user-3", "Test User", "test@example.com", "admin").
 					WillReturnError(fmt.Errorf("db error"))
@@ -73,6 +82,47 @@ func TestUserRepository_Save(t *testing.T) {
 			wantErr: true,
 			errMsg:  "failed to save user",
 		},
+		{
+			name: "失敗ケース: メールアドレスが重複",
+			user: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "test-user-4",
+				},
+				Name:     "Test User",
+				Email:    "test@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("test@example.com", "test-user-4").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("INSERT INTO users").
+					WithArgs("test-user-4", "Test User", "test@example.com", "admin").
+					WillReturnError(&driver.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			},
+			wantErr:   true,
+			errMsg:    "failed to save user",
+			wantErrIs: Errors.ErrUniqueConstraint,
+		},
+		{
+			name: "失敗ケース: 大文字小文字のみ異なるメールアドレスが登録済み",
+			user: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "test-user-5",
+				},
+				Name:     "Test User",
+				Email:    "Test@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("Test@example.com", "test-user-5").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			wantErr:   true,
+			errMsg:    "failed to save user",
+			wantErrIs: Errors.ErrUniqueConstraint,
+		},
 	}
 
 	for _, tt := range tests {
@@ -93,6 +143,9 @@ func TestUserRepository_Save(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -109,15 +162,21 @@ func TestUserRepository_List(t *testing.T) {
 	now := time.Now()
 
 	tests := []struct {
-		name    string
-		mockFn  func(mock sqlmock.Sqlmock)
-		want    int
-		wantErr bool
-		errMsg  string
+		name        string
+		opts        user.ListOptions
+		mockFn      func(mock sqlmock.Sqlmock)
+		want        int
+		wantHasNext bool
+		wantErr     bool
+		errMsg      string
 	}{
 		{
 			name: "成功ケース: ユーザー一覧を取得",
+			opts: user.ListOptions{Limit: 20, Offset: 0},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(2)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
+
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 				}).
@@ -125,14 +184,61 @@ func TestUserRepository_List(t *testing.T) {
 					AddRow("user-2", "User Name 2", "user2@example.com", "user", now, now)
 
 				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(21, 0).
 					WillReturnRows(rows)
 			},
 			want:    2,
 			wantErr: false,
 		},
+		{
+			name: "成功ケース: 検索条件で絞り込む",
+			opts: user.ListOptions{Email: "sato@example.com", Name: "佐藤_%", UserType: "user", Limit: 10, Offset: 10},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE deleted_at IS NULL AND email = \\? AND name LIKE \\? AND user_type = \\?").
+					WithArgs("sato@example.com", "%佐藤\\_\\%%", "user").
+					WillReturnRows(countRows)
+
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "email", "user_type", "created_at", "updated_at",
+				}).
+					AddRow("user-2", "佐藤_%", "sato@example.com", "user", now, now)
+
+				mock.ExpectQuery("SELECT (.+) FROM users WHERE deleted_at IS NULL AND email = \\? AND name LIKE \\? AND user_type = \\?").
+					WithArgs("sato@example.com", "%佐藤\\_\\%%", "user", 11, 10).
+					WillReturnRows(rows)
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "成功ケース: 次のページが存在する",
+			opts: user.ListOptions{Limit: 1, Offset: 0},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(2)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
+
+				rows := sqlmock.NewRows([]string{
+					"id", "name", "email", "user_type", "created_at", "updated_at",
+				}).
+					AddRow("user-1", "User Name 1", "user1@example.com", "admin", now, now).
+					AddRow("user-2", "User Name 2", "user2@example.com", "user", now, now)
+
+				mock.ExpectQuery("SELECT (.+) FROM users").
+					WithArgs(2, 0).
+					WillReturnRows(rows)
+			},
+			want:        1,
+			wantHasNext: true,
+			wantErr:     false,
+		},
 		{
 			name: "成功ケース: 空の結果",
+			opts: user.ListOptions{Limit: 20, Offset: 0},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(0)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
+
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 				})
@@ -144,13 +250,28 @@ func TestUserRepository_List(t *testing.T) {
 		},
 		{
 			name:    "失敗ケース: データベース接続がcontextに存在しない",
+			opts:    user.ListOptions{Limit: 20, Offset: 0},
 			mockFn:  func(mock sqlmock.Sqlmock) {},
 			wantErr: true,
 			errMsg:  "database connection not found in context",
 		},
+		{
+			name: "失敗ケース: 件数取得エラー",
+			opts: user.ListOptions{Limit: 20, Offset: 0},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT").
+					WillReturnError(fmt.Errorf("count error"))
+			},
+			wantErr: true,
+			errMsg:  "failed to get total count",
+		},
 		{
 			name: "失敗ケース: クエリエラー",
+			opts: user.ListOptions{Limit: 20, Offset: 0},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
+
 				mock.ExpectQuery("SELECT (.+) FROM users").
 					WillReturnError(fmt.Errorf("query error"))
 			},
@@ -159,7 +280,11 @@ func TestUserRepository_List(t *testing.T) {
 		},
 		{
 			name: "失敗ケース: スキャンエラー",
+			opts: user.ListOptions{Limit: 20, Offset: 0},
 			mockFn: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(countRows)
+
 				rows := sqlmock.NewRows([]string{
 					"id", "name", "email", "user_type", "created_at", "updated_at",
 				}).
@@ -186,7 +311,7 @@ func TestUserRepository_List(t *testing.T) {
 				ctx = Ctx.SetDB(ctx, db)
 			}
 
-			result, err := repo.List(ctx)
+			result, err := repo.List(ctx, tt.opts)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -194,7 +319,149 @@ func TestUserRepository_List(t *testing.T) {
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, result, tt.want)
+				assert.Len(t, result.Items, tt.want)
+				assert.Equal(t, tt.wantHasNext, result.HasNext)
+			}
+
+			if tt.name != "失敗ケース: データベース接続がcontextに存在しない" {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}
+		})
+	}
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	repo := NewUserRepository()
+
+	tests := []struct {
+		name      string
+		input     *user.User
+		mockFn    func(mock sqlmock.Sqlmock)
+		wantErr   bool
+		errMsg    string
+		wantErrIs error
+	}{
+		{
+			name: "成功ケース: ユーザーが更新される",
+			input: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "user-1",
+				},
+				Name:     "Updated User",
+				Email:    "updated@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("updated@example.com", "user-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE users SET name = \\?, email = \\?, user_type = \\?, updated_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs("Updated User", "updated@example.com", "admin", "user-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantErr: false,
+		},
+		{
+			name: "失敗ケース: ユーザーが見つからない",
+			input: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "non-existent",
+				},
+				Name:     "Updated User",
+				Email:    "updated@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("updated@example.com", "non-existent").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE users").
+					WithArgs("Updated User", "updated@example.com", "admin", "non-existent").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr:   true,
+			errMsg:    "user not found",
+			wantErrIs: Errors.ErrRecordNotFound,
+		},
+		{
+			name: "失敗ケース: メールアドレスが重複",
+			input: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "user-1",
+				},
+				Name:     "Updated User",
+				Email:    "duplicate@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("duplicate@example.com", "user-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE users").
+					WithArgs("Updated User", "duplicate@example.com", "admin", "user-1").
+					WillReturnError(&driver.MySQLError{Number: 1062, Message: "Duplicate entry"})
+			},
+			wantErr:   true,
+			errMsg:    "failed to update user",
+			wantErrIs: Errors.ErrUniqueConstraint,
+		},
+		{
+			name: "失敗ケース: データベース接続がcontextに存在しない",
+			input: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "user-1",
+				},
+			},
+			mockFn:  func(mock sqlmock.Sqlmock) {},
+			wantErr: true,
+			errMsg:  "database connection not found in context",
+		},
+		{
+			name: "失敗ケース: 更新エラー",
+			input: &user.User{
+				WYHBaseModel: domain.WYHBaseModel{
+					ID: "user-1",
+				},
+				Name:     "Updated User",
+				Email:    "updated@example.com",
+				UserType: "admin",
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM users WHERE email = \\? AND id <> \\?").
+					WithArgs("updated@example.com", "user-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE users").
+					WithArgs("Updated User", "updated@example.com", "admin", "user-1").
+					WillReturnError(fmt.Errorf("update error"))
+			},
+			wantErr: true,
+			errMsg:  "failed to update user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.mockFn(mock)
+
+			ctx := context.Background()
+			if tt.name != "失敗ケース: データベース接続がcontextに存在しない" {
+				ctx = Ctx.SetDB(ctx, db)
+			}
+
+			err = repo.Update(ctx, tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+			} else {
+				assert.NoError(t, err)
 			}
 
 			if tt.name != "失敗ケース: データベース接続がcontextに存在しない" {
@@ -336,7 +603,7 @@ func TestUserRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE users SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE users SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("user-1").
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
@@ -350,7 +617,7 @@ func TestUserRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE users SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE users SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("non-existent").
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
@@ -376,7 +643,7 @@ func TestUserRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE users SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE users SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("user-1").
 					WillReturnError(fmt.Errorf("delete error"))
 			},
@@ -391,7 +658,7 @@ func TestUserRepository_Delete(t *testing.T) {
 				},
 			},
 			mockFn: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec("UPDATE users SET deleted_at = NOW\\(\\) WHERE id = \\? AND deleted_at IS NULL").
+				mock.ExpectExec("UPDATE users SET deleted_at = CURRENT_TIMESTAMP\\(6\\) WHERE id = \\? AND deleted_at IS NULL").
 					WithArgs("user-1").
 					WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("rows affected error")))
 			},
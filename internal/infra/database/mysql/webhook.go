@@ -0,0 +1,405 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+)
+
+type webhookRepository struct {
+	dialect Dialect
+}
+
+func NewWebhookRepository() webhook.IWebhookRepository {
+	return NewWebhookRepositoryWithDialect(MySQL)
+}
+
+// NewWebhookRepositoryWithDialect は MySQL 以外の方言を使うWebhookのリポジトリを生成します
+func NewWebhookRepositoryWithDialect(d Dialect) webhook.IWebhookRepository {
+	return &webhookRepository{dialect: d}
+}
+
+// Save はWebhookを保存します
+// 更新時に登録したユーザーは変更しません
+func (r *webhookRepository) Save(ctx context.Context, model *webhook.Webhook) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection is not set in context")
+	}
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+
+	now := r.dialect.Now()
+	query := fmt.Sprintf(`
+		INSERT INTO webhooks (id, user_id, url, secret, event_types, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, %s, %s)
+		%s
+	`, now, now, r.dialect.Upsert("id", "url", "secret", "event_types", "active"))
+
+	_, err := execContext(ctx, r.dialect, db, query, model.ID, model.UserID, model.URL, model.Secret, joinEventTypes(model.EventTypes), model.Active)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) List(ctx context.Context, userID string) (webhook.WebhookSlice, error) {
+	db := Ctx.GetReadDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+
+	query := `
+		SELECT id, user_id, url, secret, event_types, active, created_at, updated_at
+		FROM webhooks
+	`
+	var args []any
+	if userID != "" {
+		query += " WHERE user_id = ?"
+		args = append(args, userID)
+	}
+	query += " ORDER BY created_at DESC, id"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks webhook.WebhookSlice
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Detail(ctx context.Context, model *webhook.Webhook) (*webhook.Webhook, error) {
+	db := Ctx.GetReadDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+
+	query := `
+		SELECT id, user_id, url, secret, event_types, active, created_at, updated_at
+		FROM webhooks
+		WHERE id = ?
+	`
+
+	w, err := scanWebhook(db.QueryRowContext(ctx, query, model.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook not found: %w", Errors.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("failed to get webhook detail: %w", err)
+	}
+
+	return w, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, model *webhook.Webhook) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection is not set in context")
+	}
+
+	// 配信履歴は外部キー制約の ON DELETE CASCADE で削除される
+	result, err := execContext(ctx, r.dialect, db, `DELETE FROM webhooks WHERE id = ?`, model.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found: %w", Errors.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(s scanner) (*webhook.Webhook, error) {
+	var (
+		w          webhook.Webhook
+		eventTypes string
+	)
+	if err := s.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &eventTypes, &w.Active, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	w.EventTypes = splitEventTypes(eventTypes)
+	return &w, nil
+}
+
+// joinEventTypes は購読するイベントの種類をカンマ区切りで保存します
+// イベントの種類にカンマは含まれないため、JSON型を使わずにMySQLとSQLiteで同じ形式にします
+func joinEventTypes(types []event.Type) string {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return strings.Join(s, ",")
+}
+
+func splitEventTypes(s string) []event.Type {
+	types := []event.Type{}
+	for _, t := range strings.Split(s, ",") {
+		if t != "" {
+			types = append(types, event.Type(t))
+		}
+	}
+	return types
+}
+
+type webhookDeliveryRepository struct {
+	dialect Dialect
+}
+
+func NewWebhookDeliveryRepository() webhook.IDeliveryRepository {
+	return NewWebhookDeliveryRepositoryWithDialect(MySQL)
+}
+
+// NewWebhookDeliveryRepositoryWithDialect は MySQL 以外の方言を使うWebhookの配信履歴のリポジトリを生成します
+func NewWebhookDeliveryRepositoryWithDialect(d Dialect) webhook.IDeliveryRepository {
+	return &webhookDeliveryRepository{dialect: d}
+}
+
+const deliveryColumns = `seq, id, webhook_id, event_id, event_type, payload, dedup_key, redelivery_of, status, attempts, response_status, last_error, next_attempt_at, created_at, delivered_at`
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, model *webhook.Delivery) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection is not set in context")
+	}
+
+	if model.ID == "" {
+		model.ID = uuid.GenerateID()
+	}
+	if model.Status == "" {
+		model.Status = webhook.DeliveryStatusPending
+	}
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	if model.NextAttemptAt.IsZero() {
+		model.NextAttemptAt = model.CreatedAt
+	}
+
+	// 再送は DedupKey を NULL にして、一意キーの対象から外す
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, dedup_key, redelivery_of, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
+	`
+	_, err := execContext(ctx, r.dialect, db, query,
+		model.ID, model.WebhookID, model.EventID, model.EventType, string(model.Payload),
+		nullString(model.DedupKey), nullString(model.RedeliveryOf), model.Status,
+		utcTime(model.NextAttemptAt), utcTime(model.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) List(ctx context.Context, webhookID string, limit, offset int) (*webhook.DeliveryListResult, error) {
+	db := Ctx.GetReadDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = ?`, webhookID).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	// 次のページがあるかを判定するために1件多く取得する
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY seq DESC
+		LIMIT ? OFFSET ?
+	`, webhookID, limit+1, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(deliveries) > limit
+	if hasNext {
+		deliveries = deliveries[:limit]
+	}
+
+	return &webhook.DeliveryListResult{
+		Items:   deliveries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasNext: hasNext,
+	}, nil
+}
+
+func (r *webhookDeliveryRepository) Detail(ctx context.Context, model *webhook.Delivery) (*webhook.Delivery, error) {
+	db := Ctx.GetReadDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+
+	d, err := scanDelivery(db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = ?`, model.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook delivery not found: %w", Errors.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery detail: %w", err)
+	}
+	return d, nil
+}
+
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return nil, fmt.Errorf("database connection is not set in context")
+	}
+
+	// アウトボックスと同じく、対象の行に取得用のトークンを設定してからトークンで取得する
+	token := uuid.GenerateID()
+	now := time.Now()
+	query := `
+		UPDATE webhook_deliveries SET claim_token = ?, next_attempt_at = ?
+		WHERE status = ? AND next_attempt_at <= ? AND id IN (
+			SELECT id FROM (
+				SELECT id FROM webhook_deliveries
+				WHERE status = ? AND next_attempt_at <= ?
+				ORDER BY seq
+				LIMIT ?
+			) AS due
+		)
+	`
+	_, err := execContext(ctx, r.dialect, db, query,
+		token, utcTime(now.Add(lease)),
+		webhook.DeliveryStatusPending, utcTime(now),
+		webhook.DeliveryStatusPending, utcTime(now), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE claim_token = ? ORDER BY seq`, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claimed webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func (r *webhookDeliveryRepository) UpdateResult(ctx context.Context, model *webhook.Delivery) error {
+	db := Ctx.GetDB(ctx)
+	if db == nil {
+		return fmt.Errorf("database connection is not set in context")
+	}
+
+	var deliveredAt any
+	if model.DeliveredAt != nil {
+		deliveredAt = utcTime(*model.DeliveredAt)
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = ?, last_error = ?, next_attempt_at = ?, delivered_at = ?, claim_token = NULL
+		WHERE id = ?
+	`
+	result, err := execContext(ctx, r.dialect, db, query,
+		model.Status, model.Attempts, model.ResponseStatus, model.LastError,
+		utcTime(model.NextAttemptAt), deliveredAt, model.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found: %w", Errors.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func scanDeliveries(rows *sql.Rows) (webhook.DeliverySlice, error) {
+	var deliveries webhook.DeliverySlice
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func scanDelivery(s scanner) (*webhook.Delivery, error) {
+	var (
+		d            webhook.Delivery
+		payload      []byte
+		dedupKey     sql.NullString
+		redeliveryOf sql.NullString
+		lastError    sql.NullString
+		deliveredAt  sql.NullTime
+	)
+	err := s.Scan(
+		&d.Seq, &d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &dedupKey, &redeliveryOf,
+		&d.Status, &d.Attempts, &d.ResponseStatus, &lastError,
+		&d.NextAttemptAt, &d.CreatedAt, &deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	d.DedupKey = dedupKey.String
+	d.RedeliveryOf = redeliveryOf.String
+	d.LastError = lastError.String
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	_ "modernc.org/sqlite"
+)
+
+// Scheme はSQLiteのDSNのスキームです
+const Scheme = "sqlite://"
+
+// pragmas は接続ごとに設定するPRAGMAです
+// SQLiteは接続ごとに外部キー制約を有効にする必要があります
+var pragmas = []string{
+	"busy_timeout(5000)",
+	"foreign_keys(1)",
+	"journal_mode(WAL)",
+}
+
+// DataSource は sqlite://<path> 形式のDSNをドライバーに渡すデータソース名に変換します
+// sqlite://./data/app.db は相対パス、sqlite:///var/lib/app.db は絶対パスになります
+// コネクションプールの接続ごとに別のデータベースになるため、:memory: は使用できません
+func DataSource(dsn string) string {
+	path := strings.TrimPrefix(dsn, Scheme)
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	params := make([]string, 0, len(pragmas))
+	for _, p := range pragmas {
+		params = append(params, "_pragma="+p)
+	}
+	return path + sep + strings.Join(params, "&")
+}
+
+// Open はSQLiteのデータベースファイルを開きます
+func Open(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DataSource(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// Connect はコネクションプールを作成し、接続を確認します
+// プールの設定はMySQLと同じく config.Config で行います
+func Connect(ctx context.Context) (*sql.DB, error) {
+	env := Ctx.GetCtxCfg(ctx)
+
+	db, err := Open(env.Database_url)
+	if err != nil {
+		return nil, err
+	}
+
+	// コネクションプール設定
+	db.SetMaxOpenConns(env.DBMaxOpenConns)
+	db.SetMaxIdleConns(env.DBMaxIdleConns)
+	db.SetConnMaxLifetime(env.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(env.DBConnMaxIdleTime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
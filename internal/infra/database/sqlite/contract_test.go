@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/contract"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) (context.Context, contract.Repositories) {
+		db, err := Open(Scheme + filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		migrations := &migrate.FileMigrationSource{Dir: "../../../../db/migrations/sqlite"}
+		if _, err := migrate.Exec(db, Dialect.Name(), migrations, migrate.Up); err != nil {
+			t.Fatalf("failed to migrate: %v", err)
+		}
+
+		return Ctx.SetDB(context.Background(), db), contract.Repositories{
+			User:        NewUserRepository(),
+			Summary:     NewSummaryRepository(),
+			Category:    NewCategoryRepository(),
+			Subcategory: NewSubcategoryRepository(),
+			Outbox:      NewOutboxRepository(),
+			Webhook:     NewWebhookRepository(),
+			Delivery:    NewWebhookDeliveryRepository(),
+			RateLimit:   NewRateLimitStore(),
+		}
+	})
+}
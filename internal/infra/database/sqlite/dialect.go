@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/mysql"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect はSQLiteの方言です
+var Dialect mysql.Dialect = sqliteDialect{}
+
+// errorTranslations はSQLiteの拡張エラーコードとドメインエラーの対応です
+var errorTranslations = map[int]error{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     Errors.ErrUniqueConstraint,
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: Errors.ErrUniqueConstraint,
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: Errors.ErrForeignKeyConstraint,
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite3"
+}
+
+// Now はミリ秒までの現在時刻(UTC)を返します
+func (sqliteDialect) Now() string {
+	return "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+}
+
+func (d sqliteDialect) Upsert(key string, columns ...string) string {
+	sets := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		sets = append(sets, fmt.Sprintf("%s = excluded.%s", c, c))
+	}
+	sets = append(sets, "updated_at = "+d.Now())
+	return fmt.Sprintf("ON CONFLICT(%s) DO UPDATE SET %s", key, strings.Join(sets, ", "))
+}
+
+// Like はSQLiteにはデフォルトのエスケープ文字がないため ESCAPE 句を指定します
+func (sqliteDialect) Like(column string) string {
+	return column + ` LIKE ? ESCAPE '\'`
+}
+
+// ForShare はSQLiteには行ロックがないため空の句を返します
+// SQLiteは書き込みをデータベース全体で直列化し、読み取った後に別の接続がコミットしたトランザクションの書き込みは失敗するため、
+// 読み取った行がコミットまでに変更されることはありません
+func (sqliteDialect) ForShare() string {
+	return ""
+}
+
+// TranslateError は制約違反とロック競合をドメインエラーでラップします
+// ロック競合 (SQLITE_BUSY / SQLITE_LOCKED) は拡張エラーコードに関わらず再試行可能として扱います
+func (sqliteDialect) TranslateError(err error) error {
+	var sqliteErr *driver.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	if sentinel, ok := errorTranslations[sqliteErr.Code()]; ok {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return fmt.Errorf("%w: %w", Errors.ErrRetryable, err)
+	}
+	return err
+}
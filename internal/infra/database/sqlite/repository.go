@@ -0,0 +1,53 @@
+// Package sqlite はSQLiteのデータベースファイルを使うリポジトリを提供します
+// SQLは mysql パッケージのリポジトリと共通で、方言の違いは Dialect で吸収します
+package sqlite
+
+import (
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/mysql"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+)
+
+func NewUserRepository() user.IUserRepository {
+	return mysql.NewUserRepositoryWithDialect(Dialect)
+}
+
+func NewSummaryRepository() summary.ISummaryRepository {
+	return mysql.NewSummaryRepositoryWithDialect(Dialect)
+}
+
+func NewCategoryRepository() category.ICategoryRepository {
+	return mysql.NewCategoryRepositoryWithDialect(Dialect)
+}
+
+func NewSubcategoryRepository() subcategory.ISubcategoryRepository {
+	return mysql.NewSubcategoryRepositoryWithDialect(Dialect)
+}
+
+// NewTxManager はSQLiteのトランザクションマネージャーを生成します
+// SQLiteはデータベース全体で書き込みを直列化するため、分離レベルの指定は無視されます
+func NewTxManager() domain.ITxManager {
+	return mysql.NewTxManagerWithDialect(Dialect)
+}
+
+func NewOutboxRepository() event.IOutboxRepository {
+	return mysql.NewOutboxRepositoryWithDialect(Dialect)
+}
+
+func NewWebhookRepository() webhook.IWebhookRepository {
+	return mysql.NewWebhookRepositoryWithDialect(Dialect)
+}
+
+func NewWebhookDeliveryRepository() webhook.IDeliveryRepository {
+	return mysql.NewWebhookDeliveryRepositoryWithDialect(Dialect)
+}
+
+func NewRateLimitStore() ratelimit.Store {
+	return mysql.NewRateLimitStoreWithDialect(Dialect)
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// 配信の設定
+var (
+	// claimLease は取得したイベントを他の配信処理に渡さない時間です
+	// 配信中にプロセスが停止した場合、この時間が過ぎると再度配信されます
+	claimLease = time.Minute
+	// deliveryTimeout は1つの購読者の処理に使える時間です
+	deliveryTimeout = 30 * time.Second
+	// maxRetryDelay は再試行の間隔の上限です
+	maxRetryDelay = 10 * time.Minute
+)
+
+// Subscriber はイベントを受け取る購読者です
+// 同じイベントが複数回届くことがあるため (at-least-once)、冪等に処理してください
+type Subscriber func(ctx context.Context, e *event.Event) error
+
+type subscription struct {
+	name  string
+	types map[event.Type]struct{}
+	fn    Subscriber
+}
+
+func (s subscription) accepts(t event.Type) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	_, ok := s.types[t]
+	return ok
+}
+
+// Dispatcher はアウトボックスのイベントを定期的に取得して購読者に配信します
+// 購読者がエラーを返した場合は間隔を倍々に延ばして再試行し、OUTBOX_MAX_ATTEMPTS 回失敗すると
+// デッドレター (dead) にして配信を諦めます
+// 再試行ではすべての購読者に再度配信します
+type Dispatcher struct {
+	repo          event.IOutboxRepository
+	interval      time.Duration
+	batchSize     int
+	maxAttempts   int
+	baseDelay     time.Duration
+	mu            sync.RWMutex
+	subscriptions []subscription
+	now           func() time.Time
+}
+
+// NewDispatcher は OUTBOX_* の設定で配信処理を生成します
+func NewDispatcher(repo event.IOutboxRepository, cfg *config.Config) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		interval:    cfg.OutboxPollInterval,
+		batchSize:   cfg.OutboxBatchSize,
+		maxAttempts: cfg.OutboxMaxAttempts,
+		baseDelay:   cfg.OutboxRetryBaseDelay,
+		now:         time.Now,
+	}
+}
+
+// Subscribe は types のイベントを受け取る購読者を登録します
+// types を指定しない場合はすべてのイベントを受け取ります
+// name はログと配信エラーの記録に使います
+func (d *Dispatcher) Subscribe(name string, fn Subscriber, types ...event.Type) {
+	s := subscription{name: name, types: make(map[event.Type]struct{}, len(types)), fn: fn}
+	for _, t := range types {
+		s.types[t] = struct{}{}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions = append(d.subscriptions, s)
+}
+
+// Run は ctx がキャンセルされるまで定期的に配信します
+// ctx にはアウトボックスのリポジトリが使うデータベースの接続が設定されている必要があります
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		// 1回で取得しきれなかった場合は待たずに続けて配信する
+		for {
+			n, err := d.DispatchPending(ctx)
+			if err != nil {
+				logger.Error(ctx, fmt.Sprintf("failed to dispatch outbox events: %v", err))
+				break
+			}
+			if n < d.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending は配信予定時刻を過ぎたイベントを1回分取得して配信し、取得した件数を返します
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	records, err := d.repo.Claim(ctx, d.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rec := range records {
+		if ctx.Err() != nil {
+			// 取得済みのイベントはリースが切れた後に再度配信される
+			return len(records), ctx.Err()
+		}
+		if err := d.dispatch(ctx, rec); err != nil {
+			return len(records), err
+		}
+	}
+	return len(records), nil
+}
+
+// dispatch は1つのイベントを購読者に配信し、結果をアウトボックスに記録します
+func (d *Dispatcher) dispatch(ctx context.Context, rec *event.Record) error {
+	d.mu.RLock()
+	subscriptions := d.subscriptions
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if !s.accepts(rec.Type) {
+			continue
+		}
+		if err := deliver(ctx, s, &rec.Event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return d.repo.MarkDelivered(ctx, rec.ID)
+	}
+
+	rec.Attempts++
+	rec.LastError = errors.Join(errs...).Error()
+	if rec.Attempts >= d.maxAttempts {
+		rec.Status = event.StatusDead
+		rec.NextAttemptAt = d.now()
+		logger.Error(ctx, fmt.Sprintf("outbox event %s (%s) moved to dead letter after %d attempts: %s", rec.ID, rec.Type, rec.Attempts, rec.LastError))
+	} else {
+		rec.Status = event.StatusPending
+		rec.NextAttemptAt = d.now().Add(d.retryDelay(rec.Attempts))
+		logger.Warn(ctx, fmt.Sprintf("outbox event %s (%s) failed (attempt %d): %s", rec.ID, rec.Type, rec.Attempts, rec.LastError))
+	}
+	return d.repo.MarkFailed(ctx, rec)
+}
+
+// retryDelay は attempts 回目の失敗後に待つ時間を返します
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.baseDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// deliver は購読者を呼び出します。パニックした場合もエラーとして扱います
+func deliver(ctx context.Context, s subscription, e *event.Event) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return s.fn(ctx, e)
+}
+
+// LogSubscriber はイベントを監査ログとして出力する購読者です
+func LogSubscriber(ctx context.Context, e *event.Event) error {
+	logger.Info(ctx, fmt.Sprintf("domain event: %s", e.Type), "event_id", e.ID, "aggregate_id", e.AggregateID, "occurred_at", e.OccurredAt)
+	return nil
+}
@@ -0,0 +1,208 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository はevent.IOutboxRepositoryのモック
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, events ...*event.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*event.Record, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*event.Record), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, record *event.Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ListAfter(ctx context.Context, seq int64, limit int) ([]*event.Record, error) {
+	args := m.Called(ctx, seq, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*event.Record), args.Error(1)
+}
+
+func (m *MockOutboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestDispatcher_DispatchPending(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	errSubscriber := errors.New("subscriber failed")
+
+	tests := []struct {
+		name        string
+		attempts    int
+		subscribers map[string]Subscriber
+		types       map[string][]event.Type
+		mockSetup   func(*MockOutboxRepository)
+		wantCalls   map[string]int
+		wantErr     bool
+	}{
+		{
+			name: "成功ケース: すべての購読者が成功すると配信済みにする",
+			subscribers: map[string]Subscriber{
+				"a": func(ctx context.Context, e *event.Event) error { return nil },
+				"b": func(ctx context.Context, e *event.Event) error { return nil },
+			},
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkDelivered", mock.Anything, "event-1").Return(nil)
+			},
+			wantCalls: map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name: "成功ケース: 種類が一致しない購読者には配信しない",
+			subscribers: map[string]Subscriber{
+				"summary":  func(ctx context.Context, e *event.Event) error { return nil },
+				"category": func(ctx context.Context, e *event.Event) error { return errSubscriber },
+			},
+			types: map[string][]event.Type{
+				"summary":  {event.TypeSummaryCreated},
+				"category": {event.TypeCategoryCreated, event.TypeCategoryDeleted},
+			},
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkDelivered", mock.Anything, "event-1").Return(nil)
+			},
+			wantCalls: map[string]int{"summary": 1, "category": 0},
+		},
+		{
+			name: "成功ケース: 購読者が失敗すると間隔を延ばして再試行する",
+			subscribers: map[string]Subscriber{
+				"a": func(ctx context.Context, e *event.Event) error { return errSubscriber },
+			},
+			attempts: 2,
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkFailed", mock.Anything, mock.MatchedBy(func(r *event.Record) bool {
+					return r.Status == event.StatusPending &&
+						r.Attempts == 3 &&
+						r.LastError == "a: subscriber failed" &&
+						r.NextAttemptAt.Equal(now.Add(4*time.Second))
+				})).Return(nil)
+			},
+			wantCalls: map[string]int{"a": 1},
+		},
+		{
+			name: "成功ケース: 再試行の上限に達するとデッドレターにする",
+			subscribers: map[string]Subscriber{
+				"a": func(ctx context.Context, e *event.Event) error { return errSubscriber },
+			},
+			attempts: 4,
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkFailed", mock.Anything, mock.MatchedBy(func(r *event.Record) bool {
+					return r.Status == event.StatusDead && r.Attempts == 5
+				})).Return(nil)
+			},
+			wantCalls: map[string]int{"a": 1},
+		},
+		{
+			name: "成功ケース: 購読者のパニックは失敗として扱う",
+			subscribers: map[string]Subscriber{
+				"a": func(ctx context.Context, e *event.Event) error { panic("boom") },
+			},
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkFailed", mock.Anything, mock.MatchedBy(func(r *event.Record) bool {
+					return r.Attempts == 1 && r.LastError == "a: panic: boom"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "失敗ケース: 配信済みにできない場合はエラーを返す",
+			subscribers: map[string]Subscriber{
+				"a": func(ctx context.Context, e *event.Event) error { return nil },
+			},
+			mockSetup: func(m *MockOutboxRepository) {
+				m.On("MarkDelivered", mock.Anything, "event-1").Return(errors.New("db error"))
+			},
+			wantCalls: map[string]int{"a": 1},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &event.Record{
+				Event:    event.Event{ID: "event-1", Type: event.TypeSummaryCreated, AggregateID: "summary-1"},
+				Status:   event.StatusPending,
+				Attempts: tt.attempts,
+			}
+
+			mockRepo := new(MockOutboxRepository)
+			mockRepo.On("Claim", mock.Anything, 100, claimLease).Return([]*event.Record{record}, nil)
+			tt.mockSetup(mockRepo)
+
+			d := NewDispatcher(mockRepo, &config.Config{
+				OutboxPollInterval:   time.Second,
+				OutboxBatchSize:      100,
+				OutboxMaxAttempts:    5,
+				OutboxRetryBaseDelay: time.Second,
+			})
+			d.now = func() time.Time { return now }
+
+			calls := map[string]int{}
+			for name, fn := range tt.subscribers {
+				d.Subscribe(name, func(ctx context.Context, e *event.Event) error {
+					calls[name]++
+					return fn(ctx, e)
+				}, tt.types[name]...)
+			}
+
+			n, err := d.DispatchPending(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 1, n)
+			for name, want := range tt.wantCalls {
+				assert.Equal(t, want, calls[name], name)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatcher_RetryDelay(t *testing.T) {
+	d := &Dispatcher{baseDelay: time.Second}
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "成功ケース: 1回目の失敗", attempts: 1, want: time.Second},
+		{name: "成功ケース: 3回目の失敗", attempts: 3, want: 4 * time.Second},
+		{name: "成功ケース: 上限を超えない", attempts: 30, want: maxRetryDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, d.retryDelay(tt.attempts))
+		})
+	}
+}
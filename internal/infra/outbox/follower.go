@@ -0,0 +1,119 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// gapTimeout は Seq の欠番を待つ時間です
+// 先に採番したトランザクションが後からコミットされることがあるため、欠番はすぐに読み飛ばさずに待ちます
+// ロールバックされた欠番はこの時間が過ぎると読み飛ばします
+var gapTimeout = 5 * time.Second
+
+// Follower はアウトボックスのイベントを保存した順に読み取り、購読者に渡します
+// Dispatcher と異なりイベントをリースせず、インスタンスごとに読み取った位置 (Seq) を持つため、
+// 複数台で動かしてもすべてのインスタンスが同じイベントを受け取ります
+// 読み取りは起動した時点の最後のイベントの後から始め、購読者のエラーは再試行しません
+// 接続中のクライアントへの中継のように、インスタンスごとに必要で失われても困らない処理に使います
+type Follower struct {
+	repo      event.IOutboxRepository
+	interval  time.Duration
+	batchSize int
+	sub       subscription
+	started   bool
+	cursor    int64
+	gapSince  time.Time
+	now       func() time.Time
+}
+
+// NewFollower は OUTBOX_POLL_INTERVAL と OUTBOX_BATCH_SIZE の設定で、types のイベントを fn に渡す Follower を生成します
+// types を指定しない場合はすべてのイベントを渡します。name はログに使います
+func NewFollower(repo event.IOutboxRepository, cfg *config.Config, name string, fn Subscriber, types ...event.Type) *Follower {
+	s := subscription{name: name, types: make(map[event.Type]struct{}, len(types)), fn: fn}
+	for _, t := range types {
+		s.types[t] = struct{}{}
+	}
+	return &Follower{
+		repo:      repo,
+		interval:  cfg.OutboxPollInterval,
+		batchSize: cfg.OutboxBatchSize,
+		sub:       s,
+		now:       time.Now,
+	}
+}
+
+// Run は ctx がキャンセルされるまで定期的にイベントを読み取ります
+// ctx にはアウトボックスのリポジトリが使うデータベースの接続が設定されている必要があります
+func (f *Follower) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		// 1回で読み取りきれなかった場合は待たずに続けて読み取る
+		for {
+			n, err := f.FollowPending(ctx)
+			if err != nil {
+				logger.Error(ctx, fmt.Sprintf("failed to follow outbox events (%s): %v", f.sub.name, err))
+				break
+			}
+			if n < f.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// FollowPending は前回読み取った位置より後のイベントを1回分読み取って購読者に渡し、渡した件数を返します
+// 最初の呼び出しでは読み取りを始める位置だけを決めます
+func (f *Follower) FollowPending(ctx context.Context) (int, error) {
+	if !f.started {
+		seq, err := f.repo.LastSeq(ctx)
+		if err != nil {
+			return 0, err
+		}
+		f.cursor = seq
+		f.started = true
+		return 0, nil
+	}
+
+	records, err := f.repo.ListAfter(ctx, f.cursor, f.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, rec := range records {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		if rec.Seq != f.cursor+1 {
+			// 欠番が埋まるのを待ち、gapTimeout を過ぎたら読み飛ばす
+			if f.gapSince.IsZero() {
+				f.gapSince = f.now()
+			}
+			if f.now().Sub(f.gapSince) < gapTimeout {
+				return i, nil
+			}
+		}
+		f.gapSince = time.Time{}
+		f.cursor = rec.Seq
+
+		if !f.sub.accepts(rec.Type) {
+			continue
+		}
+		if err := deliver(ctx, f.sub, &rec.Event); err != nil {
+			logger.Warn(ctx, fmt.Sprintf("outbox event %s (%s) was not passed to %s: %v", rec.ID, rec.Type, f.sub.name, err))
+		}
+	}
+	return len(records), nil
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollower_FollowPending(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	record := func(seq int64, typ event.Type) *event.Record {
+		return &event.Record{Event: event.Event{ID: fmt.Sprintf("%s-%d", typ, seq), Type: typ}, Seq: seq}
+	}
+
+	tests := []struct {
+		name       string
+		cursor     int64
+		gapSince   time.Time
+		records    []*event.Record
+		fn         Subscriber
+		wantN      int
+		wantCursor int64
+		wantSeqs   []int64
+	}{
+		{
+			name:       "成功ケース: 保存した順に購読者に渡す",
+			cursor:     10,
+			records:    []*event.Record{record(11, event.TypeSummaryCreated), record(12, event.TypeSummaryDeleted)},
+			wantN:      2,
+			wantCursor: 12,
+			wantSeqs:   []int64{11, 12},
+		},
+		{
+			name:       "成功ケース: 購読していない種類は渡さずに読み進める",
+			cursor:     10,
+			records:    []*event.Record{record(11, event.TypeCategoryCreated), record(12, event.TypeSummaryUpdated)},
+			wantN:      2,
+			wantCursor: 12,
+			wantSeqs:   []int64{12},
+		},
+		{
+			name:       "成功ケース: 購読者がエラーを返しても読み進める",
+			cursor:     10,
+			records:    []*event.Record{record(11, event.TypeSummaryCreated), record(12, event.TypeSummaryCreated)},
+			fn:         func(ctx context.Context, e *event.Event) error { return errors.New("subscriber failed") },
+			wantN:      2,
+			wantCursor: 12,
+			wantSeqs:   []int64{11, 12},
+		},
+		{
+			name:       "成功ケース: 欠番があると埋まるまで待つ",
+			cursor:     10,
+			records:    []*event.Record{record(11, event.TypeSummaryCreated), record(13, event.TypeSummaryCreated)},
+			wantN:      1,
+			wantCursor: 11,
+			wantSeqs:   []int64{11},
+		},
+		{
+			name:       "成功ケース: 欠番を gapTimeout 待っても埋まらなければ読み飛ばす",
+			cursor:     10,
+			gapSince:   now.Add(-gapTimeout),
+			records:    []*event.Record{record(12, event.TypeSummaryCreated), record(13, event.TypeSummaryCreated)},
+			wantN:      2,
+			wantCursor: 13,
+			wantSeqs:   []int64{12, 13},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockOutboxRepository)
+			mockRepo.On("ListAfter", context.Background(), tt.cursor, 100).Return(tt.records, nil)
+
+			var seqs []int64
+			f := NewFollower(mockRepo, &config.Config{OutboxPollInterval: time.Second, OutboxBatchSize: 100}, "test", func(ctx context.Context, e *event.Event) error {
+				for _, rec := range tt.records {
+					if rec.ID == e.ID {
+						seqs = append(seqs, rec.Seq)
+					}
+				}
+				if tt.fn != nil {
+					return tt.fn(ctx, e)
+				}
+				return nil
+			}, event.TypeSummaryCreated, event.TypeSummaryUpdated, event.TypeSummaryDeleted)
+			f.now = func() time.Time { return now }
+			f.started = true
+			f.cursor = tt.cursor
+			f.gapSince = tt.gapSince
+
+			n, err := f.FollowPending(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantN, n)
+			assert.Equal(t, tt.wantCursor, f.cursor)
+			assert.Equal(t, tt.wantSeqs, seqs)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFollower_FollowPending_Start(t *testing.T) {
+	mockRepo := new(MockOutboxRepository)
+	mockRepo.On("LastSeq", context.Background()).Return(int64(0), errors.New("connection refused")).Once()
+	mockRepo.On("LastSeq", context.Background()).Return(int64(42), nil).Once()
+	mockRepo.On("ListAfter", context.Background(), int64(42), 100).Return([]*event.Record{}, nil).Once()
+
+	f := NewFollower(mockRepo, &config.Config{OutboxPollInterval: time.Second, OutboxBatchSize: 100}, "test", func(ctx context.Context, e *event.Event) error {
+		t.Fatalf("unexpected event: %s", e.ID)
+		return nil
+	})
+
+	// 最後のイベントの位置を取得できるまでは読み取りを始めない
+	_, err := f.FollowPending(context.Background())
+	assert.Error(t, err)
+	assert.False(t, f.started)
+
+	// 起動した時点までに保存されたイベントは渡さない
+	n, err := f.FollowPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, int64(42), f.cursor)
+
+	n, err = f.FollowPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,161 @@
+// Package outbox はデータの変更をドメインイベントとしてアウトボックスに保存し、購読者に配信します
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
+
+// emitter はデータの変更とイベントの保存を同じトランザクションで行います
+type emitter struct {
+	outbox event.IOutboxRepository
+	txm    domain.ITxManager
+}
+
+// save は保存の前に存在するかで作成と更新を判定し、保存後のデータをイベントにします
+func save[T any](ctx context.Context, e emitter, id string, created, updated event.Type, detail func(ctx context.Context) (T, error), save func(ctx context.Context) error, aggregateID func() string) error {
+	return e.txm.WithinTx(ctx, func(ctx context.Context) error {
+		typ := created
+		if id != "" {
+			_, err := detail(ctx)
+			if err == nil {
+				typ = updated
+			} else if !Errors.Is(err, Errors.ErrRecordNotFound) {
+				return err
+			}
+		}
+
+		if err := save(ctx); err != nil {
+			return err
+		}
+
+		saved, err := detail(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load saved data: %w", err)
+		}
+		return e.append(ctx, typ, aggregateID(), saved)
+	})
+}
+
+// remove は削除前のデータをイベントにします
+func remove[T any](ctx context.Context, e emitter, id string, typ event.Type, detail func(ctx context.Context) (T, error), remove func(ctx context.Context) error) error {
+	return e.txm.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := detail(ctx)
+		if err != nil {
+			return err
+		}
+		if err := remove(ctx); err != nil {
+			return err
+		}
+		return e.append(ctx, typ, id, before)
+	})
+}
+
+func (e emitter) append(ctx context.Context, typ event.Type, aggregateID string, payload any) error {
+	ev, err := event.New(typ, aggregateID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode event payload: %w", err)
+	}
+	return e.outbox.Append(ctx, ev)
+}
+
+type summaryRepository struct {
+	summary.ISummaryRepository
+	emitter
+}
+
+// NewSummaryRepository は保存・削除・公開のイベントをアウトボックスに保存するサマリーリポジトリを生成します
+func NewSummaryRepository(next summary.ISummaryRepository, outbox event.IOutboxRepository, txm domain.ITxManager) summary.ISummaryRepository {
+	return &summaryRepository{ISummaryRepository: next, emitter: emitter{outbox: outbox, txm: txm}}
+}
+
+func (r *summaryRepository) Save(ctx context.Context, model *summary.Summary) error {
+	return save(ctx, r.emitter, model.ID, event.TypeSummaryCreated, event.TypeSummaryUpdated,
+		func(ctx context.Context) (*summary.Summary, error) { return r.ISummaryRepository.Detail(ctx, model) },
+		func(ctx context.Context) error { return r.ISummaryRepository.Save(ctx, model) },
+		func() string { return model.ID },
+	)
+}
+
+func (r *summaryRepository) Delete(ctx context.Context, model *summary.Summary) error {
+	return remove(ctx, r.emitter, model.ID, event.TypeSummaryDeleted,
+		func(ctx context.Context) (*summary.Summary, error) { return r.ISummaryRepository.Detail(ctx, model) },
+		func(ctx context.Context) error { return r.ISummaryRepository.Delete(ctx, model) },
+	)
+}
+
+// Publish は公開後のデータを summary.published のイベントにします
+func (r *summaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	return r.txm.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.ISummaryRepository.Publish(ctx, model); err != nil {
+			return err
+		}
+		published, err := r.ISummaryRepository.Detail(ctx, model)
+		if err != nil {
+			return fmt.Errorf("failed to load saved data: %w", err)
+		}
+		return r.append(ctx, event.TypeSummaryPublished, model.ID, published)
+	})
+}
+
+type categoryRepository struct {
+	category.ICategoryRepository
+	emitter
+}
+
+// NewCategoryRepository は保存と削除のイベントをアウトボックスに保存するカテゴリリポジトリを生成します
+// カテゴリの削除で削除されるサブカテゴリのイベントは保存しないため、購読者は category.deleted で合わせて処理します
+func NewCategoryRepository(next category.ICategoryRepository, outbox event.IOutboxRepository, txm domain.ITxManager) category.ICategoryRepository {
+	return &categoryRepository{ICategoryRepository: next, emitter: emitter{outbox: outbox, txm: txm}}
+}
+
+func (r *categoryRepository) Save(ctx context.Context, model *category.Category) error {
+	return save(ctx, r.emitter, model.ID, event.TypeCategoryCreated, event.TypeCategoryUpdated,
+		func(ctx context.Context) (*category.Category, error) { return r.ICategoryRepository.Detail(ctx, model) },
+		func(ctx context.Context) error { return r.ICategoryRepository.Save(ctx, model) },
+		func() string { return model.ID },
+	)
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, model *category.Category) error {
+	return remove(ctx, r.emitter, model.ID, event.TypeCategoryDeleted,
+		func(ctx context.Context) (*category.Category, error) { return r.ICategoryRepository.Detail(ctx, model) },
+		func(ctx context.Context) error { return r.ICategoryRepository.Delete(ctx, model) },
+	)
+}
+
+type subcategoryRepository struct {
+	subcategory.ISubcategoryRepository
+	emitter
+}
+
+// NewSubcategoryRepository は保存と削除のイベントをアウトボックスに保存するサブカテゴリリポジトリを生成します
+func NewSubcategoryRepository(next subcategory.ISubcategoryRepository, outbox event.IOutboxRepository, txm domain.ITxManager) subcategory.ISubcategoryRepository {
+	return &subcategoryRepository{ISubcategoryRepository: next, emitter: emitter{outbox: outbox, txm: txm}}
+}
+
+func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Subcategory) error {
+	return save(ctx, r.emitter, model.ID, event.TypeSubcategoryCreated, event.TypeSubcategoryUpdated,
+		func(ctx context.Context) (*subcategory.Subcategory, error) {
+			return r.ISubcategoryRepository.Detail(ctx, model)
+		},
+		func(ctx context.Context) error { return r.ISubcategoryRepository.Save(ctx, model) },
+		func() string { return model.ID },
+	)
+}
+
+func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.Subcategory) error {
+	return remove(ctx, r.emitter, model.ID, event.TypeSubcategoryDeleted,
+		func(ctx context.Context) (*subcategory.Subcategory, error) {
+			return r.ISubcategoryRepository.Detail(ctx, model)
+		},
+		func(ctx context.Context) error { return r.ISubcategoryRepository.Delete(ctx, model) },
+	)
+}
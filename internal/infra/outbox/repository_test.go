@@ -0,0 +1,174 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/memory"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type repositories struct {
+	user        user.IUserRepository
+	summary     summary.ISummaryRepository
+	category    category.ICategoryRepository
+	subcategory subcategory.ISubcategoryRepository
+	outbox      event.IOutboxRepository
+}
+
+func newRepositories() repositories {
+	store := memory.NewStore()
+	outboxRepo := memory.NewOutboxRepository(store)
+	txm := memory.NewTxManager(store)
+	return repositories{
+		user:        memory.NewUserRepository(store),
+		summary:     NewSummaryRepository(memory.NewSummaryRepository(store), outboxRepo, txm),
+		category:    NewCategoryRepository(memory.NewCategoryRepository(store), outboxRepo, txm),
+		subcategory: NewSubcategoryRepository(memory.NewSubcategoryRepository(store), outboxRepo, txm),
+		outbox:      outboxRepo,
+	}
+}
+
+func TestRepositories_EmitEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		run       func(t *testing.T, ctx context.Context, repos repositories) string
+		wantTypes []event.Type
+		wantErrIs error
+	}{
+		{
+			name: "成功ケース: サマリーを作成するとsummary.createdを保存する",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := newSummary(t, ctx, repos)
+				assert.NoError(t, repos.summary.Save(ctx, s))
+				return s.ID
+			},
+			wantTypes: []event.Type{event.TypeSummaryCreated},
+		},
+		{
+			name: "成功ケース: サマリーを削除するとsummary.deletedを保存する",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := newSummary(t, ctx, repos)
+				assert.NoError(t, repos.summary.Save(ctx, s))
+				assert.NoError(t, repos.summary.Delete(ctx, s))
+				return s.ID
+			},
+			wantTypes: []event.Type{event.TypeSummaryCreated, event.TypeSummaryDeleted},
+		},
+		{
+			name: "成功ケース: サマリーを公開するとsummary.publishedを保存する",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := newSummary(t, ctx, repos)
+				assert.NoError(t, repos.summary.Save(ctx, s))
+				assert.NoError(t, repos.summary.Publish(ctx, s))
+				return s.ID
+			},
+			wantTypes: []event.Type{event.TypeSummaryCreated, event.TypeSummaryPublished},
+		},
+		{
+			name: "失敗ケース: 公開済みのサマリーは再度イベントを保存しない",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := newSummary(t, ctx, repos)
+				assert.NoError(t, repos.summary.Save(ctx, s))
+				assert.NoError(t, repos.summary.Publish(ctx, s))
+				assert.ErrorIs(t, repos.summary.Publish(ctx, s), Errors.ErrConflict)
+				return s.ID
+			},
+			wantTypes: []event.Type{event.TypeSummaryCreated, event.TypeSummaryPublished},
+		},
+		{
+			name: "成功ケース: 既存のカテゴリを保存するとcategory.updatedを保存する",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				c := &category.Category{Name: "雑談"}
+				assert.NoError(t, repos.category.Save(ctx, c))
+				c.Name = "雑談配信"
+				assert.NoError(t, repos.category.Save(ctx, c))
+				return c.ID
+			},
+			wantTypes: []event.Type{event.TypeCategoryCreated, event.TypeCategoryUpdated},
+		},
+		{
+			name: "成功ケース: サブカテゴリを作成・削除するとイベントを保存する",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				c := &category.Category{Name: "雑談"}
+				assert.NoError(t, repos.category.Save(ctx, c))
+				sc := &subcategory.Subcategory{CategoryID: c.ID, Name: "朝"}
+				assert.NoError(t, repos.subcategory.Save(ctx, sc))
+				assert.NoError(t, repos.subcategory.Delete(ctx, sc))
+				return sc.ID
+			},
+			wantTypes: []event.Type{event.TypeCategoryCreated, event.TypeSubcategoryCreated, event.TypeSubcategoryDeleted},
+		},
+		{
+			name: "失敗ケース: 保存に失敗した場合はイベントを保存しない",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := newSummary(t, ctx, repos)
+				s.CategoryID = sql.NullString{String: uuid.GenerateID(), Valid: true}
+				err := repos.summary.Save(ctx, s)
+				assert.ErrorIs(t, err, Errors.ErrForeignKeyConstraint)
+				return s.ID
+			},
+		},
+		{
+			name: "失敗ケース: 存在しないサマリーの削除はイベントを保存しない",
+			run: func(t *testing.T, ctx context.Context, repos repositories) string {
+				s := &summary.Summary{WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()}}
+				assert.ErrorIs(t, repos.summary.Delete(ctx, s), Errors.ErrRecordNotFound)
+				return s.ID
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repos := newRepositories()
+			id := tt.run(t, ctx, repos)
+
+			records, err := repos.outbox.Claim(ctx, 100, time.Minute)
+			assert.NoError(t, err)
+
+			var gotTypes []event.Type
+			for _, rec := range records {
+				gotTypes = append(gotTypes, rec.Type)
+				assert.Equal(t, event.StatusPending, rec.Status)
+				assert.True(t, json.Valid(rec.Payload))
+			}
+			assert.Equal(t, tt.wantTypes, gotTypes)
+
+			// 最後のイベントは操作したデータのもの
+			if len(records) > 0 {
+				last := records[len(records)-1]
+				assert.Equal(t, id, last.AggregateID)
+
+				var payload map[string]any
+				assert.NoError(t, json.Unmarshal(last.Payload, &payload))
+				assert.Equal(t, id, payload["id"])
+			}
+		})
+	}
+}
+
+func newSummary(t *testing.T, ctx context.Context, repos repositories) *summary.Summary {
+	t.Helper()
+	u := &user.User{Name: "Alice", Email: uuid.GenerateID() + "@example.com", UserType: "admin"}
+	assert.NoError(t, repos.user.Save(ctx, u))
+	return &summary.Summary{
+		WYHBaseModel: domain.WYHBaseModel{ID: uuid.GenerateID()},
+		Title:        "Title",
+		Description:  "Description",
+		Content:      "Content",
+		UserID:       u.ID,
+	}
+}
@@ -0,0 +1,156 @@
+// Package sse はアウトボックスのイベントを Server-Sent Events で配信するために、プロセス内で購読者に中継します
+package sse
+
+import (
+	"context"
+	"sync"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+)
+
+// subscriberBuffer は購読者ごとに溜めておけるイベントの数です
+// 溜まりきった購読者は切断し、クライアントには Last-Event-ID で再接続させます
+var subscriberBuffer = 64
+
+// Filter は購読者に送るイベントを選びます
+type Filter func(e *event.Event) bool
+
+// Subscription はブローカーの購読です
+// C はブローカーが閉じられたか、購読者の処理が追いつかずに切断された場合に閉じられます
+type Subscription struct {
+	C      <-chan *event.Event
+	ch     chan *event.Event
+	filter Filter
+}
+
+// Broker は受け取ったイベントを購読者に中継し、直近のイベントをリングバッファに保持します
+// 再接続したクライアントには、保持しているイベントのうち Last-Event-ID より後のものを再送します
+// イベントはインスタンスごとの outbox.Follower から届くため、複数台で動かす場合もすべてのインスタンスに
+// 同じイベントが届きます。リングバッファはインスタンスごとなので、起動前のイベントは再送できません
+type Broker struct {
+	mu          sync.Mutex
+	buffer      []*event.Event
+	next        int
+	size        int
+	ids         map[string]struct{}
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker は直近 capacity 件のイベントを保持するブローカーを生成します
+func NewBroker(capacity int) *Broker {
+	return &Broker{
+		buffer:      make([]*event.Event, max(capacity, 1)),
+		ids:         make(map[string]struct{}),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish はイベントを保持して購読者に中継します。outbox.Follower の購読者として登録します
+// 同じイベントが複数回届くことがあるため、保持しているイベントと同じIDのものは無視します
+func (b *Broker) Publish(_ context.Context, e *event.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	if _, ok := b.ids[e.ID]; ok {
+		return nil
+	}
+
+	if evicted := b.buffer[b.next]; evicted != nil {
+		delete(b.ids, evicted.ID)
+	}
+	b.buffer[b.next] = e
+	b.ids[e.ID] = struct{}{}
+	b.next = (b.next + 1) % len(b.buffer)
+	b.size = min(b.size+1, len(b.buffer))
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			// 遅い購読者のために他の購読者を待たせない
+			b.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe は購読を開始し、lastEventID より後に保持しているイベントを返します
+// lastEventID が空の場合は再送しません。保持していないIDの場合は ok に false を返すため、
+// クライアントにはデータを取得し直させてください
+// 再送するイベントと購読で届くイベントは重複も欠落もしません
+func (b *Broker) Subscribe(lastEventID string, filter Filter) (sub *Subscription, replay []*event.Event, ok bool) {
+	ch := make(chan *event.Event, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(ch)
+		return sub, nil, true
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	if _, found := b.ids[lastEventID]; !found {
+		return sub, nil, false
+	}
+
+	found := false
+	for _, e := range b.events() {
+		if found && (filter == nil || filter(e)) {
+			replay = append(replay, e)
+		}
+		if e.ID == lastEventID {
+			found = true
+		}
+	}
+	return sub, replay, true
+}
+
+// Unsubscribe は購読を終了します。切断済みの購読に対しては何もしません
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		b.remove(sub)
+	}
+}
+
+// Close はすべての購読を終了し、以降のイベントを中継しません
+// サーバーのシャットダウンでストリームを終わらせるために使います
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove は購読者を削除してチャネルを閉じます。b.mu を取得してから呼び出します
+func (b *Broker) remove(sub *Subscription) {
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
+
+// events は保持しているイベントを古い順に返します。b.mu を取得してから呼び出します
+func (b *Broker) events() []*event.Event {
+	events := make([]*event.Event, 0, b.size)
+	start := (b.next - b.size + len(b.buffer)) % len(b.buffer)
+	for i := range b.size {
+		events = append(events, b.buffer[(start+i)%len(b.buffer)])
+	}
+	return events
+}
@@ -0,0 +1,161 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvent(id, aggregateID string) *event.Event {
+	return &event.Event{ID: id, Type: event.TypeSummaryUpdated, AggregateID: aggregateID}
+}
+
+func ids(events []*event.Event) []string {
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// receive はチャネルに溜まっているイベントを取り出します
+func receive(sub *Subscription) []*event.Event {
+	var events []*event.Event
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return events
+			}
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	onlySummary1 := func(e *event.Event) bool { return e.AggregateID == "summary-1" }
+
+	tests := []struct {
+		name        string
+		capacity    int
+		published   []*event.Event
+		lastEventID string
+		filter      Filter
+		wantReplay  []string
+		wantOK      bool
+	}{
+		{
+			name:      "成功ケース: Last-Event-ID がない場合は再送しない",
+			capacity:  10,
+			published: []*event.Event{newEvent("e1", "summary-1"), newEvent("e2", "summary-1")},
+			wantOK:    true,
+		},
+		{
+			name:        "成功ケース: Last-Event-ID より後のイベントを再送する",
+			capacity:    10,
+			published:   []*event.Event{newEvent("e1", "summary-1"), newEvent("e2", "summary-1"), newEvent("e3", "summary-1")},
+			lastEventID: "e1",
+			wantReplay:  []string{"e2", "e3"},
+			wantOK:      true,
+		},
+		{
+			name:        "成功ケース: 再送するイベントもフィルターで選ぶ",
+			capacity:    10,
+			published:   []*event.Event{newEvent("e1", "summary-1"), newEvent("e2", "summary-2"), newEvent("e3", "summary-1")},
+			lastEventID: "e1",
+			filter:      onlySummary1,
+			wantReplay:  []string{"e3"},
+			wantOK:      true,
+		},
+		{
+			name:        "成功ケース: 一周したリングバッファでも古い順に再送する",
+			capacity:    3,
+			published:   []*event.Event{newEvent("e1", "summary-1"), newEvent("e2", "summary-1"), newEvent("e3", "summary-1"), newEvent("e4", "summary-1")},
+			lastEventID: "e2",
+			wantReplay:  []string{"e3", "e4"},
+			wantOK:      true,
+		},
+		{
+			name:        "失敗ケース: リングバッファから押し出されたIDは再送できない",
+			capacity:    3,
+			published:   []*event.Event{newEvent("e1", "summary-1"), newEvent("e2", "summary-1"), newEvent("e3", "summary-1"), newEvent("e4", "summary-1")},
+			lastEventID: "e1",
+			wantOK:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBroker(tt.capacity)
+			for _, e := range tt.published {
+				assert.NoError(t, b.Publish(context.Background(), e))
+			}
+
+			sub, replay, ok := b.Subscribe(tt.lastEventID, tt.filter)
+			defer b.Unsubscribe(sub)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantReplay, ids(replay))
+		})
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	t.Run("成功ケース: フィルターに合うイベントだけを中継する", func(t *testing.T) {
+		b := NewBroker(10)
+		sub, _, _ := b.Subscribe("", func(e *event.Event) bool { return e.AggregateID == "summary-1" })
+
+		assert.NoError(t, b.Publish(context.Background(), newEvent("e1", "summary-1")))
+		assert.NoError(t, b.Publish(context.Background(), newEvent("e2", "summary-2")))
+
+		assert.Equal(t, []string{"e1"}, ids(receive(sub)))
+	})
+
+	t.Run("成功ケース: 同じIDのイベントは1回だけ中継する", func(t *testing.T) {
+		b := NewBroker(10)
+		sub, _, _ := b.Subscribe("", nil)
+
+		assert.NoError(t, b.Publish(context.Background(), newEvent("e1", "summary-1")))
+		assert.NoError(t, b.Publish(context.Background(), newEvent("e1", "summary-1")))
+
+		assert.Equal(t, []string{"e1"}, ids(receive(sub)))
+	})
+
+	t.Run("成功ケース: 処理が追いつかない購読者は切断する", func(t *testing.T) {
+		b := NewBroker(100)
+		slow, _, _ := b.Subscribe("", nil)
+		fast, _, _ := b.Subscribe("", nil)
+
+		for i := range subscriberBuffer + 1 {
+			assert.NoError(t, b.Publish(context.Background(), newEvent(fmt.Sprintf("e%d", i), "summary-1")))
+			if i < subscriberBuffer {
+				receive(fast)
+			}
+		}
+
+		assert.Len(t, receive(slow), subscriberBuffer)
+		_, open := <-slow.C
+		assert.False(t, open)
+		assert.Len(t, receive(fast), 1)
+	})
+
+	t.Run("成功ケース: Close ですべての購読を終了する", func(t *testing.T) {
+		b := NewBroker(10)
+		sub, _, _ := b.Subscribe("", nil)
+
+		b.Close()
+		assert.NoError(t, b.Publish(context.Background(), newEvent("e1", "summary-1")))
+
+		_, open := <-sub.C
+		assert.False(t, open)
+
+		after, _, _ := b.Subscribe("", nil)
+		_, open = <-after.C
+		assert.False(t, open)
+	})
+}
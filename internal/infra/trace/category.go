@@ -0,0 +1,32 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/category"
+)
+
+type categoryRepository struct {
+	next category.ICategoryRepository
+}
+
+// NewCategoryRepository は呼び出しごとにスパンと処理時間を記録するカテゴリリポジトリを生成します
+func NewCategoryRepository(next category.ICategoryRepository) category.ICategoryRepository {
+	return &categoryRepository{next: next}
+}
+
+func (r *categoryRepository) Save(ctx context.Context, model *category.Category) error {
+	return exec(ctx, "CategoryRepository.Save", func(ctx context.Context) error { return r.next.Save(ctx, model) })
+}
+
+func (r *categoryRepository) List(ctx context.Context) (category.CategorySlice, error) {
+	return call(ctx, "CategoryRepository.List", r.next.List)
+}
+
+func (r *categoryRepository) Detail(ctx context.Context, model *category.Category) (*category.Category, error) {
+	return call(ctx, "CategoryRepository.Detail", func(ctx context.Context) (*category.Category, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, model *category.Category) error {
+	return exec(ctx, "CategoryRepository.Delete", func(ctx context.Context) error { return r.next.Delete(ctx, model) })
+}
@@ -0,0 +1,28 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/contract"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/memory"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
+)
+
+// スパンを記録するリポジトリが結果とエラーをそのまま返すことを確認する
+func TestContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) (context.Context, contract.Repositories) {
+		store := memory.NewStore()
+		ctx, span := Trace.Start(context.Background(), "test")
+		t.Cleanup(span.End)
+		return ctx, contract.Repositories{
+			User:        NewUserRepository(memory.NewUserRepository(store)),
+			Summary:     NewSummaryRepository(memory.NewSummaryRepository(store)),
+			Category:    NewCategoryRepository(memory.NewCategoryRepository(store)),
+			Subcategory: NewSubcategoryRepository(memory.NewSubcategoryRepository(store)),
+			Outbox:      memory.NewOutboxRepository(store),
+			Webhook:     NewWebhookRepository(memory.NewWebhookRepository(store)),
+			Delivery:    NewDeliveryRepository(memory.NewWebhookDeliveryRepository(store)),
+		}
+	})
+}
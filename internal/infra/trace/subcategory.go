@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+)
+
+type subcategoryRepository struct {
+	next subcategory.ISubcategoryRepository
+}
+
+// NewSubcategoryRepository は呼び出しごとにスパンと処理時間を記録するサブカテゴリリポジトリを生成します
+func NewSubcategoryRepository(next subcategory.ISubcategoryRepository) subcategory.ISubcategoryRepository {
+	return &subcategoryRepository{next: next}
+}
+
+func (r *subcategoryRepository) Save(ctx context.Context, model *subcategory.Subcategory) error {
+	return exec(ctx, "SubcategoryRepository.Save", func(ctx context.Context) error { return r.next.Save(ctx, model) })
+}
+
+func (r *subcategoryRepository) List(ctx context.Context, categoryID string) (subcategory.SubcategorySlice, error) {
+	return call(ctx, "SubcategoryRepository.List", func(ctx context.Context) (subcategory.SubcategorySlice, error) { return r.next.List(ctx, categoryID) })
+}
+
+func (r *subcategoryRepository) Detail(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	return call(ctx, "SubcategoryRepository.Detail", func(ctx context.Context) (*subcategory.Subcategory, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *subcategoryRepository) DetailForShare(ctx context.Context, model *subcategory.Subcategory) (*subcategory.Subcategory, error) {
+	return call(ctx, "SubcategoryRepository.DetailForShare", func(ctx context.Context) (*subcategory.Subcategory, error) { return r.next.DetailForShare(ctx, model) })
+}
+
+func (r *subcategoryRepository) Delete(ctx context.Context, model *subcategory.Subcategory) error {
+	return exec(ctx, "SubcategoryRepository.Delete", func(ctx context.Context) error { return r.next.Delete(ctx, model) })
+}
@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/summary"
+)
+
+type summaryRepository struct {
+	next summary.ISummaryRepository
+}
+
+// NewSummaryRepository は呼び出しごとにスパンと処理時間を記録するサマリーリポジトリを生成します
+func NewSummaryRepository(next summary.ISummaryRepository) summary.ISummaryRepository {
+	return &summaryRepository{next: next}
+}
+
+func (r *summaryRepository) Save(ctx context.Context, model *summary.Summary) error {
+	return exec(ctx, "SummaryRepository.Save", func(ctx context.Context) error { return r.next.Save(ctx, model) })
+}
+
+func (r *summaryRepository) List(ctx context.Context, opts summary.ListOptions) (*summary.ListResult, error) {
+	return call(ctx, "SummaryRepository.List", func(ctx context.Context) (*summary.ListResult, error) { return r.next.List(ctx, opts) })
+}
+
+func (r *summaryRepository) Detail(ctx context.Context, model *summary.Summary) (*summary.Summary, error) {
+	return call(ctx, "SummaryRepository.Detail", func(ctx context.Context) (*summary.Summary, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *summaryRepository) Delete(ctx context.Context, model *summary.Summary) error {
+	return exec(ctx, "SummaryRepository.Delete", func(ctx context.Context) error { return r.next.Delete(ctx, model) })
+}
+
+func (r *summaryRepository) Publish(ctx context.Context, model *summary.Summary) error {
+	return exec(ctx, "SummaryRepository.Publish", func(ctx context.Context) error { return r.next.Publish(ctx, model) })
+}
@@ -0,0 +1,59 @@
+// Package trace はリポジトリの呼び出しごとにスパンと処理時間を記録するリポジトリを提供します
+package trace
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/metrics"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
+)
+
+const (
+	outcomeOK       = "ok"
+	outcomeNotFound = "not_found"
+	outcomeError    = "error"
+)
+
+var repositoryCallDuration = metrics.Register(metrics.NewHistogramVec(
+	"repository_call_duration_seconds", "Repository call latency in seconds.", metrics.DefBuckets, "repository", "method", "outcome"))
+
+// call はリポジトリの呼び出しの処理時間を記録し、スパンとして記録します
+// 処理時間はすべての呼び出しで記録します
+// スパンはバックグラウンドの定期的な処理でトレースが増えないように、リクエストなどのスパンの中で呼び出された場合だけ記録します
+// データがないことは正常な結果として扱い、エラーにしません
+func call[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	var span *Trace.Span
+	if Trace.SpanContextFromContext(ctx).IsValid() {
+		ctx, span = Trace.Start(ctx, name)
+		defer span.End()
+	}
+
+	v, err := fn(ctx)
+
+	outcome := outcomeOK
+	switch {
+	case Errors.Is(err, Errors.ErrRecordNotFound):
+		outcome = outcomeNotFound
+	case err != nil:
+		outcome = outcomeError
+		if span != nil {
+			span.SetError(err)
+		}
+	}
+	repository, method, _ := strings.Cut(name, ".")
+	repositoryCallDuration.Observe(time.Since(start).Seconds(), repository, method, outcome)
+
+	return v, err
+}
+
+// exec は戻り値がエラーだけのリポジトリの呼び出しをスパンとして記録します
+func exec(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	_, err := call(ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
@@ -0,0 +1,97 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/metrics"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingExporter は出力されたスパンを記録します
+type recordingExporter struct {
+	spans []*Trace.Span
+}
+
+func (e *recordingExporter) Export(_ context.Context, spans []*Trace.Span) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name          string
+		withParent    bool
+		err           error
+		wantSpans     int
+		wantErrStatus bool
+		wantOutcome   string
+	}{
+		{
+			name:        "成功ケース: スパンの中の呼び出しを記録する",
+			withParent:  true,
+			wantSpans:   1,
+			wantOutcome: "ok",
+		},
+		{
+			name:          "成功ケース: エラーをスパンに記録する",
+			withParent:    true,
+			err:           errors.New("database error"),
+			wantSpans:     1,
+			wantErrStatus: true,
+			wantOutcome:   "error",
+		},
+		{
+			name:        "成功ケース: データがないことはエラーとして記録しない",
+			withParent:  true,
+			err:         Errors.ErrRecordNotFound,
+			wantSpans:   1,
+			wantOutcome: "not_found",
+		},
+		{
+			name:        "成功ケース: スパンの外の呼び出しはスパンを記録せず処理時間だけ記録する",
+			wantOutcome: "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := &recordingExporter{}
+			p := Trace.NewProvider(exporter, 0, 10)
+			Trace.SetProvider(p)
+			defer Trace.SetProvider(nil)
+
+			ctx := context.Background()
+			if tt.withParent {
+				var parent *Trace.Span
+				ctx, parent = Trace.Start(ctx, "parent")
+				defer parent.End()
+			}
+
+			var called context.Context
+			err := exec(ctx, "SummaryRepository.Save", func(ctx context.Context) error {
+				called = ctx
+				return tt.err
+			})
+			assert.ErrorIs(t, err, tt.err)
+			assert.Contains(t, string(metrics.Default.Gather()),
+				`repository_call_duration_seconds_count{repository="SummaryRepository",method="Save",outcome="`+tt.wantOutcome+`"}`)
+
+			assert.NoError(t, p.Flush(context.Background()))
+			assert.Len(t, exporter.spans, tt.wantSpans)
+			if tt.wantSpans == 0 {
+				return
+			}
+			// 呼び出し先には記録中のスパンを渡す
+			assert.Equal(t, exporter.spans[0].SpanContext(), Trace.SpanContextFromContext(called))
+
+			body, _ := Trace.MarshalOTLP("test", exporter.spans)
+			assert.Contains(t, string(body), `"name":"SummaryRepository.Save"`)
+			assert.Equal(t, tt.wantErrStatus, strings.Contains(string(body), `"status":{"code":2`))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/user"
+)
+
+type userRepository struct {
+	next user.IUserRepository
+}
+
+// NewUserRepository は呼び出しごとにスパンと処理時間を記録するユーザーリポジトリを生成します
+func NewUserRepository(next user.IUserRepository) user.IUserRepository {
+	return &userRepository{next: next}
+}
+
+func (r *userRepository) Save(ctx context.Context, model *user.User) error {
+	return exec(ctx, "UserRepository.Save", func(ctx context.Context) error { return r.next.Save(ctx, model) })
+}
+
+func (r *userRepository) Update(ctx context.Context, model *user.User) error {
+	return exec(ctx, "UserRepository.Update", func(ctx context.Context) error { return r.next.Update(ctx, model) })
+}
+
+func (r *userRepository) List(ctx context.Context, opts user.ListOptions) (*user.ListResult, error) {
+	return call(ctx, "UserRepository.List", func(ctx context.Context) (*user.ListResult, error) { return r.next.List(ctx, opts) })
+}
+
+func (r *userRepository) Detail(ctx context.Context, model *user.User) (*user.User, error) {
+	return call(ctx, "UserRepository.Detail", func(ctx context.Context) (*user.User, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *userRepository) Delete(ctx context.Context, model *user.User) error {
+	return exec(ctx, "UserRepository.Delete", func(ctx context.Context) error { return r.next.Delete(ctx, model) })
+}
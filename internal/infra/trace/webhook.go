@@ -0,0 +1,64 @@
+package trace
+
+import (
+	"context"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+)
+
+type webhookRepository struct {
+	next webhook.IWebhookRepository
+}
+
+// NewWebhookRepository は呼び出しごとにスパンと処理時間を記録するWebhookリポジトリを生成します
+func NewWebhookRepository(next webhook.IWebhookRepository) webhook.IWebhookRepository {
+	return &webhookRepository{next: next}
+}
+
+func (r *webhookRepository) Save(ctx context.Context, model *webhook.Webhook) error {
+	return exec(ctx, "WebhookRepository.Save", func(ctx context.Context) error { return r.next.Save(ctx, model) })
+}
+
+func (r *webhookRepository) List(ctx context.Context, userID string) (webhook.WebhookSlice, error) {
+	return call(ctx, "WebhookRepository.List", func(ctx context.Context) (webhook.WebhookSlice, error) { return r.next.List(ctx, userID) })
+}
+
+func (r *webhookRepository) Detail(ctx context.Context, model *webhook.Webhook) (*webhook.Webhook, error) {
+	return call(ctx, "WebhookRepository.Detail", func(ctx context.Context) (*webhook.Webhook, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, model *webhook.Webhook) error {
+	return exec(ctx, "WebhookRepository.Delete", func(ctx context.Context) error { return r.next.Delete(ctx, model) })
+}
+
+type deliveryRepository struct {
+	next webhook.IDeliveryRepository
+}
+
+// NewDeliveryRepository は呼び出しごとにスパンと処理時間を記録する配信履歴のリポジトリを生成します
+func NewDeliveryRepository(next webhook.IDeliveryRepository) webhook.IDeliveryRepository {
+	return &deliveryRepository{next: next}
+}
+
+func (r *deliveryRepository) Create(ctx context.Context, model *webhook.Delivery) error {
+	return exec(ctx, "DeliveryRepository.Create", func(ctx context.Context) error { return r.next.Create(ctx, model) })
+}
+
+func (r *deliveryRepository) List(ctx context.Context, webhookID string, limit, offset int) (*webhook.DeliveryListResult, error) {
+	return call(ctx, "DeliveryRepository.List", func(ctx context.Context) (*webhook.DeliveryListResult, error) {
+		return r.next.List(ctx, webhookID, limit, offset)
+	})
+}
+
+func (r *deliveryRepository) Detail(ctx context.Context, model *webhook.Delivery) (*webhook.Delivery, error) {
+	return call(ctx, "DeliveryRepository.Detail", func(ctx context.Context) (*webhook.Delivery, error) { return r.next.Detail(ctx, model) })
+}
+
+func (r *deliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	return call(ctx, "DeliveryRepository.Claim", func(ctx context.Context) ([]*webhook.Delivery, error) { return r.next.Claim(ctx, limit, lease) })
+}
+
+func (r *deliveryRepository) UpdateResult(ctx context.Context, model *webhook.Delivery) error {
+	return exec(ctx, "DeliveryRepository.UpdateResult", func(ctx context.Context) error { return r.next.UpdateResult(ctx, model) })
+}
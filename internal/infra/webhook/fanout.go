@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	"github.com/o-ga09/web-ya-hime/internal/infra/outbox"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+)
+
+// Payload はWebhookに送信するリクエストのボディです
+type Payload struct {
+	ID         string          `json:"id"`
+	Type       event.Type      `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewFanout はイベントを購読しているWebhookごとに配信を保存する購読者を生成します
+// 送信は Sender が行うため、送信先が応答しなくてもアウトボックスの配信は遅れません
+// アウトボックスから同じイベントが複数回届いた場合も、イベントIDをキーにして配信は1回だけ保存します
+func NewFanout(webhooks webhook.IWebhookRepository, deliveries webhook.IDeliveryRepository) outbox.Subscriber {
+	return func(ctx context.Context, e *event.Event) error {
+		targets, err := webhooks.List(ctx, "")
+		if err != nil {
+			return fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		body, err := json.Marshal(Payload{ID: e.ID, Type: e.Type, OccurredAt: e.OccurredAt, Data: e.Payload})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+
+		for _, w := range targets {
+			if !w.Subscribes(e.Type) {
+				continue
+			}
+			d := &webhook.Delivery{
+				WebhookID: w.ID,
+				EventID:   e.ID,
+				EventType: e.Type,
+				Payload:   body,
+				DedupKey:  e.ID,
+			}
+			if err := deliveries.Create(ctx, d); err != nil {
+				// 前回の配信で保存済み、または保存後にWebhookが削除された場合は何もしない
+				if errors.Is(err, Errors.ErrUniqueConstraint) || errors.Is(err, Errors.ErrForeignKeyConstraint) {
+					continue
+				}
+				return fmt.Errorf("failed to create webhook delivery: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFanout(t *testing.T) {
+	e := &event.Event{
+		ID:          "event-1",
+		Type:        event.TypeSummaryCreated,
+		AggregateID: "summary-1",
+		Payload:     json.RawMessage(`{"title":"Title"}`),
+		OccurredAt:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+	hook := func(id string, active bool, types ...event.Type) *webhook.Webhook {
+		return &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: id}, EventTypes: types, Active: active}
+	}
+
+	tests := []struct {
+		name      string
+		webhooks  webhook.WebhookSlice
+		createErr error
+		wantIDs   []string
+		wantErr   bool
+	}{
+		{
+			name: "成功ケース: 購読している有効なWebhookにだけ配信を保存する",
+			webhooks: webhook.WebhookSlice{
+				hook("webhook-1", true, event.TypeSummaryCreated, event.TypeSummaryDeleted),
+				hook("webhook-2", true, event.TypeCategoryCreated),
+				hook("webhook-3", false, event.TypeSummaryCreated),
+			},
+			wantIDs: []string{"webhook-1"},
+		},
+		{
+			name:      "成功ケース: 保存済みの配信は無視する",
+			webhooks:  webhook.WebhookSlice{hook("webhook-1", true, event.TypeSummaryCreated)},
+			createErr: Errors.ErrUniqueConstraint,
+			wantIDs:   []string{"webhook-1"},
+		},
+		{
+			name:      "失敗ケース: 配信の保存に失敗",
+			webhooks:  webhook.WebhookSlice{hook("webhook-1", true, event.TypeSummaryCreated)},
+			createErr: errors.New("database error"),
+			wantIDs:   []string{"webhook-1"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := new(MockWebhookRepository)
+			webhooks.On("List", mock.Anything, "").Return(tt.webhooks, nil)
+			deliveries := new(MockDeliveryRepository)
+			var created []*webhook.Delivery
+			deliveries.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				created = append(created, args.Get(1).(*webhook.Delivery))
+			}).Return(tt.createErr)
+
+			err := NewFanout(webhooks, deliveries)(context.Background(), e)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var ids []string
+			for _, d := range created {
+				ids = append(ids, d.WebhookID)
+				assert.Equal(t, e.ID, d.EventID)
+				assert.Equal(t, e.ID, d.DedupKey)
+				assert.Equal(t, e.Type, d.EventType)
+				assert.JSONEq(t, `{"id":"event-1","type":"summary.created","occurred_at":"2026-10-19T00:00:00Z","data":{"title":"Title"}}`, string(d.Payload))
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
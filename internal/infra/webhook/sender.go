@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
+)
+
+// 送信の設定
+var (
+	// claimLease は取得した配信を他の送信処理に渡さない時間です
+	// WEBHOOK_TIMEOUT より長くしないと、応答を待っている間に同じ配信が再度送信されます
+	claimLease = 5 * time.Minute
+	// maxRetryDelay は再試行の間隔の上限です
+	maxRetryDelay = time.Hour
+	// maxLastError は配信履歴に保存するエラーの上限 (バイト) です
+	// レスポンスのボディは送信先の内部の情報を含むことがあるため保存しません
+	maxLastError = 512
+	// maxDrainBody は接続を再利用するために読み捨てるレスポンスのボディの上限 (バイト) です
+	maxDrainBody = 4096
+	// dialControl は接続する直前に送信先のIPアドレスを確認します
+	// テストではループバックのサーバーに送信するため置き換えます
+	dialControl = httputil.PublicDialControl
+)
+
+// Sender は保存された配信を定期的に取得してWebhookに送信します
+// 2xx 以外のレスポンスや通信エラーの場合は間隔を倍々に延ばして再試行し、WEBHOOK_MAX_ATTEMPTS 回失敗すると
+// dead にして送信を諦めます。Webhookが削除・無効化されている場合は送信せずに dead にします
+type Sender struct {
+	webhooks    webhook.IWebhookRepository
+	deliveries  webhook.IDeliveryRepository
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	baseDelay   time.Duration
+	now         func() time.Time
+}
+
+// NewSender は WEBHOOK_* の設定で送信処理を生成します
+func NewSender(webhooks webhook.IWebhookRepository, deliveries webhook.IDeliveryRepository, cfg *config.Config) *Sender {
+	return &Sender{
+		webhooks:    webhooks,
+		deliveries:  deliveries,
+		client:      newClient(cfg.WebhookTimeout),
+		interval:    cfg.WebhookPollInterval,
+		batchSize:   cfg.WebhookBatchSize,
+		maxAttempts: cfg.WebhookMaxAttempts,
+		baseDelay:   cfg.WebhookRetryBaseDelay,
+		now:         time.Now,
+	}
+}
+
+// newClient は内部のアドレスに接続せず、リダイレクトに従わないクライアントを生成します
+// 登録時にURLを検証していても名前解決の結果は変わるため、接続時に dialControl で確認します
+func newClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// プロキシを経由すると接続先のIPアドレスを確認できないため使わない
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialControl}).DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &Trace.Transport{Base: transport},
+		// リダイレクト先は検証していないため従わず、3xx のレスポンスを送信の失敗として扱う
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run は ctx がキャンセルされるまで定期的に送信します
+// ctx には配信履歴のリポジトリが使うデータベースの接続が設定されている必要があります
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		// 1回で取得しきれなかった場合は待たずに続けて送信する
+		for {
+			n, err := s.SendPending(ctx)
+			if err != nil {
+				logger.Error(ctx, fmt.Sprintf("failed to send webhook deliveries: %v", err))
+				break
+			}
+			if n < s.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendPending は配信予定時刻を過ぎた配信を1回分取得して送信し、取得した件数を返します
+func (s *Sender) SendPending(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveries.Claim(ctx, s.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			// 取得済みの配信はリースが切れた後に再度送信される
+			return len(deliveries), ctx.Err()
+		}
+		if err := s.send(ctx, d); err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// send は1つの配信を送信し、結果を配信履歴に記録します
+func (s *Sender) send(ctx context.Context, d *webhook.Delivery) error {
+	w, err := s.webhooks.Detail(ctx, &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: d.WebhookID}})
+	switch {
+	case errors.Is(err, Errors.ErrRecordNotFound):
+		return s.giveUp(ctx, d, "webhook not found")
+	case err != nil:
+		return err
+	case !w.Active:
+		return s.giveUp(ctx, d, "webhook is inactive")
+	}
+
+	d.Attempts++
+	status, err := s.post(ctx, w, d)
+	d.ResponseStatus = status
+	if err == nil && (status < 200 || status > 299) {
+		err = fmt.Errorf("unexpected status code: %d", status)
+	}
+
+	if err == nil {
+		deliveredAt := s.now()
+		d.Status = webhook.DeliveryStatusSucceeded
+		d.LastError = ""
+		d.DeliveredAt = &deliveredAt
+		d.NextAttemptAt = deliveredAt
+		return s.deliveries.UpdateResult(ctx, d)
+	}
+
+	d.LastError = truncate(err.Error(), maxLastError)
+	if d.Attempts >= s.maxAttempts {
+		d.Status = webhook.DeliveryStatusDead
+		d.NextAttemptAt = s.now()
+		logger.Error(ctx, fmt.Sprintf("webhook delivery %s (%s) gave up after %d attempts: %s", d.ID, d.EventType, d.Attempts, d.LastError))
+	} else {
+		d.Status = webhook.DeliveryStatusPending
+		d.NextAttemptAt = s.now().Add(s.retryDelay(d.Attempts))
+		logger.Warn(ctx, fmt.Sprintf("webhook delivery %s (%s) failed (attempt %d): %s", d.ID, d.EventType, d.Attempts, d.LastError))
+	}
+	return s.deliveries.UpdateResult(ctx, d)
+}
+
+// post は署名したリクエストを送信し、レスポンスのステータスコードを返します
+func (s *Sender) post(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "web-ya-hime-webhook/1.0")
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, d.Payload))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, d.ID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, int64(maxDrainBody)))
+	return resp.StatusCode, nil
+}
+
+// truncate は s を UTF-8 の文字の途中で切らないように n バイト以内に切り詰めます
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// giveUp は送信できない配信を再試行せずに dead にします
+func (s *Sender) giveUp(ctx context.Context, d *webhook.Delivery, reason string) error {
+	d.Status = webhook.DeliveryStatusDead
+	d.LastError = reason
+	d.NextAttemptAt = s.now()
+	logger.Warn(ctx, fmt.Sprintf("webhook delivery %s (%s) dropped: %s", d.ID, d.EventType, reason))
+	return s.deliveries.UpdateResult(ctx, d)
+}
+
+// retryDelay は attempts 回目の失敗後に待つ時間を返します
+func (s *Sender) retryDelay(attempts int) time.Duration {
+	delay := s.baseDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
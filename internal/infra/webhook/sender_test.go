@@ -0,0 +1,366 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	"github.com/o-ga09/web-ya-hime/internal/domain/webhook"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository はwebhook.IWebhookRepositoryのモック
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Save(ctx context.Context, model *webhook.Webhook) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context, userID string) (webhook.WebhookSlice, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(webhook.WebhookSlice), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Detail(ctx context.Context, model *webhook.Webhook) (*webhook.Webhook, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, model *webhook.Webhook) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+// MockDeliveryRepository はwebhook.IDeliveryRepositoryのモック
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) Create(ctx context.Context, model *webhook.Delivery) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) List(ctx context.Context, webhookID string, limit, offset int) (*webhook.DeliveryListResult, error) {
+	args := m.Called(ctx, webhookID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.DeliveryListResult), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Detail(ctx context.Context, model *webhook.Delivery) (*webhook.Delivery, error) {
+	args := m.Called(ctx, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) UpdateResult(ctx context.Context, model *webhook.Delivery) error {
+	args := m.Called(ctx, model)
+	return args.Error(0)
+}
+
+// receiver はWebhookを受信するテスト用のサーバーが受け取ったリクエストです
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func (rc *receiver) handler(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		rc.requests = append(rc.requests, r)
+		rc.bodies = append(rc.bodies, string(b))
+		rc.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// allowLoopback はテストのサーバーに送信できるように、送信先のIPアドレスの確認を無効にします
+func allowLoopback(t *testing.T) {
+	t.Helper()
+	original := dialControl
+	dialControl = nil
+	t.Cleanup(func() { dialControl = original })
+}
+
+func TestSender_SendPending(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	payload := `{"id":"event-1","type":"summary.created","occurred_at":"2026-10-19T00:00:00Z","data":{"title":"Title"}}`
+
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		attempts      int
+		webhookFound  bool
+		inactive      bool
+		wantRequests  int
+		wantStatus    string
+		wantAttempts  int
+		wantNext      time.Time
+		wantLastError string
+	}{
+		{
+			name:         "成功ケース: 2xxのレスポンスで送信済みにする",
+			status:       http.StatusNoContent,
+			webhookFound: true,
+			wantRequests: 1,
+			wantStatus:   webhook.DeliveryStatusSucceeded,
+			wantAttempts: 1,
+			wantNext:     now,
+		},
+		{
+			name:          "成功ケース: 2xx以外のレスポンスは間隔を倍にして再試行する",
+			status:        http.StatusInternalServerError,
+			body:          "internal server error",
+			attempts:      2,
+			webhookFound:  true,
+			wantRequests:  1,
+			wantStatus:    webhook.DeliveryStatusPending,
+			wantAttempts:  3,
+			wantNext:      now.Add(40 * time.Second),
+			wantLastError: "unexpected status code: 500",
+		},
+		{
+			name:          "成功ケース: 上限回数失敗するとdeadにする",
+			status:        http.StatusBadGateway,
+			attempts:      4,
+			webhookFound:  true,
+			wantRequests:  1,
+			wantStatus:    webhook.DeliveryStatusDead,
+			wantAttempts:  5,
+			wantNext:      now,
+			wantLastError: "unexpected status code: 502",
+		},
+		{
+			name:          "成功ケース: 削除されたWebhookには送信せずにdeadにする",
+			wantRequests:  0,
+			wantStatus:    webhook.DeliveryStatusDead,
+			wantNext:      now,
+			wantLastError: "webhook not found",
+		},
+		{
+			name:          "成功ケース: 無効なWebhookには送信せずにdeadにする",
+			webhookFound:  true,
+			inactive:      true,
+			wantRequests:  0,
+			wantStatus:    webhook.DeliveryStatusDead,
+			wantNext:      now,
+			wantLastError: "webhook is inactive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowLoopback(t)
+			rc := &receiver{}
+			server := httptest.NewServer(rc.handler(tt.status, tt.body))
+			defer server.Close()
+
+			hook := &webhook.Webhook{
+				WYHBaseModel: domain.WYHBaseModel{ID: "webhook-1"},
+				URL:          server.URL,
+				Secret:       "secret",
+				EventTypes:   []event.Type{event.TypeSummaryCreated},
+				Active:       !tt.inactive,
+			}
+			delivery := &webhook.Delivery{
+				ID:        "delivery-1",
+				WebhookID: hook.ID,
+				EventID:   "event-1",
+				EventType: event.TypeSummaryCreated,
+				Payload:   []byte(payload),
+				Status:    webhook.DeliveryStatusPending,
+				Attempts:  tt.attempts,
+			}
+
+			webhooks := new(MockWebhookRepository)
+			if tt.webhookFound {
+				webhooks.On("Detail", mock.Anything, mock.Anything).Return(hook, nil)
+			} else {
+				webhooks.On("Detail", mock.Anything, mock.Anything).Return(nil, Errors.ErrRecordNotFound)
+			}
+			deliveries := new(MockDeliveryRepository)
+			deliveries.On("Claim", mock.Anything, 50, claimLease).Return([]*webhook.Delivery{delivery}, nil)
+			deliveries.On("UpdateResult", mock.Anything, delivery).Return(nil)
+
+			sender := NewSender(webhooks, deliveries, &config.Config{
+				WebhookBatchSize:      50,
+				WebhookMaxAttempts:    5,
+				WebhookRetryBaseDelay: 10 * time.Second,
+				WebhookTimeout:        time.Second,
+			})
+			sender.now = func() time.Time { return now }
+
+			n, err := sender.SendPending(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			assert.Len(t, rc.requests, tt.wantRequests)
+			if tt.wantRequests > 0 {
+				req := rc.requests[0]
+				assert.Equal(t, payload, rc.bodies[0])
+				assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+				assert.Equal(t, "summary.created", req.Header.Get(HeaderEvent))
+				assert.Equal(t, "delivery-1", req.Header.Get(HeaderDelivery))
+				assert.Equal(t, "1792368000", req.Header.Get(HeaderTimestamp))
+				assert.True(t, Verify("secret", req.Header.Get(HeaderSignature), req.Header.Get(HeaderTimestamp), []byte(rc.bodies[0])))
+				assert.Equal(t, tt.status, delivery.ResponseStatus)
+			}
+
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantAttempts, delivery.Attempts)
+			assert.Equal(t, tt.wantNext, delivery.NextAttemptAt)
+			assert.Equal(t, tt.wantLastError, delivery.LastError)
+			if tt.wantStatus == webhook.DeliveryStatusSucceeded {
+				assert.Equal(t, &now, delivery.DeliveredAt)
+			} else {
+				assert.Nil(t, delivery.DeliveredAt)
+			}
+			webhooks.AssertExpectations(t)
+			deliveries.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSender_SendPending_NonPublicAddress(t *testing.T) {
+	// 登録後に名前解決の結果が内部のアドレスに変わっても、接続時に拒否する
+	rc := &receiver{}
+	server := httptest.NewServer(rc.handler(http.StatusOK, "metadata"))
+	defer server.Close()
+
+	hook := &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: "webhook-1"}, URL: server.URL, Secret: "secret", Active: true}
+	delivery := &webhook.Delivery{ID: "delivery-1", WebhookID: hook.ID, Payload: []byte(`{}`)}
+
+	webhooks := new(MockWebhookRepository)
+	webhooks.On("Detail", mock.Anything, mock.Anything).Return(hook, nil)
+	deliveries := new(MockDeliveryRepository)
+	deliveries.On("Claim", mock.Anything, 1, claimLease).Return([]*webhook.Delivery{delivery}, nil)
+	deliveries.On("UpdateResult", mock.Anything, delivery).Return(nil)
+
+	sender := NewSender(webhooks, deliveries, &config.Config{WebhookBatchSize: 1, WebhookMaxAttempts: 3, WebhookRetryBaseDelay: time.Second, WebhookTimeout: time.Second})
+	_, err := sender.SendPending(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, rc.requests)
+	assert.Equal(t, webhook.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.Contains(t, delivery.LastError, "destination is not a public address")
+}
+
+func TestSender_SendPending_Redirect(t *testing.T) {
+	allowLoopback(t)
+	rc := &receiver{}
+	target := httptest.NewServer(rc.handler(http.StatusOK, ""))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer server.Close()
+
+	hook := &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: "webhook-1"}, URL: server.URL, Secret: "secret", Active: true}
+	delivery := &webhook.Delivery{ID: "delivery-1", WebhookID: hook.ID, Payload: []byte(`{}`)}
+
+	webhooks := new(MockWebhookRepository)
+	webhooks.On("Detail", mock.Anything, mock.Anything).Return(hook, nil)
+	deliveries := new(MockDeliveryRepository)
+	deliveries.On("Claim", mock.Anything, 1, claimLease).Return([]*webhook.Delivery{delivery}, nil)
+	deliveries.On("UpdateResult", mock.Anything, delivery).Return(nil)
+
+	sender := NewSender(webhooks, deliveries, &config.Config{WebhookBatchSize: 1, WebhookMaxAttempts: 3, WebhookRetryBaseDelay: time.Second, WebhookTimeout: time.Second})
+	_, err := sender.SendPending(context.Background())
+	assert.NoError(t, err)
+	// リダイレクト先には送信しない
+	assert.Empty(t, rc.requests)
+	assert.Equal(t, webhook.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, http.StatusFound, delivery.ResponseStatus)
+	assert.Equal(t, "unexpected status code: 302", delivery.LastError)
+}
+
+func TestSender_SendPending_ConnectionError(t *testing.T) {
+	// 接続できない送信先は、レスポンスがない失敗として再試行する
+	allowLoopback(t)
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	hook := &webhook.Webhook{WYHBaseModel: domain.WYHBaseModel{ID: "webhook-1"}, URL: url, Secret: "secret", Active: true}
+	delivery := &webhook.Delivery{ID: "delivery-1", WebhookID: hook.ID, Payload: []byte(`{}`)}
+
+	webhooks := new(MockWebhookRepository)
+	webhooks.On("Detail", mock.Anything, mock.Anything).Return(hook, nil)
+	deliveries := new(MockDeliveryRepository)
+	deliveries.On("Claim", mock.Anything, 1, claimLease).Return([]*webhook.Delivery{delivery}, nil)
+	deliveries.On("UpdateResult", mock.Anything, delivery).Return(nil)
+
+	sender := NewSender(webhooks, deliveries, &config.Config{WebhookBatchSize: 1, WebhookMaxAttempts: 3, WebhookRetryBaseDelay: time.Second, WebhookTimeout: time.Second})
+	_, err := sender.SendPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, webhook.DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.LastError)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "ab", truncate("abcdef", 2))
+	// 文字の途中で切らない
+	assert.Equal(t, "あ", truncate("あい", 4))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"event-1"}`)
+	signature := Sign("secret", "1792368000", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		want      bool
+	}{
+		{name: "成功ケース: 同じ秘密鍵とタイムスタンプとボディ", secret: "secret", signature: signature, timestamp: "1792368000", body: body, want: true},
+		{name: "失敗ケース: 秘密鍵が異なる", secret: "other", signature: signature, timestamp: "1792368000", body: body, want: false},
+		{name: "失敗ケース: タイムスタンプが異なる", secret: "secret", signature: signature, timestamp: "1792368001", body: body, want: false},
+		{name: "失敗ケース: ボディが改ざんされている", secret: "secret", signature: signature, timestamp: "1792368000", body: []byte(`{"id":"event-2"}`), want: false},
+		{name: "失敗ケース: 形式が異なる", secret: "secret", signature: strings.TrimPrefix(signature, "sha256="), timestamp: "1792368000", body: body, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Verify(tt.secret, tt.signature, tt.timestamp, tt.body))
+		})
+	}
+}
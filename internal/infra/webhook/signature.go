@@ -0,0 +1,41 @@
+// Package webhook はドメインイベントを登録されたWebhookに送信します
+// アウトボックスの購読者 (NewFanout) がイベントごとに配信を保存し、Sender が署名付きで送信して再試行します
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// 送信するリクエストのヘッダー
+const (
+	// HeaderSignature は "sha256=<HMAC-SHA256の16進数>" の形式の署名です
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderTimestamp は署名した時刻のUNIX時間 (秒) です
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	// HeaderDelivery は配信IDです。再送では新しい配信IDになります
+	HeaderDelivery = "X-Webhook-Delivery"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign はWebhookの秘密鍵で "<timestamp>.<body>" に署名し、X-Webhook-Signature ヘッダーの値を返します
+// タイムスタンプを署名に含めるため、受信側はタイムスタンプが古いリクエストを拒否することでリプレイを防げます
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify は受信したリクエストの署名が正しいかを検証します
+func Verify(secret, signature, timestamp string, body []byte) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
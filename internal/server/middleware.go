@@ -1,64 +1,337 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"database/sql"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/o-ga09/web-ya-hime/internal/infra/database/mysql"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
 	"github.com/o-ga09/web-ya-hime/pkg/constant"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/logger"
-	"github.com/o-ga09/web-ya-hime/pkg/uuid"
+	"github.com/o-ga09/web-ya-hime/pkg/metrics"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
 )
 
-type RequestId string
-
-const RequestIdKey RequestId = "requestId"
+const (
+	// RequestIDHeader はリクエストIDを受け取り、レスポンスで返すヘッダーです
+	RequestIDHeader = "X-Request-ID"
+	// CloudTraceContextHeader は Cloud Run が付与するトレースのヘッダーです (TRACE_ID/SPAN_ID;o=OPTIONS)
+	CloudTraceContextHeader = Trace.CloudTraceContextHeader
+	// maxRequestIDLength は受け取るリクエストIDの最大の長さです
+	maxRequestIDLength = 128
+)
 
 // AddIDはリクエスト毎にIDを付与するmiddlewareです。
+// X-Request-ID、X-Cloud-Trace-Context のトレースIDの順に受け取ったIDを使い、どちらもなければ生成します
+// IDはログとエラーレスポンスに出力し、X-Request-ID ヘッダーで返します
+// リクエストのcontextには ctx の設定を引き継ぎます
 func AddID(ctx context.Context, next http.HandlerFunc) http.HandlerFunc {
+	cfg := Ctx.GetCtxCfg(ctx)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// IDを生成してcontextに保存
-		id := uuid.GenerateID()
-		ctx := context.WithValue(ctx, RequestIdKey, id)
+		id := requestIDFrom(r)
+		ctx := Ctx.SetCtxCfg(r.Context(), cfg)
+		ctx = Ctx.SetRequestID(ctx, id)
+		w.Header().Set(RequestIDHeader, Ctx.GetRequestID(ctx))
 		// 次のハンドラーに渡す
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// WithTimeoutはIDを追加するmiddlewareです。
-func WithTimeout(next http.HandlerFunc) http.HandlerFunc {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Context() == nil {
-			r = r.WithContext(context.Background())
+// requestIDFrom はリクエストのヘッダーからリクエストIDを取得します。使えるIDがない場合は空文字を返します
+func requestIDFrom(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); validRequestID(id) {
+		return id
+	}
+	if trace, _, _ := strings.Cut(r.Header.Get(CloudTraceContextHeader), "/"); validRequestID(trace) {
+		return trace
+	}
+	return ""
+}
+
+// validRequestID はログやヘッダーにそのまま出力できるIDかを判定します
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
 		}
+	}
+	return true
+}
 
-		// タイムアウトを設定
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel() // 処理が終了したらキャンセルする
+// WithTimeout はハンドラーの処理時間を timeout までに制限するミドルウェアです
+// ハンドラーには期限付きのcontextを渡すため、リポジトリの呼び出しも期限で打ち切られます
+// レスポンスはハンドラーが終わるまでバッファし、期限までに終わった場合だけクライアントに返します
+// 期限を過ぎた場合は503を返し、その後のハンドラーの書き込みは http.ErrHandlerTimeout で捨てます
+// timeout が0以下の場合は制限しません
+func WithTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
 
-		// 次のハンドラーを実行し、タイムアウトが発生した場合はエラーメッセージを出力
+		tw := &timeoutWriter{h: w.Header().Clone()}
 		done := make(chan struct{})
+		panicked := make(chan any, 1)
 		go func() {
-			defer close(done)
-			next.ServeHTTP(w, r)
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- fmt.Sprintf("%v\n%s", p, debug.Stack())
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
 		}()
+
 		select {
+		case p := <-panicked:
+			// http.Server の回復処理に任せるため、リクエストを処理するgoroutineで panic し直す
+			panic(p)
 		case <-done:
-			// ハンドラーが正常に終了した場合は何もしない
-			return
+			tw.flushTo(w)
 		case <-ctx.Done():
-			http.Error(w, "Timeout", http.StatusRequestTimeout)
+			// 期限と同時にハンドラーが終わった場合は select がどちらを選ぶか決まらないため、
+			// レスポンスを書き終えていればそれを返す
+			select {
+			case <-done:
+				if tw.written() {
+					tw.flushTo(w)
+					return
+				}
+			default:
+			}
+			tw.timeout()
+			if r.Context().Err() != nil {
+				// クライアントが切断した場合は返す相手がいない
+				return
+			}
+			response.Error(r.Context(), w, Errors.ErrTimeout)
+		}
+	})
+}
+
+// LimitBody はリクエストボディを n バイトまでに制限するミドルウェアです
+// Content-Length が n を超える場合はボディを読まずに413を返し、それ以外は読み込みが n を超えた時点でエラーにします
+// (request.Bind は Errors.ErrRequestEntityTooLarge を返します)。n が0以下の場合は制限しません
+func LimitBody(n int64, next http.HandlerFunc) http.HandlerFunc {
+	if n <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > n {
+			response.Error(r.Context(), w, fmt.Errorf("request body of %d bytes exceeds %d bytes: %w", r.ContentLength, n, Errors.ErrRequestEntityTooLarge))
+			return
+		}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// timeoutWriter はハンドラーのレスポンスをバッファする http.ResponseWriter です
+// ヘッダーはハンドラーのgoroutineだけが変更し、完了を待ってから読み取ります
+type timeoutWriter struct {
+	h http.Header
+
+	mu          sync.Mutex
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.h }
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	tw.wroteHeader = true
+	tw.status = code
+}
+
+// written はハンドラーがステータスかボディを書き込んだかを返します
+func (tw *timeoutWriter) written() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.wroteHeader
+}
+
+// timeout は期限切れを記録し、以降の書き込みを捨てます
+func (tw *timeoutWriter) timeout() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+}
+
+// flushTo はバッファしたヘッダーとボディを w に書き込みます。ハンドラーが終わってから呼び出します
+func (tw *timeoutWriter) flushTo(w http.ResponseWriter) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	dst := w.Header()
+	clear(dst)
+	maps.Copy(dst, tw.h)
+	if !tw.wroteHeader {
+		tw.status = http.StatusOK
+	}
+	w.WriteHeader(tw.status)
+	_, _ = w.Write(tw.buf.Bytes())
+}
+
+// Tracing はリクエストごとにスパンを記録するミドルウェアです
+// traceparent または X-Cloud-Trace-Context ヘッダーがあれば呼び出し元のトレースを引き継ぎます
+// スパンの名前はルーティングのパターン (例: GET /summaries/{id}) です
+func Tracing(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if sc, ok := Trace.Extract(r.Header); ok {
+			ctx = Trace.ContextWithRemote(ctx, sc)
+		}
+
+		ctx, span := Trace.Start(ctx, routeName(r), Trace.WithKind(Trace.SpanKindServer), Trace.WithAttributes(
+			"http.request.method", r.Method,
+			"url.path", r.URL.Path,
+			"request.id", Ctx.GetRequestID(ctx),
+		))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		status := rec.statusCode()
+		span.SetAttributes("http.response.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("%d %s", status, http.StatusText(status)))
 		}
 	})
 }
 
-func GetRequestID(ctx context.Context) string {
-	return ctx.Value(RequestIdKey).(string)
+var (
+	httpRequestsTotal = metrics.Register(metrics.NewCounterVec(
+		"http_requests_total", "Total number of HTTP requests.", "route", "status"))
+	httpRequestDuration = metrics.Register(metrics.NewHistogramVec(
+		"http_request_duration_seconds", "HTTP request latency in seconds.", metrics.DefBuckets, "route", "status"))
+	httpRequestsInFlight = metrics.Register(metrics.NewGaugeVec(
+		"http_requests_in_flight", "Number of HTTP requests currently being served."))
+)
+
+// Metrics はリクエスト数・処理時間・処理中のリクエスト数を記録するミドルウェアです
+// route ラベルはパスではなくルーティングのパターンにして、ラベルの値の数が増え続けないようにします
+func Metrics(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		route, status := routeName(r), strconv.Itoa(rec.statusCode())
+		httpRequestsTotal.Inc(route, status)
+		httpRequestDuration.Observe(time.Since(start).Seconds(), route, status)
+	})
+}
+
+// routeName はリクエストに一致したルーティングのパターンをメソッド付きで返します (例: GET /summaries/{id})
+func routeName(r *http.Request) string {
+	name := r.Pattern
+	if name == "" {
+		name = r.URL.Path
+	}
+	if !strings.HasPrefix(name, r.Method+" ") {
+		name = r.Method + " " + name
+	}
+	return name
+}
+
+// statusRecorder はレスポンスのステータスコードを記録します
+// Unwrap で元の ResponseWriter を返すため、http.ResponseController で Flush できます
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Languageは Accept-Language ヘッダーからレスポンスの言語を決めてcontextに保存するmiddlewareです。
+func Language(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", string(lang))
+		w.Header().Add("Vary", "Accept-Language")
+
+		ctx := i18n.WithLang(r.Context(), lang)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 type RequestInfo struct {
@@ -85,7 +358,7 @@ func (r *RequestInfo) LogValue() interface{} { // Assuming slog expects an inter
 
 func RequestLogger(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		slog.Log(r.Context(), constant.SeverityInfo, "処理開始", "requestId", GetRequestID(r.Context()))
+		slog.Log(r.Context(), constant.SeverityInfo, "処理開始", "requestId", Ctx.GetRequestID(r.Context()))
 		start := time.Now()
 
 		next.ServeHTTP(w, r)
@@ -100,7 +373,7 @@ func RequestLogger(next http.HandlerFunc) http.HandlerFunc {
 			Elapsed:        time.Since(start),
 		}
 
-		slog.Log(r.Context(), constant.SeverityInfo, "処理終了", "Request", req.LogValue(), "requestId", GetRequestID(r.Context())) // Adjust logging context as needed
+		slog.Log(r.Context(), constant.SeverityInfo, "処理終了", "Request", req.LogValue(), "requestId", Ctx.GetRequestID(r.Context())) // Adjust logging context as needed
 	})
 }
 
@@ -115,20 +388,26 @@ func (h *traceHandler) Enabled(ctx context.Context, l slog.Level) bool {
 	return h.Handler.Enabled(ctx, l)
 }
 
+// Handle は実行中のスパンのトレースIDとスパンIDをログに追加します
+// スパンの外のログ (起動時やバックグラウンドの処理) には追加しません
 func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
-	trace := fmt.Sprintf("projects/%s/traces/%s", h.projectID, uuid.GenerateID())
-	r.AddAttrs(slog.String("logging.googleapis.com/trace", trace),
-		slog.String("logging.googleapis.com/spanId", uuid.GenerateID()))
+	if sc := Trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("logging.googleapis.com/trace", fmt.Sprintf("projects/%s/traces/%s", h.projectID, sc.TraceID)),
+			slog.String("logging.googleapis.com/spanId", sc.SpanID.String()),
+			slog.Bool("logging.googleapis.com/trace_sampled", sc.Sampled),
+		)
+	}
 
 	return h.Handler.Handle(ctx, r)
 }
 
-func (h *traceHandler) WithAttr(attrs []slog.Attr) slog.Handler {
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &traceHandler{h.Handler.WithAttrs(attrs), h.projectID}
 }
 
 func (h *traceHandler) WithGroup(g string) slog.Handler {
-	return h.Handler.WithGroup(g)
+	return &traceHandler{h.Handler.WithGroup(g), h.projectID}
 }
 
 // logger 生成関数
@@ -153,7 +432,7 @@ func Logger(next http.HandlerFunc) http.HandlerFunc {
 		env := Ctx.GetCtxCfg(r.Context())
 		projectID := env.ProjectID
 		h := traceHandler{slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true, ReplaceAttr: replacer}), projectID}
-		newh := h.WithAttr([]slog.Attr{
+		newh := h.WithAttrs([]slog.Attr{
 			slog.Group("logging.googleapis.com/labels",
 				slog.String("app", "MH-API"),
 				slog.String("env", env.Env),
@@ -165,56 +444,740 @@ func Logger(next http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
-func Cors(next http.HandlerFunc) http.HandlerFunc {
+// corsAllowedHeaders はクロスオリジンのリクエストで送信できるヘッダーです
+var corsAllowedHeaders = []string{
+	"Content-Type", "Authorization", "If-None-Match", "Last-Event-ID",
+	RequestIDHeader, Trace.TraceparentHeader, ReadYourWritesHeader,
+}
+
+// corsExposedHeaders はクロスオリジンのレスポンスでスクリプトから読み取れるヘッダーです
+// CORS_EXPOSED_HEADERS で追加できます
+var corsExposedHeaders = []string{
+	"ETag", RequestIDHeader,
+	RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, RateLimitPolicyHeader, "Retry-After",
+}
+
+// corsProbeMethods はプリフライトで ServeMux に登録されているか確認するメソッドです
+var corsProbeMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// CORS はオリジンごとにクロスオリジンのリクエストを許可します
+// 許可するオリジンはルート (パスの前方一致) ごとに上書きできます
+type CORS struct {
+	origins          []string
+	routes           []corsRoute
+	allowCredentials bool
+	allowHeaders     string
+	exposeHeaders    string
+	maxAge           string
+}
+
+// corsRoute は prefix のパスで許可するオリジンの上書きです
+type corsRoute struct {
+	prefix  string
+	origins []string
+}
+
+type corsAllowedKey struct{}
+
+// NewCORS は設定の CORS_* から CORS を生成します
+// 認証情報を送信させる場合はすべてのオリジン (*) を許可できません
+func NewCORS(cfg *config.Config) (*CORS, error) {
+	c := &CORS{
+		allowCredentials: cfg.CORSAllowCredentials,
+		allowHeaders:     strings.Join(corsAllowedHeaders, ", "),
+		exposeHeaders:    strings.Join(append(slices.Clone(corsExposedHeaders), cfg.CORSExposedHeaders...), ", "),
+		maxAge:           strconv.Itoa(int(cfg.CORSMaxAge.Seconds())),
+	}
+
+	origins, err := c.parseOrigins(cfg.CORSAllowedOrigins)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS_ALLOWED_ORIGINS: %w", err)
+	}
+	c.origins = origins
+
+	for _, v := range cfg.CORSRouteOrigins {
+		prefix, list, ok := strings.Cut(v, "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid CORS_ROUTE_ORIGINS entry %q: want /path=origin [origin...]", v)
+		}
+		origins, err := c.parseOrigins(strings.Fields(list))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS_ROUTE_ORIGINS entry %q: %w", v, err)
+		}
+		c.routes = append(c.routes, corsRoute{prefix: strings.TrimSuffix(prefix, "/"), origins: origins})
+	}
+	// 長い (具体的な) パスを優先する
+	slices.SortFunc(c.routes, func(a, b corsRoute) int { return len(b.prefix) - len(a.prefix) })
+	return c, nil
+}
+
+func (c *CORS) parseOrigins(values []string) ([]string, error) {
+	origins := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(v), "/"))
+		if v == "*" {
+			if c.allowCredentials {
+				return nil, fmt.Errorf("* cannot be used with CORS_ALLOW_CREDENTIALS")
+			}
+			origins = append(origins, v)
+			continue
+		}
+		scheme, host, ok := strings.Cut(v, "://")
+		if !ok || scheme == "" || host == "" || strings.Contains(host, "/") || strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return nil, fmt.Errorf("invalid origin %q: want scheme://host[:port] or scheme://*.host[:port]", v)
+		}
+		origins = append(origins, v)
+	}
+	return origins, nil
+}
+
+// originsFor は path で許可するオリジンを返します
+func (c *CORS) originsFor(path string) []string {
+	for _, route := range c.routes {
+		if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+			return route.origins
+		}
+	}
+	return c.origins
+}
+
+// allowOrigin は path で origin を許可する場合に Access-Control-Allow-Origin の値を返します
+// 認証情報を送信させない場合に * で許可していれば * を返し、それ以外はオリジンをそのまま返します
+func (c *CORS) allowOrigin(path, origin string) (string, bool) {
+	lower := strings.ToLower(origin)
+	for _, pattern := range c.originsFor(path) {
+		switch {
+		case pattern == "*":
+			return "*", true
+		case pattern == lower:
+			return origin, true
+		case matchWildcardOrigin(pattern, lower):
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// matchWildcardOrigin は https://*.example.com のようなパターンに一致するサブドメインのオリジンかどうかを返します
+// example.com 自体は一致しません
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, host, _ := strings.Cut(pattern, "://")
+	suffix, ok := strings.CutPrefix(host, "*")
+	if !ok {
+		return false
+	}
+	originScheme, originHost, _ := strings.Cut(origin, "://")
+	return originScheme == scheme && len(originHost) > len(suffix) && strings.HasSuffix(originHost, suffix)
+}
+
+// Handler はクロスオリジンのリクエストに CORS のヘッダーを付け、プリフライトに応答するハンドラーです
+// ルーティングの前に処理するため ServeMux 全体を包みます
+// プリフライトは要求されたメソッドが mux にそのパスで登録されている場合だけ許可し、
+// 登録されていないパスは404、登録されていないメソッドは405で拒否します
+// 許可したオリジンのリクエストは Csrf のクロスオリジンの確認を省略します
+func (c *CORS) Handler(mux *http.ServeMux) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowOrigin, allowed := c.allowOrigin(r.URL.Path, origin)
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.preflight(w, r, mux, allowOrigin, allowed)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		if allowed {
+			c.setCommonHeaders(w, allowOrigin)
+			w.Header().Set("Access-Control-Expose-Headers", c.exposeHeaders)
+			r = r.WithContext(context.WithValue(r.Context(), corsAllowedKey{}, true))
+		}
+		mux.ServeHTTP(w, r)
 	})
 }
 
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, mux *http.ServeMux, allowOrigin string, allowed bool) {
+	if !allowed {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	methods := registeredMethods(mux, r)
+	if len(methods) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) {
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	c.setCommonHeaders(w, allowOrigin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	w.Header().Set("Access-Control-Allow-Headers", c.allowHeaders)
+	w.Header().Set("Access-Control-Max-Age", c.maxAge)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *CORS) setCommonHeaders(w http.ResponseWriter, allowOrigin string) {
+	w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+	if c.allowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// registeredMethods は r のパスで mux に登録されているメソッドを返します
+// メソッドを指定していないパターン (例: /health) はすべてのメソッドを返します
+func registeredMethods(mux *http.ServeMux, r *http.Request) []string {
+	var methods []string
+	for _, method := range corsProbeMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := mux.Handler(probe); pattern != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// corsAllowed は CORS で許可したオリジンのリクエストの場合に true を返します
+func corsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(corsAllowedKey{}).(bool)
+	return allowed
+}
+
+// compressionEncodings は対応している圧縮の形式です
+var compressionEncodings = map[string]*sync.Pool{
+	"gzip": {New: func() any { return gzip.NewWriter(io.Discard) }},
+	// HTTP の deflate は zlib の形式 (RFC 1950) です
+	"deflate": {New: func() any { return zlib.NewWriter(io.Discard) }},
+}
+
+// incompressibleTypes は圧縮しない Content-Type です。末尾が / のものは前方一致で比較します
+// 圧縮済みの形式と、Server-Sent Events のように少しずつ送るストリームは圧縮しません
+var incompressibleTypes = []string{
+	"image/", "video/", "audio/", "font/woff", "font/woff2",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/pdf",
+	"application/octet-stream", "text/event-stream",
+}
+
+// Compressor は Accept-Encoding に合わせてレスポンスを gzip か deflate で圧縮します
+type Compressor struct {
+	encodings []string
+	minSize   int
+}
+
+// compressWriter はレスポンスの圧縮に使うライター (*gzip.Writer か *zlib.Writer) です
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// NewCompressor は設定の COMPRESSION_* から Compressor を生成します
+// COMPRESSION_ENCODINGS が空の場合は圧縮しません
+func NewCompressor(cfg *config.Config) (*Compressor, error) {
+	c := &Compressor{minSize: max(cfg.CompressionMinSize, 0)}
+	for _, e := range cfg.CompressionEncodings {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if _, ok := compressionEncodings[e]; !ok {
+			return nil, fmt.Errorf("invalid COMPRESSION_ENCODINGS: unsupported encoding %q", e)
+		}
+		c.encodings = append(c.encodings, e)
+	}
+	return c, nil
+}
+
+// Handler はレスポンスを圧縮するハンドラーです。ServeMux 全体を包みます
+// ボディが COMPRESSION_MIN_SIZE バイトになるまでバッファし、それ未満で終わったレスポンスはそのまま返します
+// Content-Encoding を設定済みのレスポンスと、圧縮済みの形式のレスポンスは圧縮しません
+func (c *Compressor) Handler(next http.Handler) http.Handler {
+	if c == nil || len(c.encodings) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := c.negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, minSize: c.minSize}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate は Accept-Encoding から使う圧縮の形式を返します。圧縮しない場合は空文字列です
+// 品質 (q) が同じ場合は COMPRESSION_ENCODINGS の順に優先します
+func (c *Compressor) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	best, bestQ := "", 0.0
+	for _, e := range c.encodings {
+		q, specificity := 0.0, -1
+		for _, part := range strings.Split(acceptEncoding, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "x-gzip" {
+				coding = "gzip"
+			}
+			s := -1
+			switch coding {
+			case e:
+				s = 1
+			case "*":
+				s = 0
+			}
+			if s > specificity {
+				specificity, q = s, encodingQuality(params)
+			}
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// encodingQuality は Accept-Encoding のパラメーターから q の値を返します。指定がない場合は1です
+func encodingQuality(params string) float64 {
+	key, value, _ := strings.Cut(strings.TrimSpace(params), "=")
+	if !strings.EqualFold(key, "q") {
+		return 1
+	}
+	q, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return q
+}
+
+// compressResponseWriter はボディが minSize バイトになった時点で圧縮するかを決める http.ResponseWriter です
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool
+	// started は下位の ResponseWriter にヘッダーを書き込んだ後に true になります
+	started bool
+	buf     []byte
+	enc     compressWriter
+}
+
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	if code < http.StatusOK {
+		// 1xx はそのまま送る
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	if !cw.compressible() {
+		cw.start(false)
+	}
+}
+
+func (cw *compressResponseWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.started {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.start(cw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush はバッファしたボディを送ります。ボディが minSize バイト未満の場合は圧縮しません
+func (cw *compressResponseWriter) Flush() {
+	if cw.wroteHeader && !cw.started {
+		_ = cw.start(len(cw.buf) >= cw.minSize && cw.compressible())
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap は http.ResponseController が下位の ResponseWriter を使えるようにします
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible はヘッダーとステータスから圧縮できるレスポンスかを判定します
+func (cw *compressResponseWriter) compressible() bool {
+	h := cw.Header()
+	if cw.status == http.StatusNoContent || cw.status == http.StatusNotModified || cw.status == http.StatusPartialContent {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		if len(cw.buf) == 0 {
+			// ボディを見るまで判定できない
+			return true
+		}
+		contentType = http.DetectContentType(cw.buf)
+	}
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if mediaType == "image/svg+xml" {
+		return true
+	}
+	for _, t := range incompressibleTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return false
+		}
+	}
+	return true
+}
+
+// start はヘッダーを書き込み、バッファしたボディを送ります
+func (cw *compressResponseWriter) start(compress bool) error {
+	cw.started = true
+	h := cw.Header()
+	if compress {
+		if h.Get("Content-Type") == "" {
+			// 圧縮後のボディから判定されないように、圧縮前のボディで判定しておく
+			h.Set("Content-Type", http.DetectContentType(cw.buf))
+		}
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		// 圧縮後のボディは元のボディとバイト単位で一致しないため弱いETagにする
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.enc = compressionEncodings[cw.encoding].Get().(compressWriter)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close はハンドラーが終わった後に残りのボディを送り、圧縮を終えます
+func (cw *compressResponseWriter) close() {
+	if !cw.wroteHeader {
+		// ハンドラーが何も書き込まなかった場合は http.Server に任せる
+		return
+	}
+	if !cw.started {
+		_ = cw.start(len(cw.buf) >= cw.minSize && cw.compressible())
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		compressionEncodings[cw.encoding].Put(cw.enc)
+		cw.enc = nil
+	}
+}
+
+// Csrf はクロスオリジンからの状態を変更するリクエストを拒否するミドルウェアです
+// CORS で許可したオリジンのリクエストは拒否しません
 func Csrf(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if corsAllowed(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		cop := http.NewCrossOriginProtection()
 		if err := cop.Check(r); err != nil {
-			http.Error(w, err.Error(), http.StatusForbidden)
+			response.Error(r.Context(), w, Errors.WrapWithMessage(r.Context(), Errors.ErrUnauthorized, err.Error()))
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
 }
 
-// dbMiddleware はリクエストごとにDBセッションをcontextに設定するミドルウェア
-func DBSetUp(next http.HandlerFunc) http.HandlerFunc {
+// DBSetUp は起動時に作成した共有のコネクションプールとレプリカをリクエストのcontextに設定するミドルウェア
+// リクエストごとに接続は作成せず、プールが接続を再利用します
+func DBSetUp(db *sql.DB, replicas Ctx.ReplicaPicker, next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		db, err := mysql.Connect(ctx)
-		if err != nil {
-			logger.Error(ctx, "Failed to connect to database", "error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		if db == nil {
+			response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrSystem, i18n.T(ctx, i18n.MsgDBConnectionNotFound)))
 			return
 		}
-		defer db.Close()
 
 		// DBセッションをcontextに設定
 		ctx = Ctx.SetDB(ctx, db)
+		if replicas != nil {
+			ctx = Ctx.SetReplicas(ctx, replicas)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func UseMiddleware(ctx context.Context, handler http.HandlerFunc) http.HandlerFunc {
-	handler = WithTimeout(handler)
-	handler = DBSetUp(handler)
+const (
+	// ReadYourWritesCookie は書き込み後にプライマリから読み取る期間を示すCookieです
+	ReadYourWritesCookie = "read_your_writes"
+	// ReadYourWritesHeader はCookieを使えないクライアントがプライマリからの読み取りを指定するヘッダーです
+	ReadYourWritesHeader = "X-Read-Your-Writes"
+)
+
+// ReadYourWrites は書き込んだ内容をレプリケーションの遅延に関わらず読めるようにするミドルウェア
+// 書き込みのリクエストでは読み取りもプライマリで行い、window の間有効なCookieを返します
+// Cookieか X-Read-Your-Writes: true ヘッダーがあるリクエストはプライマリから読み取ります
+// 期間は DB_READ_YOUR_WRITES_WINDOW で設定します。Cookieの Domain は COOKIE_DOMAIN を指定した場合だけ設定します
+// レプリカを使う場合だけ適用します
+func ReadYourWrites(cfg *config.Config, next http.HandlerFunc) http.HandlerFunc {
+	window := cfg.DBReadYourWritesWindow
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if _, err := r.Cookie(ReadYourWritesCookie); err == nil || r.Header.Get(ReadYourWritesHeader) == "true" {
+				ctx = Ctx.SetReadFromPrimary(ctx)
+			}
+		default:
+			ctx = Ctx.SetReadFromPrimary(ctx)
+			if window > 0 {
+				http.SetCookie(w, &http.Cookie{
+					Name:     ReadYourWritesCookie,
+					Value:    "1",
+					Path:     "/",
+					Domain:   cfg.COOKIE_DOMAIN,
+					MaxAge:   int(window.Seconds()),
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ルートのグループ。グループごとに RATE_LIMIT_* の上限を適用します
+const (
+	RateLimitGroupDefault = "default"
+	RateLimitGroupList    = "list"
+	RateLimitGroupWrite   = "write"
+)
+
+// レート制限の結果を返すヘッダー (draft-ietf-httpapi-ratelimit-headers)
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// RateLimiter はルートのグループごとの上限でクライアントごとのリクエスト数を制限します
+type RateLimiter struct {
+	store   ratelimit.Store
+	limits  map[string]ratelimit.Limit
+	trusted []netip.Prefix
+}
+
+// NewRateLimiter は設定のグループごとの上限と信頼するプロキシで RateLimiter を生成します
+func NewRateLimiter(cfg *config.Config, store ratelimit.Store) (*RateLimiter, error) {
+	l := &RateLimiter{store: store, limits: map[string]ratelimit.Limit{}}
+
+	for group, value := range map[string]string{
+		RateLimitGroupDefault: cfg.RateLimitDefault,
+		RateLimitGroupList:    cfg.RateLimitList,
+		RateLimitGroupWrite:   cfg.RateLimitWrite,
+	} {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s group: %w", group, err)
+		}
+		l.limits[group] = limit
+	}
+
+	for _, v := range cfg.TrustedProxies {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(v)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: %w", v, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		l.trusted = append(l.trusted, prefix.Masked())
+	}
+	return l, nil
+}
+
+// Limit は group の上限を超えたリクエストを429で拒否するミドルウェアです
+// クライアントは認証済みのユーザー、なければ接続元のIPアドレスで区別します
+// RateLimit-* ヘッダーで上限と残りの回数を返し、拒否する場合は Retry-After で次にリクエストできるまでの秒数を返します
+// l が nil かグループの上限がない場合は制限しません。ストアのエラーでは制限せずに処理を続けます
+func (l *RateLimiter) Limit(group string, next http.HandlerFunc) http.HandlerFunc {
+	if l == nil || l.limits[group].IsZero() {
+		return next
+	}
+	limit := l.limits[group]
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		result, err := l.store.Take(ctx, group+":"+l.clientKey(r), limit)
+		if err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to take rate limit token: %v", err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		w.Header().Set(RateLimitResetHeader, ratelimit.Seconds(result.Reset))
+		w.Header().Set(RateLimitPolicyHeader, limit.Policy())
+		if !result.Allowed {
+			w.Header().Set("Retry-After", ratelimit.Seconds(result.RetryAfter))
+			response.Error(ctx, w, Errors.ErrTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey はリクエストのクライアントを区別するキーを返します
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if userID := Ctx.GetCtxFromUser(r.Context()); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+// clientIP は接続元のIPアドレスを返します
+// 接続元が信頼するプロキシの場合は X-Forwarded-For を右から辿り、信頼するプロキシでない最初のアドレスを返します
+// クライアントが送った X-Forwarded-For の左側の値は偽装できるため、信頼するプロキシが追加した値だけを使います
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !l.isTrusted(addr) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		addr = hop.Unmap()
+		if !l.isTrusted(addr) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func (l *RateLimiter) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range l.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteOption はルートごとに共通のミドルウェアの設定を変更します
+type RouteOption func(cfg *config.Config, o *routeOptions)
+
+type routeOptions struct {
+	timeout      time.Duration
+	maxBodyBytes int64
+}
+
+// RouteTimeout はルートの処理時間の上限を REQUEST_TIMEOUT から d に変更します。0 の場合は制限しません
+func RouteTimeout(d time.Duration) RouteOption {
+	return func(_ *config.Config, o *routeOptions) { o.timeout = d }
+}
+
+// LongRunning はインポートやエクスポートのような時間のかかるルートに REQUEST_TIMEOUT_LONG を使います
+func LongRunning() RouteOption {
+	return func(cfg *config.Config, o *routeOptions) { o.timeout = cfg.RequestTimeoutLong }
+}
+
+// MaxBodyBytes はルートのリクエストボディの上限を REQUEST_MAX_BODY_BYTES から n バイトに変更します
+// ファイルのアップロードやインポートのような大きなボディを受け付けるルートに使います
+func MaxBodyBytes(n int64) RouteOption {
+	return func(_ *config.Config, o *routeOptions) { o.maxBodyBytes = n }
+}
+
+// UseMiddleware は共通のミドルウェアを適用します
+// MySQLとSQLiteの場合、ctx には起動時に作成したコネクションプールが設定されている必要があります
+// レプリカが設定されている場合は読み取りクエリをレプリカに振り分けます
+// 処理時間は REQUEST_TIMEOUT、リクエストボディは REQUEST_MAX_BODY_BYTES までに制限し、opts でルートごとに変更できます
+func UseMiddleware(ctx context.Context, handler http.HandlerFunc, opts ...RouteOption) http.HandlerFunc {
+	cfg := Ctx.GetCtxCfg(ctx)
+	o := routeOptions{timeout: cfg.RequestTimeout, maxBodyBytes: int64(cfg.RequestMaxBodyBytes)}
+	for _, opt := range opts {
+		opt(cfg, &o)
+	}
+	return useCommonMiddleware(ctx, WithTimeout(o.timeout, LimitBody(o.maxBodyBytes, handler)))
+}
+
+// UseStreamMiddleware は WithTimeout を除いた共通のミドルウェアを適用します
+// Server-Sent Events のようにレスポンスを返し続けるハンドラーに使います
+// ハンドラーはクライアントの切断 (リクエストのcontextのキャンセル) で終了する必要があります
+func UseStreamMiddleware(ctx context.Context, handler http.HandlerFunc) http.HandlerFunc {
+	return useCommonMiddleware(ctx, handler)
+}
+
+func useCommonMiddleware(ctx context.Context, handler http.HandlerFunc) http.HandlerFunc {
+	if cfg := Ctx.GetCtxCfg(ctx); cfg.DatabaseDriver() != config.DBDriverMemory {
+		// レプリカがない場合は常にプライマリから読み取るため、Cookieを発行しない
+		replicas := Ctx.GetReplicas(ctx)
+		if replicas != nil {
+			handler = ReadYourWrites(cfg, handler)
+		}
+		handler = DBSetUp(Ctx.GetPool(ctx), replicas, handler)
+	}
 	handler = RequestLogger(handler)
 	handler = Csrf(handler)
-	handler = Cors(handler)
+	handler = Language(handler)
+	handler = Tracing(handler)
+	handler = Metrics(handler)
 	handler = AddID(ctx, handler)
 	handler = Logger(handler)
 
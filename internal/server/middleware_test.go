@@ -0,0 +1,990 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/internal/handler/request"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	"github.com/o-ga09/web-ya-hime/pkg/metrics"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddID(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantID  string
+	}{
+		{
+			name:    "成功ケース: X-Request-ID を使う",
+			headers: map[string]string{RequestIDHeader: "req-123"},
+			wantID:  "req-123",
+		},
+		{
+			name:    "成功ケース: X-Cloud-Trace-Context のトレースIDを使う",
+			headers: map[string]string{CloudTraceContextHeader: "105445aa7843bc8bf206b12000100000/1;o=1"},
+			wantID:  "105445aa7843bc8bf206b12000100000",
+		},
+		{
+			name: "成功ケース: X-Request-ID を X-Cloud-Trace-Context より優先する",
+			headers: map[string]string{
+				RequestIDHeader:         "req-123",
+				CloudTraceContextHeader: "105445aa7843bc8bf206b12000100000/1;o=1",
+			},
+			wantID: "req-123",
+		},
+		{
+			name:    "成功ケース: 空白を含むIDは使わずに生成する",
+			headers: map[string]string{RequestIDHeader: "req 123"},
+		},
+		{
+			name:    "成功ケース: 長すぎるIDは使わずに生成する",
+			headers: map[string]string{RequestIDHeader: strings.Repeat("a", maxRequestIDLength+1)},
+		},
+		{
+			name: "成功ケース: ヘッダーがない場合は生成する",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Env: "test"}
+			var (
+				gotID  string
+				gotCfg *config.Config
+			)
+			handler := AddID(Ctx.SetCtxCfg(context.Background(), cfg), func(w http.ResponseWriter, r *http.Request) {
+				gotID = Ctx.GetRequestID(r.Context())
+				gotCfg = Ctx.GetCtxCfg(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, gotID)
+			} else {
+				assert.Len(t, gotID, 36)
+			}
+			assert.Equal(t, gotID, w.Header().Get(RequestIDHeader))
+			assert.Same(t, cfg, gotCfg)
+		})
+	}
+}
+
+func TestAddID_RequestContext(t *testing.T) {
+	t.Run("成功ケース: クライアントの切断でcontextがキャンセルされる", func(t *testing.T) {
+		handler := AddID(context.Background(), func(w http.ResponseWriter, r *http.Request) {
+			assert.Error(t, r.Context().Err())
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "/health", nil).WithContext(ctx)
+
+		handler(httptest.NewRecorder(), req)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantBody   string
+		wantHeader string
+	}{
+		{
+			name: "成功ケース: 期限までに終わったレスポンスをそのまま返す",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Handler", "done")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte("created"))
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   "created",
+			wantHeader: "done",
+		},
+		{
+			name: "成功ケース: ステータスを書かない場合は200を返す",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name: "失敗ケース: 期限を過ぎると503を返し、ハンドラーの書き込みを捨てる",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `"code":"timeout"`,
+		},
+		{
+			name: "失敗ケース: 呼び出し先の期限切れは504を返す",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				response.Error(r.Context(), w, fmt.Errorf("failed to get summary: %w", context.DeadlineExceeded))
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   `"code":"gateway_timeout"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set(RequestIDHeader, "req-1")
+			WithTimeout(20*time.Millisecond, tt.handler)(w, httptest.NewRequest(http.MethodGet, "/summaries", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantHeader, w.Header().Get("X-Handler"))
+			// 外側のミドルウェアが設定したヘッダーは残す
+			assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+		})
+	}
+}
+
+func TestWithTimeout_WriteAfterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	result := make(chan error, 2)
+	handler := WithTimeout(10*time.Millisecond, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		// ハンドラーには期限付きのcontextが渡される
+		result <- r.Context().Err()
+		<-release
+		w.Header().Set("X-Handler", "late")
+		_, err := w.Write([]byte("late"))
+		result <- err
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/summaries", nil))
+	close(release)
+
+	assert.ErrorIs(t, <-result, context.DeadlineExceeded)
+	assert.ErrorIs(t, <-result, http.ErrHandlerTimeout)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Header().Get("X-Handler"))
+	assert.NotContains(t, w.Body.String(), "late")
+}
+
+func TestWithTimeout_Panic(t *testing.T) {
+	handler := WithTimeout(time.Second, func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() {
+		defer func() {
+			// パニックの値にはハンドラーのスタックトレースを付ける
+			if p := recover(); p != nil {
+				msg, _, _ := strings.Cut(p.(string), "\n")
+				panic(msg)
+			}
+		}()
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+// TestWithTimeout_Concurrent は期限の前後で終わるリクエストを並行して処理します
+// go test -race でレスポンスの書き込みが競合しないことを確認します
+func TestWithTimeout_Concurrent(t *testing.T) {
+	handler := WithTimeout(5*time.Millisecond, func(w http.ResponseWriter, r *http.Request) {
+		delay, _ := time.ParseDuration(r.URL.Query().Get("delay"))
+		for range 10 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(delay / 10):
+			}
+			w.Header().Set("X-Progress", "1")
+			_, _ = w.Write([]byte("chunk"))
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			delay := time.Duration(i%10) * time.Millisecond
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/summaries?delay="+delay.String(), nil))
+			switch w.Code {
+			case http.StatusOK:
+				assert.Equal(t, strings.Repeat("chunk", 10), w.Body.String())
+			case http.StatusServiceUnavailable:
+				assert.Contains(t, w.Body.String(), `"code":"timeout"`)
+				assert.NotContains(t, w.Body.String(), "chunk")
+			default:
+				t.Errorf("unexpected status %d", w.Code)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestUseMiddleware_RouteTimeout(t *testing.T) {
+	ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{
+		DBDriver:           config.DBDriverMemory,
+		RequestTimeout:     10 * time.Millisecond,
+		RequestTimeoutLong: time.Second,
+	})
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(50 * time.Millisecond):
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		opts       []RouteOption
+		wantStatus int
+	}{
+		{name: "失敗ケース: REQUEST_TIMEOUT を過ぎると503を返す", wantStatus: http.StatusServiceUnavailable},
+		{name: "成功ケース: 時間のかかるルートは REQUEST_TIMEOUT_LONG を使う", opts: []RouteOption{LongRunning()}, wantStatus: http.StatusNoContent},
+		{name: "成功ケース: 0を指定すると制限しない", opts: []RouteOption{RouteTimeout(0)}, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			UseMiddleware(ctx, slow, tt.opts...)(w, httptest.NewRequest(http.MethodGet, "/summaries", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	handler := LimitBody(16, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := request.Bind(r, &req); err != nil {
+			response.BindError(r.Context(), w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{name: "成功ケース: 上限以内のボディ", body: `{"name":"taro"}`, contentLength: 15, wantStatus: http.StatusNoContent},
+		{name: "失敗ケース: Content-Length が上限を超える場合は読まずに413を返す", body: `{"name":"taro-jiro"}`, contentLength: 20, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "失敗ケース: 長さが不明なボディは読み込みが上限を超えた時点で413を返す", body: `{"name":"taro-jiro"}`, contentLength: -1, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.ContentLength = tt.contentLength
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, w.Body.String(), `"code":"request_too_large"`)
+			}
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		status      int
+		wantTraceID string
+	}{
+		{
+			name:        "成功ケース: traceparent のトレースを引き継ぐ",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			status:      http.StatusOK,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:   "成功ケース: ヘッダーがない場合は新しいトレースを開始する",
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sc Trace.SpanContext
+			handler := Tracing(func(w http.ResponseWriter, r *http.Request) {
+				sc = Trace.SpanContextFromContext(r.Context())
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/summaries/1", nil)
+			if tt.traceparent != "" {
+				req.Header.Set(Trace.TraceparentHeader, tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			assert.True(t, sc.IsValid())
+			if tt.wantTraceID != "" {
+				assert.Equal(t, tt.wantTraceID, sc.TraceID.String())
+			}
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		path      string
+		status    int
+		wantRoute string
+	}{
+		{
+			name:      "成功ケース: パスではなくルーティングのパターンで記録する",
+			pattern:   "GET /metrics-test/{id}",
+			path:      "/metrics-test/123",
+			status:    http.StatusOK,
+			wantRoute: "GET /metrics-test/{id}",
+		},
+		{
+			name:      "成功ケース: メソッドのないパターンはメソッドを付けて記録する",
+			pattern:   "/metrics-test-any",
+			path:      "/metrics-test-any",
+			status:    http.StatusNotFound,
+			wantRoute: "GET /metrics-test-any",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, Metrics(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			body := string(metrics.Default.Gather())
+			labels := fmt.Sprintf(`{route="%s",status="%d"}`, tt.wantRoute, tt.status)
+			assert.Contains(t, body, "http_requests_total"+labels+" 1\n")
+			assert.Contains(t, body, "http_request_duration_seconds_count"+labels+" 1\n")
+			assert.Contains(t, body, "http_requests_in_flight 0\n")
+		})
+	}
+}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	cfg := &config.Config{RateLimitList: "2/1m"}
+	limiter, err := NewRateLimiter(cfg, ratelimit.NewMemoryStore())
+	assert.NoError(t, err)
+
+	called := 0
+	handler := limiter.Limit(RateLimitGroupList, func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/summaries", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	t.Run("成功ケース: 上限までは残りの回数を返して処理する", func(t *testing.T) {
+		w := request("192.0.2.1:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "30", w.Header().Get(RateLimitResetHeader))
+		assert.Equal(t, "2;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+		w = request("192.0.2.1:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+	})
+
+	t.Run("失敗ケース: 上限を超えると429と Retry-After を返す", func(t *testing.T) {
+		w := request("192.0.2.1:5678")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"too_many_requests"`)
+		assert.Equal(t, 2, called)
+	})
+
+	t.Run("成功ケース: 他のクライアントは制限しない", func(t *testing.T) {
+		w := request("192.0.2.2:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("成功ケース: 上限のないグループは制限しない", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		limiter.Limit(RateLimitGroupWrite, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})(w, httptest.NewRequest(http.MethodPost, "/summaries", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	})
+}
+
+func TestRateLimiter_clientKey(t *testing.T) {
+	limiter, err := NewRateLimiter(&config.Config{TrustedProxies: []string{"10.0.0.0/8", "192.0.2.10"}}, ratelimit.NewMemoryStore())
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		userID       string
+		want         string
+	}{
+		{
+			name:       "成功ケース: 接続元のIPアドレス",
+			remoteAddr: "198.51.100.1:1234",
+			want:       "ip:198.51.100.1",
+		},
+		{
+			name:         "成功ケース: 信頼しない接続元の X-Forwarded-For は使わない",
+			remoteAddr:   "198.51.100.1:1234",
+			forwardedFor: []string{"203.0.113.1"},
+			want:         "ip:198.51.100.1",
+		},
+		{
+			name:         "成功ケース: 信頼するプロキシを右から辿って最初のクライアントを使う",
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: []string{"203.0.113.99, 203.0.113.1", "192.0.2.10"},
+			want:         "ip:203.0.113.1",
+		},
+		{
+			name:         "成功ケース: すべて信頼するプロキシの場合は一番左のアドレスを使う",
+			remoteAddr:   "10.0.0.2:1234",
+			forwardedFor: []string{"10.0.0.3, 10.0.0.4"},
+			want:         "ip:10.0.0.3",
+		},
+		{
+			name:       "成功ケース: 認証済みのユーザーはユーザーIDで区別する",
+			remoteAddr: "198.51.100.1:1234",
+			userID:     "user-1",
+			want:       "user:user-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/summaries", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.userID != "" {
+				req = req.WithContext(Ctx.SetCtxFromUser(req.Context(), tt.userID))
+			}
+
+			assert.Equal(t, tt.want, limiter.clientKey(req))
+		})
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{name: "失敗ケース: 上限の形式が不正", cfg: &config.Config{RateLimitList: "120"}},
+		{name: "失敗ケース: 信頼するプロキシのアドレスが不正", cfg: &config.Config{TrustedProxies: []string{"proxy.local"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRateLimiter(tt.cfg, ratelimit.NewMemoryStore())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCORS_Handler(t *testing.T) {
+	cors, err := NewCORS(&config.Config{
+		CORSAllowedOrigins:   []string{"https://viewer.example.com", "https://*.preview.example.com"},
+		CORSAllowCredentials: true,
+		CORSExposedHeaders:   []string{"X-Total-Count"},
+		CORSMaxAge:           10 * time.Minute,
+		CORSRouteOrigins:     []string{"/webhooks=https://admin.example.com"},
+	})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /summaries", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /summaries", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := cors.Handler(mux)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		origin          string
+		requestMethod   string
+		wantStatus      int
+		wantAllowOrigin string
+		wantMethods     string
+	}{
+		{
+			name:            "成功ケース: 許可したオリジンのリクエストにオリジンを返す",
+			method:          http.MethodGet,
+			path:            "/summaries",
+			origin:          "https://viewer.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://viewer.example.com",
+		},
+		{
+			name:            "成功ケース: ワイルドカードのサブドメインを許可する",
+			method:          http.MethodGet,
+			path:            "/summaries",
+			origin:          "https://pr-12.preview.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://pr-12.preview.example.com",
+		},
+		{
+			name:       "成功ケース: 許可していないオリジンにはヘッダーを付けない",
+			method:     http.MethodGet,
+			path:       "/summaries",
+			origin:     "https://preview.example.com",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:            "成功ケース: プリフライトに登録されているメソッドを返す",
+			method:          http.MethodOptions,
+			path:            "/summaries",
+			origin:          "https://viewer.example.com",
+			requestMethod:   http.MethodPost,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://viewer.example.com",
+			wantMethods:     "GET, HEAD, POST",
+		},
+		{
+			name:            "成功ケース: ルートごとに許可するオリジンを上書きする",
+			method:          http.MethodOptions,
+			path:            "/webhooks/1",
+			origin:          "https://admin.example.com",
+			requestMethod:   http.MethodGet,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://admin.example.com",
+			wantMethods:     "GET, HEAD",
+		},
+		{
+			name:          "失敗ケース: 上書きしたルートでは既定のオリジンを許可しない",
+			method:        http.MethodOptions,
+			path:          "/webhooks/1",
+			origin:        "https://viewer.example.com",
+			requestMethod: http.MethodGet,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "失敗ケース: 登録されていないメソッドのプリフライトは405",
+			method:        http.MethodOptions,
+			path:          "/summaries",
+			origin:        "https://viewer.example.com",
+			requestMethod: http.MethodDelete,
+			wantStatus:    http.StatusMethodNotAllowed,
+		},
+		{
+			name:          "失敗ケース: 登録されていないパスのプリフライトは404",
+			method:        http.MethodOptions,
+			path:          "/unknown",
+			origin:        "https://viewer.example.com",
+			requestMethod: http.MethodGet,
+			wantStatus:    http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantAllowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantMethods, w.Header().Get("Access-Control-Allow-Methods"))
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+			if tt.wantAllowOrigin == "" {
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+				return
+			}
+			assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+			if tt.method == http.MethodOptions {
+				assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+			} else {
+				assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Total-Count")
+			}
+		})
+	}
+}
+
+func TestCORS_Wildcard(t *testing.T) {
+	cors, err := NewCORS(&config.Config{CORSAllowedOrigins: []string{"*"}})
+	assert.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /summaries", func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest(http.MethodGet, "/summaries", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	w := httptest.NewRecorder()
+
+	cors.Handler(mux).ServeHTTP(w, req)
+
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestNewCORS(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{
+			name: "失敗ケース: 認証情報を送信させる場合にすべてのオリジンは許可できない",
+			cfg:  &config.Config{CORSAllowedOrigins: []string{"*"}, CORSAllowCredentials: true},
+		},
+		{
+			name: "失敗ケース: スキームのないオリジン",
+			cfg:  &config.Config{CORSAllowedOrigins: []string{"example.com"}},
+		},
+		{
+			name: "失敗ケース: ホストの途中のワイルドカード",
+			cfg:  &config.Config{CORSAllowedOrigins: []string{"https://api.*.example.com"}},
+		},
+		{
+			name: "失敗ケース: ルートの上書きの形式が不正",
+			cfg:  &config.Config{CORSRouteOrigins: []string{"webhooks:https://admin.example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCORS(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompressor_Handler(t *testing.T) {
+	c, err := NewCompressor(&config.Config{CompressionEncodings: []string{"gzip", "deflate"}, CompressionMinSize: 100})
+	assert.NoError(t, err)
+	large := strings.Repeat(`{"title":"概要欄"}`, 50)
+
+	decode := func(t *testing.T, encoding string, body []byte) string {
+		var r io.Reader = bytes.NewReader(body)
+		switch encoding {
+		case "gzip":
+			gr, err := gzip.NewReader(r)
+			assert.NoError(t, err)
+			r = gr
+		case "deflate":
+			zr, err := zlib.NewReader(r)
+			assert.NoError(t, err)
+			r = zr
+		}
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		method         string
+		handler        http.HandlerFunc
+		wantEncoding   string
+		wantETag       string
+		wantBody       string
+	}{
+		{
+			name:           "成功ケース: gzip で圧縮し、ETag を弱いETagにする",
+			acceptEncoding: "gzip, deflate, br",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Length", strconv.Itoa(len(large)))
+				w.Header().Set("ETag", `"abc"`)
+				_, _ = w.Write([]byte(large))
+			},
+			wantEncoding: "gzip",
+			wantETag:     `W/"abc"`,
+			wantBody:     large,
+		},
+		{
+			name:           "成功ケース: 品質の高い deflate を選ぶ",
+			acceptEncoding: "gzip;q=0.5, deflate",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				// 少しずつ書き込んでも最小サイズを超えた時点で圧縮する
+				for i := 0; i < len(large); i += 10 {
+					_, _ = w.Write([]byte(large[i:min(i+10, len(large))]))
+				}
+			},
+			wantEncoding: "deflate",
+			wantBody:     large,
+		},
+		{
+			name:           "成功ケース: 最小サイズ未満は圧縮しない",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"message":"OK"}`))
+			},
+			wantBody: `{"message":"OK"}`,
+		},
+		{
+			name:           "成功ケース: 圧縮済みの形式は圧縮しない",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(large))
+			},
+			wantBody: large,
+		},
+		{
+			name:           "成功ケース: Content-Encoding を設定済みのレスポンスは圧縮しない",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				_, _ = w.Write([]byte(large))
+			},
+			wantEncoding: "br",
+			wantBody:     large,
+		},
+		{
+			name:           "成功ケース: gzip を拒否した場合は圧縮しない",
+			acceptEncoding: "gzip;q=0, deflate;q=0",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(large))
+			},
+			wantBody: large,
+		},
+		{
+			name: "成功ケース: Accept-Encoding がない場合は圧縮しない",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(large))
+			},
+			wantBody: large,
+		},
+		{
+			name:           "成功ケース: ボディのないレスポンス",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/summaries", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			c.Handler(tt.handler).ServeHTTP(w, req)
+
+			encoding := w.Header().Get("Content-Encoding")
+			assert.Equal(t, tt.wantEncoding, encoding)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+			if tt.wantEncoding == "gzip" || tt.wantEncoding == "deflate" {
+				assert.Empty(t, w.Header().Get("Content-Length"))
+				assert.Less(t, w.Body.Len(), len(tt.wantBody))
+			}
+			assert.Equal(t, tt.wantBody, decode(t, encoding, w.Body.Bytes()))
+		})
+	}
+}
+
+func TestCompressor_Handler_Stream(t *testing.T) {
+	c, err := NewCompressor(&config.Config{CompressionEncodings: []string{"gzip"}, CompressionMinSize: 1024})
+	assert.NoError(t, err)
+
+	handler := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/events/summaries", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// Server-Sent Events は圧縮せずにすぐに送る
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "data: 1\n\n", w.Body.String())
+}
+
+func TestNewCompressor(t *testing.T) {
+	t.Run("成功ケース: 空の場合は圧縮しない", func(t *testing.T) {
+		c, err := NewCompressor(&config.Config{})
+		assert.NoError(t, err)
+		next := http.NotFoundHandler()
+		handler := c.Handler(next)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+
+	t.Run("失敗ケース: 対応していない形式", func(t *testing.T) {
+		_, err := NewCompressor(&config.Config{CompressionEncodings: []string{"gzip", "br"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestCsrf(t *testing.T) {
+	handler := Csrf(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	t.Run("失敗ケース: クロスオリジンの書き込みを拒否する", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/summaries", nil)
+		req.Header.Set("Sec-Fetch-Site", "cross-site")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("成功ケース: CORS で許可したオリジンの書き込みは拒否しない", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/summaries", nil)
+		req.Header.Set("Sec-Fetch-Site", "cross-site")
+		req = req.WithContext(context.WithValue(req.Context(), corsAllowedKey{}, true))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+// stubReplicas はレプリカを返さない Ctx.ReplicaPicker です
+type stubReplicas struct{}
+
+func (stubReplicas) Pick() *sql.DB { return nil }
+
+func TestReadYourWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		cookie      bool
+		header      bool
+		domain      string
+		wantPrimary bool
+		wantCookie  bool
+	}{
+		{name: "成功ケース: 書き込みはプライマリから読み取り、Cookieを返す", method: http.MethodPost, wantPrimary: true, wantCookie: true},
+		{name: "成功ケース: COOKIE_DOMAIN を指定するとCookieの Domain に設定する", method: http.MethodPost, domain: "example.com", wantPrimary: true, wantCookie: true},
+		{name: "成功ケース: Cookieがある読み取りはプライマリから読み取る", method: http.MethodGet, cookie: true, wantPrimary: true},
+		{name: "成功ケース: ヘッダーがある読み取りはプライマリから読み取る", method: http.MethodGet, header: true, wantPrimary: true},
+		{name: "成功ケース: Cookieもヘッダーもない読み取りはレプリカから読み取る", method: http.MethodGet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primary bool
+			handler := ReadYourWrites(&config.Config{DBReadYourWritesWindow: 5 * time.Second, COOKIE_DOMAIN: tt.domain}, func(w http.ResponseWriter, r *http.Request) {
+				primary = Ctx.IsReadFromPrimary(r.Context())
+			})
+
+			req := httptest.NewRequest(tt.method, "/summaries", nil)
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: ReadYourWritesCookie, Value: "1"})
+			}
+			if tt.header {
+				req.Header.Set(ReadYourWritesHeader, "true")
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.wantPrimary, primary)
+			cookies := w.Result().Cookies()
+			if !tt.wantCookie {
+				assert.Empty(t, cookies)
+				return
+			}
+			if len(cookies) != 1 {
+				t.Fatalf("unexpected cookies: %v", cookies)
+			}
+			assert.Equal(t, ReadYourWritesCookie, cookies[0].Name)
+			assert.Equal(t, 5, cookies[0].MaxAge)
+			assert.Equal(t, tt.domain, cookies[0].Domain)
+		})
+	}
+}
+
+func TestUseMiddleware_ReadYourWrites(t *testing.T) {
+	tests := []struct {
+		name       string
+		replicas   Ctx.ReplicaPicker
+		wantCookie bool
+	}{
+		{name: "成功ケース: レプリカがある場合は書き込み後にCookieを返す", replicas: stubReplicas{}, wantCookie: true},
+		{name: "成功ケース: レプリカがない場合はCookieを返さない"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), config.CtxEnvKey, &config.Config{
+				DBDriver:               config.DBDriverMySQL,
+				DBReadYourWritesWindow: 5 * time.Second,
+			})
+			ctx = Ctx.SetDB(ctx, new(sql.DB))
+			if tt.replicas != nil {
+				ctx = Ctx.SetReplicas(ctx, tt.replicas)
+			}
+
+			w := httptest.NewRecorder()
+			UseMiddleware(ctx, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			})(w, httptest.NewRequest(http.MethodPost, "/summaries", nil))
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, tt.wantCookie, strings.Contains(w.Header().Get("Set-Cookie"), ReadYourWritesCookie))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,14 +10,40 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/o-ga09/web-ya-hime/internal/domain"
+	CategoryDomain "github.com/o-ga09/web-ya-hime/internal/domain/category"
+	"github.com/o-ga09/web-ya-hime/internal/domain/event"
+	SubcategoryDomain "github.com/o-ga09/web-ya-hime/internal/domain/subcategory"
+	SummaryDomain "github.com/o-ga09/web-ya-hime/internal/domain/summary"
+	UserDomain "github.com/o-ga09/web-ya-hime/internal/domain/user"
+	WebhookDomain "github.com/o-ga09/web-ya-hime/internal/domain/webhook"
 	"github.com/o-ga09/web-ya-hime/internal/handler/category"
+	"github.com/o-ga09/web-ya-hime/internal/handler/response"
+	"github.com/o-ga09/web-ya-hime/internal/handler/stream"
 	"github.com/o-ga09/web-ya-hime/internal/handler/subcategory"
 	"github.com/o-ga09/web-ya-hime/internal/handler/summary"
 	"github.com/o-ga09/web-ya-hime/internal/handler/user"
+	"github.com/o-ga09/web-ya-hime/internal/handler/webhook"
+	"github.com/o-ga09/web-ya-hime/internal/infra/cache"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/memory"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/migration"
 	"github.com/o-ga09/web-ya-hime/internal/infra/database/mysql"
+	"github.com/o-ga09/web-ya-hime/internal/infra/database/sqlite"
+	"github.com/o-ga09/web-ya-hime/internal/infra/outbox"
+	"github.com/o-ga09/web-ya-hime/internal/infra/sse"
+	TraceInfra "github.com/o-ga09/web-ya-hime/internal/infra/trace"
+	WebhookInfra "github.com/o-ga09/web-ya-hime/internal/infra/webhook"
+	Cache "github.com/o-ga09/web-ya-hime/pkg/cache"
+	"github.com/o-ga09/web-ya-hime/pkg/config"
 	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+	Errors "github.com/o-ga09/web-ya-hime/pkg/errors"
+	"github.com/o-ga09/web-ya-hime/pkg/health"
 	"github.com/o-ga09/web-ya-hime/pkg/httputil"
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/logger"
+	"github.com/o-ga09/web-ya-hime/pkg/metrics"
+	"github.com/o-ga09/web-ya-hime/pkg/ratelimit"
+	Trace "github.com/o-ga09/web-ya-hime/pkg/trace"
 )
 
 type IServer interface {
@@ -24,41 +51,275 @@ type IServer interface {
 }
 
 type server struct {
+	db          *sql.DB
+	replicas    *mysql.ReplicaSet
+	cache       *Cache.Cache[any]
+	dispatcher  *outbox.Dispatcher
+	sender      *WebhookInfra.Sender
+	broker      *sse.Broker
+	follower    *outbox.Follower
+	tracer      *Trace.Provider
+	limiter     *RateLimiter
+	cors        *CORS
+	compressor  *Compressor
+	readiness   *health.Registry
 	user        user.IUserHandler
 	summary     summary.ISummaryHandler
 	category    category.ICategoryHandler
 	subcategory subcategory.ISubcategoryHandler
+	webhook     webhook.IWebhookHandler
+	stream      stream.IStreamHandler
 }
 
-func NewServer(ctx context.Context) IServer {
-	summaryRepo := mysql.NewSummaryRepository()
-	userRepo := mysql.NewUserRepository()
-	categoryRepo := mysql.NewCategoryRepository()
-	subcategoryRepo := mysql.NewSubcategoryRepository()
-	return &server{
-		user:        user.New(userRepo),
-		summary:     summary.New(summaryRepo, subcategoryRepo),
-		category:    category.New(categoryRepo),
-		subcategory: subcategory.New(subcategoryRepo),
+// NewServer はリポジトリを生成し、すべてのリクエストで共有するサーバーを生成します
+// MySQLとSQLiteの場合はコネクションプールを1つ作成します
+// MySQLでは DATABASE_REPLICA_URLS のレプリカごとにもコネクションプールを作成します
+// CACHE_SIZE が0より大きい場合は一覧と詳細の取得結果をキャッシュするリポジトリで包みます
+// MySQLとSQLiteでは共有のコネクションプールへの疎通と未適用のマイグレーションを /readyz で確認します
+func NewServer(ctx context.Context) (IServer, error) {
+	cfg := Ctx.GetCtxCfg(ctx)
+
+	var (
+		s               = &server{}
+		summaryRepo     SummaryDomain.ISummaryRepository
+		userRepo        UserDomain.IUserRepository
+		categoryRepo    CategoryDomain.ICategoryRepository
+		subcategoryRepo SubcategoryDomain.ISubcategoryRepository
+		outboxRepo      event.IOutboxRepository
+		webhookRepo     WebhookDomain.IWebhookRepository
+		deliveryRepo    WebhookDomain.IDeliveryRepository
+		txManager       domain.ITxManager
+	)
+
+	tracer, err := newTraceProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.tracer = tracer
+
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.limiter = limiter
+
+	cors, err := NewCORS(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.cors = cors
+
+	compressor, err := NewCompressor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.compressor = compressor
+
+	switch cfg.DatabaseDriver() {
+	case config.DBDriverMySQL:
+		db, err := mysql.Connect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		replicas, err := mysql.ConnectReplicas(ctx)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to connect to replicas: %w", err)
+		}
+
+		s.db = db
+		s.replicas = replicas
+		summaryRepo = mysql.NewSummaryRepository()
+		userRepo = mysql.NewUserRepository()
+		categoryRepo = mysql.NewCategoryRepository()
+		subcategoryRepo = mysql.NewSubcategoryRepository()
+		outboxRepo = mysql.NewOutboxRepository()
+		webhookRepo = mysql.NewWebhookRepository()
+		deliveryRepo = mysql.NewWebhookDeliveryRepository()
+		txManager = mysql.NewTxManager()
+	case config.DBDriverSQLite:
+		db, err := sqlite.Connect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		s.db = db
+		summaryRepo = sqlite.NewSummaryRepository()
+		userRepo = sqlite.NewUserRepository()
+		categoryRepo = sqlite.NewCategoryRepository()
+		subcategoryRepo = sqlite.NewSubcategoryRepository()
+		outboxRepo = sqlite.NewOutboxRepository()
+		webhookRepo = sqlite.NewWebhookRepository()
+		deliveryRepo = sqlite.NewWebhookDeliveryRepository()
+		txManager = sqlite.NewTxManager()
+	case config.DBDriverMemory:
+		store := memory.NewStore()
+		summaryRepo = memory.NewSummaryRepository(store)
+		userRepo = memory.NewUserRepository(store)
+		categoryRepo = memory.NewCategoryRepository(store)
+		subcategoryRepo = memory.NewSubcategoryRepository(store)
+		outboxRepo = memory.NewOutboxRepository(store)
+		webhookRepo = memory.NewWebhookRepository(store)
+		deliveryRepo = memory.NewWebhookDeliveryRepository(store)
+		txManager = memory.NewTxManager(store)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER: %q", cfg.DatabaseDriver())
+	}
+
+	// 書き込みと同じトランザクションでドメインイベントをアウトボックスに保存する
+	summaryRepo = outbox.NewSummaryRepository(summaryRepo, outboxRepo, txManager)
+	categoryRepo = outbox.NewCategoryRepository(categoryRepo, outboxRepo, txManager)
+	subcategoryRepo = outbox.NewSubcategoryRepository(subcategoryRepo, outboxRepo, txManager)
+	s.dispatcher = outbox.NewDispatcher(outboxRepo, cfg)
+	s.dispatcher.Subscribe("audit-log", outbox.LogSubscriber)
+	// Webhookへの送信は Sender が配信履歴を通して非同期に行う
+	s.dispatcher.Subscribe("webhook", WebhookInfra.NewFanout(webhookRepo, deliveryRepo))
+	s.sender = WebhookInfra.NewSender(webhookRepo, deliveryRepo, cfg)
+	// サマリーの変更を Server-Sent Events の接続に中継する
+	// 接続はインスタンスごとなので、ディスパッチャーではなくすべてのインスタンスが読み取る Follower から受け取る
+	s.broker = sse.NewBroker(cfg.SSEBufferSize)
+	s.follower = outbox.NewFollower(outboxRepo, cfg, "sse", s.broker.Publish, event.TypeSummaryCreated, event.TypeSummaryUpdated, event.TypeSummaryDeleted, event.TypeSummaryPublished)
+
+	// カテゴリの削除でサマリーのキャッシュも無効化するため、すべてのリポジトリで同じキャッシュを共有する
+	if cfg.CacheSize > 0 {
+		s.cache = Cache.New[any](cfg.CacheSize, cfg.CacheTTL)
+		summaryRepo = cache.NewSummaryRepository(summaryRepo, s.cache)
+		userRepo = cache.NewUserRepository(userRepo, s.cache)
+		categoryRepo = cache.NewCategoryRepository(categoryRepo, s.cache)
+		subcategoryRepo = cache.NewSubcategoryRepository(subcategoryRepo, s.cache)
+	}
+
+	// キャッシュのヒットも含めてリポジトリの呼び出しにかかった時間を記録する
+	summaryRepo = TraceInfra.NewSummaryRepository(summaryRepo)
+	userRepo = TraceInfra.NewUserRepository(userRepo)
+	categoryRepo = TraceInfra.NewCategoryRepository(categoryRepo)
+	subcategoryRepo = TraceInfra.NewSubcategoryRepository(subcategoryRepo)
+
+	s.user = user.New(userRepo)
+	s.summary = summary.New(summaryRepo, subcategoryRepo, txManager)
+	s.category = category.New(categoryRepo)
+	s.subcategory = subcategory.New(subcategoryRepo)
+	s.webhook = webhook.New(TraceInfra.NewWebhookRepository(webhookRepo), TraceInfra.NewDeliveryRepository(deliveryRepo))
+	s.stream = stream.New(s.broker, cfg.SSEHeartbeatInterval)
+
+	// コネクションプールの統計情報と Go ランタイムの統計情報を /metrics で出力する
+	if s.db != nil {
+		dbs := []metrics.NamedDB{{Name: "primary", DB: s.db}}
+		for i, db := range s.replicas.DBs() {
+			dbs = append(dbs, metrics.NamedDB{Name: fmt.Sprintf("replica%d", i), DB: db})
+		}
+		metrics.Default.Register(metrics.NewDBStatsCollector(dbs...))
+	}
+	metrics.Default.Register(metrics.NewRuntimeCollector())
+
+	s.readiness = health.NewRegistry(cfg.HealthCheckTimeout, cfg.HealthCheckCacheTTL)
+	if s.db != nil {
+		s.readiness.Register("database", s.db.PingContext)
+
+		source, err := migration.Source(cfg.DatabaseDriver())
+		if err != nil {
+			return nil, err
+		}
+		checkMigrations, err := migration.NewCheck(s.db, source)
+		if err != nil {
+			return nil, err
+		}
+		s.readiness.Register("migrations", checkMigrations)
 	}
+	return s, nil
 }
 
 func (s *server) Run(ctx context.Context) error {
 	cfg := Ctx.GetCtxCfg(ctx)
 	engine := http.NewServeMux()
 
+	// 共有のコネクションプールを各リクエストのcontextに設定する
+	if s.db != nil {
+		defer s.db.Close()
+		ctx = Ctx.SetDB(ctx, s.db)
+	}
+
+	// 読み取りクエリをレプリカに振り分け、定期的にヘルスチェックを行う
+	if s.replicas != nil {
+		defer s.replicas.Close()
+		ctx = Ctx.SetReplicas(ctx, s.replicas)
+
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		defer stopWatch()
+		go s.replicas.Watch(watchCtx, cfg.DBReplicaHealthCheckInterval)
+	}
+
+	// 終了したスパンを出力する。他の処理が記録したスパンも出力するため最後に止める
+	if s.tracer != nil {
+		Trace.SetProvider(s.tracer)
+		traceCtx, stopTrace := context.WithCancel(ctx)
+		traceDone := make(chan struct{})
+		go func() {
+			defer close(traceDone)
+			s.tracer.Run(traceCtx)
+		}()
+		defer func() {
+			stopTrace()
+			<-traceDone
+			Trace.SetProvider(nil)
+		}()
+	}
+
+	// アウトボックスのイベントを購読者に配信する
+	// コネクションプールを閉じる前に配信を止めて終了を待つ
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+	dispatchDone := make(chan struct{})
+	go func() {
+		defer close(dispatchDone)
+		s.dispatcher.Run(dispatchCtx)
+	}()
+	defer func() {
+		stopDispatch()
+		<-dispatchDone
+	}()
+
+	// アウトボックスのイベントをこのインスタンスの Server-Sent Events の接続に中継する
+	followCtx, stopFollow := context.WithCancel(ctx)
+	followDone := make(chan struct{})
+	go func() {
+		defer close(followDone)
+		s.follower.Run(followCtx)
+	}()
+	defer func() {
+		stopFollow()
+		<-followDone
+	}()
+
+	// 保存された配信をWebhookに送信する
+	sendCtx, stopSend := context.WithCancel(ctx)
+	sendDone := make(chan struct{})
+	go func() {
+		defer close(sendDone)
+		s.sender.Run(sendCtx)
+	}()
+	defer func() {
+		stopSend()
+		<-sendDone
+	}()
+
 	// ヘルスチェックAPI
 	healthCheckHandler := UseMiddleware(ctx, healthCheck)
 	DBHealthCheckHandler := UseMiddleware(ctx, DBHealthCheck)
 
 	engine.HandleFunc("/health", healthCheckHandler)
 	engine.HandleFunc("/db-health", DBHealthCheckHandler)
+	engine.HandleFunc("GET /cache-stats", UseMiddleware(ctx, s.cacheStats))
+	// プローブは数秒ごとに届くため、ログやメトリクスに記録しないようにミドルウェアを適用しない
+	engine.HandleFunc("GET /livez", livez)
+	engine.HandleFunc("GET /readyz", s.readyz)
+	// Prometheus からの収集で自身のリクエストが記録されないようにミドルウェアを適用しない
+	engine.Handle("GET /metrics", metrics.Default.Handler())
 
 	// ユーザーAPI
-	userSaveHandler := UseMiddleware(ctx, s.user.Save)
-	userListHandler := UseMiddleware(ctx, s.user.List)
-	userDetailHandler := UseMiddleware(ctx, s.user.Detail)
-	userDeleteHandler := UseMiddleware(ctx, s.user.Delete)
+	userSaveHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.user.Save))
+	userListHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.user.List))
+	userDetailHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.user.Detail))
+	userDeleteHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.user.Delete))
 
 	engine.HandleFunc("POST /users", userSaveHandler)
 	engine.HandleFunc("PUT /users/{id}", userSaveHandler)
@@ -67,22 +328,24 @@ func (s *server) Run(ctx context.Context) error {
 	engine.HandleFunc("DELETE /users/{id}", userDeleteHandler)
 
 	// 概要欄取得API
-	summarySaveHandler := UseMiddleware(ctx, s.summary.Save)
-	summaryListHandler := UseMiddleware(ctx, s.summary.List)
-	summaryDetailHandler := UseMiddleware(ctx, s.summary.Detail)
-	summaryDeleteHandler := UseMiddleware(ctx, s.summary.Delete)
+	summarySaveHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.summary.Save))
+	summaryListHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.summary.List))
+	summaryDetailHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.summary.Detail))
+	summaryDeleteHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.summary.Delete))
+	summaryPublishHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.summary.Publish))
 
 	engine.HandleFunc("POST /summaries", summarySaveHandler)
 	engine.HandleFunc("PUT /summaries/{id}", summarySaveHandler)
 	engine.HandleFunc("GET /summaries", summaryListHandler)
 	engine.HandleFunc("GET /summaries/{id}", summaryDetailHandler)
 	engine.HandleFunc("DELETE /summaries/{id}", summaryDeleteHandler)
+	engine.HandleFunc("POST /summaries/{id}/publish", summaryPublishHandler)
 
 	// カテゴリAPI
-	categorySaveHandler := UseMiddleware(ctx, s.category.Save)
-	categoryListHandler := UseMiddleware(ctx, s.category.List)
-	categoryDetailHandler := UseMiddleware(ctx, s.category.Detail)
-	categoryDeleteHandler := UseMiddleware(ctx, s.category.Delete)
+	categorySaveHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.category.Save))
+	categoryListHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.category.List))
+	categoryDetailHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.category.Detail))
+	categoryDeleteHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.category.Delete))
 
 	engine.HandleFunc("POST /categories", categorySaveHandler)
 	engine.HandleFunc("PUT /categories/{id}", categorySaveHandler)
@@ -91,10 +354,10 @@ func (s *server) Run(ctx context.Context) error {
 	engine.HandleFunc("DELETE /categories/{id}", categoryDeleteHandler)
 
 	// サブカテゴリAPI
-	subcategorySaveHandler := UseMiddleware(ctx, s.subcategory.Save)
-	subcategoryListHandler := UseMiddleware(ctx, s.subcategory.List)
-	subcategoryDetailHandler := UseMiddleware(ctx, s.subcategory.Detail)
-	subcategoryDeleteHandler := UseMiddleware(ctx, s.subcategory.Delete)
+	subcategorySaveHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.subcategory.Save))
+	subcategoryListHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.subcategory.List))
+	subcategoryDetailHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.subcategory.Detail))
+	subcategoryDeleteHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.subcategory.Delete))
 
 	engine.HandleFunc("POST /subcategories", subcategorySaveHandler)
 	engine.HandleFunc("PUT /subcategories/{id}", subcategorySaveHandler)
@@ -102,11 +365,32 @@ func (s *server) Run(ctx context.Context) error {
 	engine.HandleFunc("GET /subcategories/{id}", subcategoryDetailHandler)
 	engine.HandleFunc("DELETE /subcategories/{id}", subcategoryDeleteHandler)
 
+	// WebhookAPI
+	webhookSaveHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.webhook.Save))
+	webhookListHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.webhook.List))
+	webhookDetailHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.webhook.Detail))
+	webhookDeleteHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.webhook.Delete))
+	webhookDeliveriesHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupList, s.webhook.Deliveries))
+	webhookRedeliverHandler := UseMiddleware(ctx, s.limiter.Limit(RateLimitGroupWrite, s.webhook.Redeliver))
+
+	engine.HandleFunc("POST /webhooks", webhookSaveHandler)
+	engine.HandleFunc("PUT /webhooks/{id}", webhookSaveHandler)
+	engine.HandleFunc("GET /webhooks", webhookListHandler)
+	engine.HandleFunc("GET /webhooks/{id}", webhookDetailHandler)
+	engine.HandleFunc("DELETE /webhooks/{id}", webhookDeleteHandler)
+	engine.HandleFunc("GET /webhooks/{id}/deliveries", webhookDeliveriesHandler)
+	engine.HandleFunc("POST /webhooks/{id}/deliveries/{delivery_id}/redeliver", webhookRedeliverHandler)
+
+	// イベント配信API (接続を維持するためタイムアウトのミドルウェアを使わない)
+	engine.HandleFunc("GET /events/summaries", UseStreamMiddleware(ctx, s.limiter.Limit(RateLimitGroupDefault, s.stream.Summaries)))
+
 	port := fmt.Sprintf(":%s", cfg.Port)
 	srv := &http.Server{
 		Addr:    port,
-		Handler: engine,
+		Handler: s.compressor.Handler(s.cors.Handler(engine)),
 	}
+	// Shutdown は接続中のストリームの終了を待つため、先にストリームを終わらせる
+	srv.RegisterOnShutdown(s.broker.Close)
 
 	// サーバーの起動
 	go func() {
@@ -121,6 +405,13 @@ func (s *server) Run(ctx context.Context) error {
 	<-quit
 	logger.Info(ctx, "graceful shutdown")
 
+	// 新しいリクエストが振り分けられないように /readyz を失敗にし、ロードバランサーが外すまで待つ
+	s.readiness.Shutdown()
+	if cfg.ShutdownDrainDelay > 0 {
+		logger.Info(ctx, fmt.Sprintf("draining for %s", cfg.ShutdownDrainDelay))
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
 	// サーバーのタイムアウト設定
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
@@ -134,22 +425,99 @@ func (s *server) Run(ctx context.Context) error {
 	return nil
 }
 
+// newTraceProvider は TRACE_EXPORTER に指定した出力先にスパンを出力する Provider を生成します
+// TRACE_EXPORTER が空の場合は nil を返し、スパンはログのトレースIDとスパンIDにだけ使います
+func newTraceProvider(cfg *config.Config) (*Trace.Provider, error) {
+	var exporter Trace.Exporter
+	switch cfg.TraceExporter {
+	case "":
+		return nil, nil
+	case config.TraceExporterStdout:
+		exporter = Trace.NewWriterExporter(os.Stdout, cfg.TraceServiceName)
+	case config.TraceExporterOTLP:
+		exporter = Trace.NewOTLPExporter(cfg.TraceOTLPEndpoint, cfg.TraceServiceName, cfg.TraceExportInterval)
+	default:
+		return nil, fmt.Errorf("unsupported TRACE_EXPORTER: %q", cfg.TraceExporter)
+	}
+	return Trace.NewProvider(exporter, cfg.TraceExportInterval, cfg.TraceBatchSize), nil
+}
+
+// newRateLimiter は RATE_LIMIT_STORE のストアでレート制限を生成します
+// 空の場合は制限しないため nil を返します
+func newRateLimiter(cfg *config.Config) (*RateLimiter, error) {
+	var store ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "":
+		return nil, nil
+	case config.RateLimitStoreMemory:
+		store = ratelimit.NewMemoryStore()
+	case config.RateLimitStoreDatabase:
+		switch cfg.DatabaseDriver() {
+		case config.DBDriverMySQL:
+			store = mysql.NewRateLimitStore()
+		case config.DBDriverSQLite:
+			store = sqlite.NewRateLimitStore()
+		default:
+			return nil, fmt.Errorf("RATE_LIMIT_STORE=%s is not supported with DB_DRIVER=%s", cfg.RateLimitStore, cfg.DatabaseDriver())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported RATE_LIMIT_STORE: %q", cfg.RateLimitStore)
+	}
+	return NewRateLimiter(cfg, store)
+}
+
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	httputil.Response(&w, http.StatusOK, map[string]string{"message": "OK"})
 }
 
+// livez はプロセスがリクエストに応答できることを返します
+// 依存先の障害で再起動が繰り返されないように、依存先は確認しません
+func livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	httputil.Response(&w, http.StatusOK, &health.Report{Status: health.StatusPass, Checks: []*health.Result{}})
+}
+
+// readyz は依存先のチェックの結果を返します
+// いずれかのチェックが失敗した場合と、終了処理中の場合は503を返します
+func (s *server) readyz(w http.ResponseWriter, r *http.Request) {
+	report := s.readiness.Check(r.Context())
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	httputil.Response(&w, status, report)
+}
+
+// cacheStats はリポジトリのキャッシュのヒット数・ミス数などを返します
+// キャッシュを使わない設定の場合はすべて0を返します
+func (s *server) cacheStats(w http.ResponseWriter, r *http.Request) {
+	var stats Cache.Stats
+	if s.cache != nil {
+		stats = s.cache.Stats()
+	}
+	httputil.Response(&w, http.StatusOK, stats)
+}
+
 func DBHealthCheck(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	db := Ctx.GetDB(ctx)
+
+	// インメモリのデータベースは常に利用できる
+	if Ctx.GetCtxCfg(ctx).DatabaseDriver() == config.DBDriverMemory {
+		httputil.Response(&w, http.StatusOK, map[string]string{"message": i18n.T(ctx, i18n.MsgDBConnectionHealthy)})
+		return
+	}
+
+	db := Ctx.GetPool(ctx)
 	if db == nil {
-		http.Error(w, "Database connection not found", http.StatusInternalServerError)
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, Errors.ErrSystem, i18n.T(ctx, i18n.MsgDBConnectionNotFound)))
 		return
 	}
 
 	if err := db.PingContext(ctx); err != nil {
-		http.Error(w, "Database connection error", http.StatusInternalServerError)
+		response.Error(ctx, w, Errors.WrapWithMessage(ctx, err, i18n.T(ctx, i18n.MsgDBConnectionError)))
 		return
 	}
 
-	httputil.Response(&w, http.StatusOK, map[string]string{"message": "Database connection is healthy"})
+	httputil.Response(&w, http.StatusOK, map[string]string{"message": i18n.T(ctx, i18n.MsgDBConnectionHealthy)})
 }
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name         string
+		check        health.CheckFunc
+		shutdown     bool
+		wantStatus   int
+		wantReport   health.Status
+		wantCheckNum int
+	}{
+		{
+			name:         "成功ケース: すべてのチェックが成功する",
+			check:        func(context.Context) error { return nil },
+			wantStatus:   http.StatusOK,
+			wantReport:   health.StatusPass,
+			wantCheckNum: 1,
+		},
+		{
+			name:         "失敗ケース: チェックが失敗すると503を返す",
+			check:        func(context.Context) error { return errors.New("connection refused") },
+			wantStatus:   http.StatusServiceUnavailable,
+			wantReport:   health.StatusFail,
+			wantCheckNum: 1,
+		},
+		{
+			name:         "失敗ケース: 終了処理中は503を返す",
+			check:        func(context.Context) error { return nil },
+			shutdown:     true,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantReport:   health.StatusFail,
+			wantCheckNum: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &server{readiness: health.NewRegistry(time.Second, 0)}
+			s.readiness.Register("database", tt.check)
+			if tt.shutdown {
+				s.readiness.Shutdown()
+			}
+
+			rec := httptest.NewRecorder()
+			s.readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			var report health.Report
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantReport, report.Status)
+			assert.Len(t, report.Checks, tt.wantCheckNum)
+		})
+	}
+}
+
+func TestLivez(t *testing.T) {
+	rec := httptest.NewRecorder()
+	livez(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"pass","checks":[]}`, rec.Body.String())
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats はキャッシュの統計情報です
+type Stats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+	Capacity      int    `json:"capacity"`
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	tags      []string
+	expiresAt time.Time
+}
+
+// Cache はTTL付きのLRUキャッシュです
+// エントリにはタグを付けることができ、InvalidateTags でタグ単位に削除します
+// 取得した値は複数のリクエストで共有されるため、呼び出し側で変更してはいけません
+type Cache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+	tags     map[string]map[string]struct{}
+	// version はタグの無効化のたびに増えます
+	// 読み込み中に無効化された古い値を保存しないために使います
+	version uint64
+	stats   Stats
+	now     func() time.Time
+}
+
+// New は最大 capacity 件、有効期限 ttl のキャッシュを生成します
+func New[V any](capacity int, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		tags:     make(map[string]map[string]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Get はキーに対応する値を返します
+// 有効期限が切れている場合は削除して見つからなかったものとして扱います
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[V])
+		if c.now().Before(e.expiresAt) {
+			c.ll.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true
+		}
+		c.removeElement(el)
+	}
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+// Set は値をタグ付きで保存します
+// 件数が上限を超えた場合は最も使われていないエントリから削除します
+func (c *Cache[V]) Set(key string, value V, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value, tags)
+}
+
+// GetOrLoad はキャッシュにない場合に load で読み込んで保存します
+// load は値とその値に付けるタグを返します。エラーの場合は保存しません
+// 読み込み中にタグが無効化された場合、読み込んだ値は古い可能性があるため保存せずに返します
+func (c *Cache[V]) GetOrLoad(key string, load func() (V, []string, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	version := c.version
+	c.mu.Unlock()
+
+	v, tags, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version == version {
+		c.set(key, v, tags)
+	}
+	return v, nil
+}
+
+// InvalidateTags は指定したタグのいずれかが付いたエントリを削除します
+func (c *Cache[V]) InvalidateTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			if el, ok := c.items[key]; ok {
+				c.removeElement(el)
+				c.stats.Invalidations++
+			}
+		}
+	}
+}
+
+// Purge はすべてのエントリを削除します
+func (c *Cache[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.ll.Init()
+	clear(c.items)
+	clear(c.tags)
+}
+
+// Stats は現在の統計情報を返します
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Size = c.ll.Len()
+	s.Capacity = c.capacity
+	return s
+}
+
+func (c *Cache[V]) set(key string, value V, tags []string) {
+	if c.capacity <= 0 {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+
+	e := &entry[V]{key: key, value: value, tags: tags, expiresAt: c.now().Add(c.ttl)}
+	c.items[key] = c.ll.PushFront(e)
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache[V]) removeElement(el *list.Element) {
+	e := c.ll.Remove(el).(*entry[V])
+	delete(c.items, e.key)
+	for _, tag := range e.tags {
+		if keys, ok := c.tags[tag]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(c.tags, tag)
+			}
+		}
+	}
+}
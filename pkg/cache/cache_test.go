@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	type op struct {
+		set        string
+		tags       []string
+		invalidate []string
+		advance    time.Duration
+	}
+
+	tests := []struct {
+		name      string
+		capacity  int
+		ops       []op
+		wantHit   []string
+		wantMiss  []string
+		wantStats Stats
+	}{
+		{
+			name:      "成功ケース: 保存した値を取得できる",
+			capacity:  2,
+			ops:       []op{{set: "a"}},
+			wantHit:   []string{"a"},
+			wantMiss:  []string{"b"},
+			wantStats: Stats{Hits: 1, Misses: 1, Size: 1, Capacity: 2},
+		},
+		{
+			name:      "成功ケース: 上限を超えると最も使われていないエントリを削除する",
+			capacity:  2,
+			ops:       []op{{set: "a"}, {set: "b"}, {set: "c"}},
+			wantHit:   []string{"b", "c"},
+			wantMiss:  []string{"a"},
+			wantStats: Stats{Hits: 2, Misses: 1, Evictions: 1, Size: 2, Capacity: 2},
+		},
+		{
+			name:      "成功ケース: 有効期限が切れたエントリは取得できない",
+			capacity:  2,
+			ops:       []op{{set: "a"}, {advance: time.Minute}, {set: "b"}},
+			wantHit:   []string{"b"},
+			wantMiss:  []string{"a"},
+			wantStats: Stats{Hits: 1, Misses: 1, Size: 1, Capacity: 2},
+		},
+		{
+			name:     "成功ケース: タグを指定して削除する",
+			capacity: 3,
+			ops: []op{
+				{set: "a", tags: []string{"x"}},
+				{set: "b", tags: []string{"x", "y"}},
+				{set: "c", tags: []string{"z"}},
+				{invalidate: []string{"y"}},
+			},
+			wantHit:   []string{"a", "c"},
+			wantMiss:  []string{"b"},
+			wantStats: Stats{Hits: 2, Misses: 1, Invalidations: 1, Size: 2, Capacity: 3},
+		},
+		{
+			name:      "成功ケース: 上限が0の場合は保存しない",
+			capacity:  0,
+			ops:       []op{{set: "a"}},
+			wantMiss:  []string{"a"},
+			wantStats: Stats{Misses: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			c := New[string](tt.capacity, 30*time.Second)
+			c.now = func() time.Time { return now }
+
+			for _, o := range tt.ops {
+				if o.set != "" {
+					c.Set(o.set, o.set, o.tags...)
+				}
+				if len(o.invalidate) > 0 {
+					c.InvalidateTags(o.invalidate...)
+				}
+				now = now.Add(o.advance)
+			}
+
+			for _, key := range tt.wantHit {
+				v, ok := c.Get(key)
+				assert.True(t, ok, key)
+				assert.Equal(t, key, v)
+			}
+			for _, key := range tt.wantMiss {
+				_, ok := c.Get(key)
+				assert.False(t, ok, key)
+			}
+			assert.Equal(t, tt.wantStats, c.Stats())
+		})
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	errLoad := errors.New("load error")
+
+	tests := []struct {
+		name             string
+		invalidateDuring bool
+		loadErr          error
+		wantErr          bool
+		wantCached       bool
+	}{
+		{
+			name:       "成功ケース: 読み込んだ値を保存する",
+			wantCached: true,
+		},
+		{
+			name:             "成功ケース: 読み込み中に無効化された場合は保存しない",
+			invalidateDuring: true,
+			wantCached:       false,
+		},
+		{
+			name:       "失敗ケース: 読み込みに失敗した場合は保存しない",
+			loadErr:    errLoad,
+			wantErr:    true,
+			wantCached: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New[string](10, time.Minute)
+
+			v, err := c.GetOrLoad("key", func() (string, []string, error) {
+				if tt.invalidateDuring {
+					c.InvalidateTags("tag")
+				}
+				return "value", []string{"tag"}, tt.loadErr
+			})
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.loadErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "value", v)
+			}
+
+			_, ok := c.Get("key")
+			assert.Equal(t, tt.wantCached, ok)
+		})
+	}
+}
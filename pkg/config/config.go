@@ -2,14 +2,37 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Env string
 
 const CtxEnvKey Env = "env"
 
+// DBDriver に指定できるデータベースの種類
+const (
+	DBDriverMySQL  = "mysql"
+	DBDriverSQLite = "sqlite"
+	DBDriverMemory = "memory"
+)
+
+// TraceExporter に指定できるトレースの出力先
+const (
+	TraceExporterStdout = "stdout"
+	TraceExporterOTLP   = "otlp"
+)
+
+// RateLimitStore に指定できるレート制限のバケットの保存先
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStoreDatabase = "database"
+)
+
 type Config struct {
 	Env                       string `env:"ENV" envDefault:"dev"`
 	Port                      string `env:"PORT" envDefault:"8080"`
@@ -20,7 +43,80 @@ type Config struct {
 	CLOUDFLARE_R2_ACCESSKEY   string `env:"CLOUDFLARE_R2_ACCESSKEY" envDefult:""`
 	CLOUDFLARE_R2_SECRETKEY   string `env:"CLOUDFLARE_R2_SECRETKEY" envDefult:""`
 	CLOUDFLARE_R2_BUCKET_NAME string `env:"CLOUDFLARE_R2_BUCKET_NAME" envDefult:""`
-	COOKIE_DOMAIN             string `env:"COOKIE_DOMAIN" envDefault:"localhost"`
+	COOKIE_DOMAIN             string `env:"COOKIE_DOMAIN" envDefault:""`
+
+	// データベースの種類 (mysql / sqlite / memory)
+	// 未指定の場合は DATABASE_URL のスキームから判定します
+	DBDriver string `env:"DB_DRIVER" envDefault:""`
+	// コネクションプールの設定
+	DBMaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" envDefault:"25"`
+	DBMaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" envDefault:"10"`
+	DBConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" envDefault:"1h"`
+	DBConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" envDefault:"5m"`
+	// 読み取り専用のレプリカのDSN (カンマ区切りで複数指定)
+	DatabaseReplicaURLs          []string      `env:"DATABASE_REPLICA_URLS" envDefault:""`
+	DBReplicaHealthCheckInterval time.Duration `env:"DB_REPLICA_HEALTH_CHECK_INTERVAL" envDefault:"10s"`
+	// 書き込み後にプライマリから読み取る期間 (レプリケーション遅延より長くする)
+	DBReadYourWritesWindow time.Duration `env:"DB_READ_YOUR_WRITES_WINDOW" envDefault:"5s"`
+	// 一覧・詳細の取得結果のキャッシュ (CACHE_SIZE が0の場合は使わない)
+	CacheSize int           `env:"CACHE_SIZE" envDefault:"1000"`
+	CacheTTL  time.Duration `env:"CACHE_TTL" envDefault:"30s"`
+	// GETのレスポンスの Cache-Control の max-age (0の場合は no-cache で毎回再検証させる)
+	HTTPCacheMaxAge time.Duration `env:"HTTP_CACHE_MAX_AGE" envDefault:"0s"`
+	// アウトボックスのイベントの配信設定
+	OutboxPollInterval   time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize      int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	OutboxMaxAttempts    int           `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"10"`
+	OutboxRetryBaseDelay time.Duration `env:"OUTBOX_RETRY_BASE_DELAY" envDefault:"1s"`
+	// Webhookの送信設定 (WEBHOOK_TIMEOUT は1回の送信でレスポンスを待つ時間)
+	WebhookPollInterval   time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	WebhookBatchSize      int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	WebhookMaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	WebhookRetryBaseDelay time.Duration `env:"WEBHOOK_RETRY_BASE_DELAY" envDefault:"10s"`
+	WebhookTimeout        time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	// Server-Sent Events の設定 (SSE_BUFFER_SIZE は再接続時に再送するために保持するイベントの数)
+	SSEBufferSize        int           `env:"SSE_BUFFER_SIZE" envDefault:"1000"`
+	SSEHeartbeatInterval time.Duration `env:"SSE_HEARTBEAT_INTERVAL" envDefault:"15s"`
+	// トレースの出力先 (TRACE_EXPORTER: 空の場合は出力しない、stdout、otlp)
+	TraceExporter       string        `env:"TRACE_EXPORTER" envDefault:""`
+	TraceOTLPEndpoint   string        `env:"TRACE_OTLP_ENDPOINT" envDefault:"http://localhost:4318/v1/traces"`
+	TraceServiceName    string        `env:"TRACE_SERVICE_NAME" envDefault:"web-ya-hime"`
+	TraceExportInterval time.Duration `env:"TRACE_EXPORT_INTERVAL" envDefault:"5s"`
+	TraceBatchSize      int           `env:"TRACE_BATCH_SIZE" envDefault:"512"`
+	// レート制限 (RATE_LIMIT_STORE: 空の場合は制限しない、memory はインスタンスごと、database は rate_limits テーブルで共有)
+	// 上限はルートのグループごとに "60/1m" (1分あたり60回) の形式で指定し、空の場合はそのグループを制限しない
+	RateLimitStore   string `env:"RATE_LIMIT_STORE" envDefault:"memory"`
+	RateLimitDefault string `env:"RATE_LIMIT_DEFAULT" envDefault:"600/1m"`
+	RateLimitList    string `env:"RATE_LIMIT_LIST" envDefault:"120/1m"`
+	RateLimitWrite   string `env:"RATE_LIMIT_WRITE" envDefault:"60/1m"`
+	// X-Forwarded-For を信頼するプロキシのIPアドレスかCIDR (カンマ区切り)
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envDefault:""`
+	// CORS の設定 (CORS_ALLOWED_ORIGINS: "*"、オリジンの完全一致、"https://*.example.com" でサブドメインのワイルドカード)
+	// CORS_ROUTE_ORIGINS はパスの前方一致でルートごとに許可するオリジンを上書きする ("/webhooks=https://admin.example.com https://*.admin.example.com")
+	CORSAllowedOrigins   []string      `env:"CORS_ALLOWED_ORIGINS" envDefault:"*"`
+	CORSAllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	CORSExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" envDefault:""`
+	CORSMaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`
+	CORSRouteOrigins     []string      `env:"CORS_ROUTE_ORIGINS" envDefault:""`
+	// レスポンスの圧縮 (COMPRESSION_ENCODINGS: 優先する順に gzip、deflate を指定し、空の場合は圧縮しない)
+	// COMPRESSION_MIN_SIZE バイト未満のレスポンスと、画像のように圧縮済みの形式のレスポンスは圧縮しない
+	CompressionEncodings []string `env:"COMPRESSION_ENCODINGS" envDefault:"gzip,deflate"`
+	CompressionMinSize   int      `env:"COMPRESSION_MIN_SIZE" envDefault:"1024"`
+	// リクエストの処理時間の上限 (REQUEST_TIMEOUT: 通常のルート、REQUEST_TIMEOUT_LONG: インポートやエクスポートのような時間のかかるルート)
+	// 0s の場合は上限を設けない。Server-Sent Events のルートには適用しない
+	RequestTimeout     time.Duration `env:"REQUEST_TIMEOUT" envDefault:"5s"`
+	RequestTimeoutLong time.Duration `env:"REQUEST_TIMEOUT_LONG" envDefault:"2m"`
+	// リクエストボディの最大のバイト数 (超えた場合は413を返す)。ルートごとに MaxBodyBytes で変更できる
+	RequestMaxBodyBytes int `env:"REQUEST_MAX_BODY_BYTES" envDefault:"1048576"`
+	// /readyz の依存先のチェック (HEALTH_CHECK_TIMEOUT: チェックごとの上限、HEALTH_CHECK_CACHE_TTL: 結果を再利用する期間)
+	HealthCheckTimeout  time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	HealthCheckCacheTTL time.Duration `env:"HEALTH_CHECK_CACHE_TTL" envDefault:"5s"`
+	// 終了のシグナルを受け取ってから /readyz を失敗にしたまま新しいリクエストを受け付ける期間
+	// ロードバランサーが振り分け先から外すまでの時間を指定する。0s の場合は待たずに終了処理を始める
+	ShutdownDrainDelay time.Duration `env:"SHUTDOWN_DRAIN_DELAY" envDefault:"0s"`
+	// 起動時の接続確認のリトライ設定
+	DBConnectMaxRetries    int           `env:"DB_CONNECT_MAX_RETRIES" envDefault:"5"`
+	DBConnectRetryInterval time.Duration `env:"DB_CONNECT_RETRY_INTERVAL" envDefault:"2s"`
 }
 
 func New(ctx context.Context) (context.Context, error) {
@@ -32,19 +128,83 @@ func New(ctx context.Context) (context.Context, error) {
 	return context.WithValue(ctx, CtxEnvKey, cfg), nil
 }
 
+// DatabaseDriver は使用するデータベースの種類を返します
+func (c *Config) DatabaseDriver() string {
+	if c.DBDriver != "" {
+		return c.DBDriver
+	}
+	return DBDriverFromDSN(c.Database_url)
+}
+
+// DBDriverFromDSN は DSN のスキームからデータベースの種類を判定します
+// sqlite:// で始まる場合はSQLite、それ以外はMySQLのDSNとして扱います
+func DBDriverFromDSN(dsn string) string {
+	if strings.HasPrefix(dsn, "sqlite://") {
+		return DBDriverSQLite
+	}
+	return DBDriverMySQL
+}
+
 func loadConfig() (*Config, error) {
 	cfg := &Config{}
 	value := reflect.Indirect(reflect.ValueOf(cfg))
 
 	for _, v := range reflect.VisibleFields(value.Type()) {
 		env := os.Getenv(v.Tag.Get("env"))
-		if env != "" {
-			value.FieldByName(v.Name).SetString(env)
-			continue
+		if env == "" {
+			env = v.Tag.Get("envDefault")
+		}
+		if err := setValue(value.FieldByName(v.Name), env); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", v.Tag.Get("env"), err)
 		}
-		defaultValue := v.Tag.Get("envDefault")
-		value.FieldByName(v.Name).SetString(defaultValue)
 	}
 
 	return cfg, nil
 }
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue は環境変数の値をフィールドの型に変換して設定します
+// string, int, bool, time.Duration("30s" などの形式), []string(カンマ区切り) に対応します
+func setValue(field reflect.Value, value string) error {
+	switch {
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	case field.Type() == durationType:
+		if value == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.Bool:
+		if value == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	default:
+		field.SetString(value)
+	}
+	return nil
+}
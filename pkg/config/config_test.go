@@ -3,6 +3,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_loadConfig(t *testing.T) {
@@ -10,6 +11,7 @@ func Test_loadConfig(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
+		env     map[string]string
 		want    *Config
 		wantErr bool
 	}{
@@ -17,22 +19,135 @@ func Test_loadConfig(t *testing.T) {
 			name: "成功ケース: 環境変数が設定されていない場合、デフォルト値が設定される",
 			args: args{},
 			want: &Config{
-				Env:                       "dev",
-				Port:                      "8080",
-				Database_url:              "user:P@ssw0rd@tcp(127.0.0.1:3306)/develop_web_ya_hime?parseTime=true",
-				Sentry_DSN:                "",
-				ProjectID:                 "",
-				CLOUDFLARE_R2_ACCOUNT_ID:  "",
-				CLOUDFLARE_R2_ACCESSKEY:   "",
-				CLOUDFLARE_R2_SECRETKEY:   "",
-				CLOUDFLARE_R2_BUCKET_NAME: "",
-				COOKIE_DOMAIN:             "localhost",
+				Env:                          "dev",
+				Port:                         "8080",
+				Database_url:                 "user:P@ssw0rd@tcp(127.0.0.1:3306)/develop_web_ya_hime?parseTime=true",
+				Sentry_DSN:                   "",
+				ProjectID:                    "",
+				CLOUDFLARE_R2_ACCOUNT_ID:     "",
+				CLOUDFLARE_R2_ACCESSKEY:      "",
+				CLOUDFLARE_R2_SECRETKEY:      "",
+				CLOUDFLARE_R2_BUCKET_NAME:    "",
+				DBMaxOpenConns:               25,
+				DBMaxIdleConns:               10,
+				DBConnMaxLifetime:            time.Hour,
+				DBConnMaxIdleTime:            5 * time.Minute,
+				DBReplicaHealthCheckInterval: 10 * time.Second,
+				DBReadYourWritesWindow:       5 * time.Second,
+				CacheSize:                    1000,
+				CacheTTL:                     30 * time.Second,
+				OutboxPollInterval:           time.Second,
+				OutboxBatchSize:              100,
+				OutboxMaxAttempts:            10,
+				OutboxRetryBaseDelay:         time.Second,
+				WebhookPollInterval:          time.Second,
+				WebhookBatchSize:             50,
+				WebhookMaxAttempts:           8,
+				WebhookRetryBaseDelay:        10 * time.Second,
+				WebhookTimeout:               10 * time.Second,
+				SSEBufferSize:                1000,
+				SSEHeartbeatInterval:         15 * time.Second,
+				TraceOTLPEndpoint:            "http://localhost:4318/v1/traces",
+				TraceServiceName:             "web-ya-hime",
+				TraceExportInterval:          5 * time.Second,
+				TraceBatchSize:               512,
+				RateLimitStore:               "memory",
+				RateLimitDefault:             "600/1m",
+				RateLimitList:                "120/1m",
+				RateLimitWrite:               "60/1m",
+				CORSAllowedOrigins:           []string{"*"},
+				CORSMaxAge:                   10 * time.Minute,
+				CompressionEncodings:         []string{"gzip", "deflate"},
+				CompressionMinSize:           1024,
+				RequestTimeout:               5 * time.Second,
+				RequestTimeoutLong:           2 * time.Minute,
+				RequestMaxBodyBytes:          1 << 20,
+				HealthCheckTimeout:           2 * time.Second,
+				HealthCheckCacheTTL:          5 * time.Second,
+				DBConnectMaxRetries:          5,
+				DBConnectRetryInterval:       2 * time.Second,
 			},
 			wantErr: false,
 		},
+		{
+			name: "成功ケース: 数値と真偽値と時間とリストの環境変数が型に変換される",
+			env: map[string]string{
+				"DB_MAX_OPEN_CONNS":         "50",
+				"CORS_ALLOW_CREDENTIALS":    "true",
+				"DB_CONN_MAX_LIFETIME":      "30m",
+				"DB_CONNECT_RETRY_INTERVAL": "500ms",
+				"DATABASE_REPLICA_URLS":     "user:P@ssw0rd@tcp(replica1:3306)/db, user:P@ssw0rd@tcp(replica2:3306)/db,",
+			},
+			want: &Config{
+				Env:               "dev",
+				Port:              "8080",
+				Database_url:      "user:P@ssw0rd@tcp(127.0.0.1:3306)/develop_web_ya_hime?parseTime=true",
+				DBMaxOpenConns:    50,
+				DBMaxIdleConns:    10,
+				DBConnMaxLifetime: 30 * time.Minute,
+				DBConnMaxIdleTime: 5 * time.Minute,
+				DatabaseReplicaURLs: []string{
+					"user:P@ssw0rd@tcp(replica1:3306)/db",
+					"user:P@ssw0rd@tcp(replica2:3306)/db",
+				},
+				DBReplicaHealthCheckInterval: 10 * time.Second,
+				DBReadYourWritesWindow:       5 * time.Second,
+				CacheSize:                    1000,
+				CacheTTL:                     30 * time.Second,
+				OutboxPollInterval:           time.Second,
+				OutboxBatchSize:              100,
+				OutboxMaxAttempts:            10,
+				OutboxRetryBaseDelay:         time.Second,
+				WebhookPollInterval:          time.Second,
+				WebhookBatchSize:             50,
+				WebhookMaxAttempts:           8,
+				WebhookRetryBaseDelay:        10 * time.Second,
+				WebhookTimeout:               10 * time.Second,
+				SSEBufferSize:                1000,
+				SSEHeartbeatInterval:         15 * time.Second,
+				TraceOTLPEndpoint:            "http://localhost:4318/v1/traces",
+				TraceServiceName:             "web-ya-hime",
+				TraceExportInterval:          5 * time.Second,
+				TraceBatchSize:               512,
+				RateLimitStore:               "memory",
+				RateLimitDefault:             "600/1m",
+				RateLimitList:                "120/1m",
+				RateLimitWrite:               "60/1m",
+				CORSAllowedOrigins:           []string{"*"},
+				CORSAllowCredentials:         true,
+				CORSMaxAge:                   10 * time.Minute,
+				CompressionEncodings:         []string{"gzip", "deflate"},
+				CompressionMinSize:           1024,
+				RequestTimeout:               5 * time.Second,
+				RequestTimeoutLong:           2 * time.Minute,
+				RequestMaxBodyBytes:          1 << 20,
+				HealthCheckTimeout:           2 * time.Second,
+				HealthCheckCacheTTL:          5 * time.Second,
+				DBConnectMaxRetries:          5,
+				DBConnectRetryInterval:       500 * time.Millisecond,
+			},
+		},
+		{
+			name:    "失敗ケース: 数値でない値",
+			env:     map[string]string{"DB_MAX_OPEN_CONNS": "many"},
+			wantErr: true,
+		},
+		{
+			name:    "失敗ケース: 真偽値でない値",
+			env:     map[string]string{"CORS_ALLOW_CREDENTIALS": "yes please"},
+			wantErr: true,
+		},
+		{
+			name:    "失敗ケース: 時間の形式でない値",
+			env:     map[string]string{"DB_CONN_MAX_LIFETIME": "1 hour"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
 			got, err := loadConfig()
 			if (err != nil) != tt.wantErr {
 				t.Errorf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
@@ -44,3 +159,34 @@ func Test_loadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_DatabaseDriver(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "成功ケース: MySQLのDSN",
+			cfg:  Config{Database_url: "user:P@ssw0rd@tcp(127.0.0.1:3306)/develop_web_ya_hime?parseTime=true"},
+			want: DBDriverMySQL,
+		},
+		{
+			name: "成功ケース: sqliteスキームのDSN",
+			cfg:  Config{Database_url: "sqlite://./data/web-ya-hime.db"},
+			want: DBDriverSQLite,
+		},
+		{
+			name: "成功ケース: DB_DRIVERの指定を優先する",
+			cfg:  Config{DBDriver: DBDriverMemory, Database_url: "sqlite://./data/web-ya-hime.db"},
+			want: DBDriverMemory,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.DatabaseDriver(); got != tt.want {
+				t.Errorf("DatabaseDriver() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
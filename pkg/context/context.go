@@ -3,6 +3,7 @@ package context
 import (
 	"context"
 	"database/sql"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/o-ga09/web-ya-hime/pkg/config"
@@ -28,12 +29,13 @@ func SetCtxFromUser(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, USERID, userID)
 }
 
-func SetRequestID(ctx context.Context) context.Context {
-	reqID := GetRequestID(ctx)
-	if reqID != "" {
-		return ctx
+// SetRequestID はリクエストIDをcontextに設定します。id が空の場合は生成します
+// ログとエラーレスポンスはこのIDを使うため、リクエストIDはこのキーだけに保存します
+func SetRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		id = uuid.NewString()
 	}
-	return context.WithValue(ctx, REQUESTID, uuid.NewString())
+	return context.WithValue(ctx, REQUESTID, id)
 }
 
 func GetRequestID(ctx context.Context) string {
@@ -43,6 +45,11 @@ func GetRequestID(ctx context.Context) string {
 	return ""
 }
 
+// SetCtxCfg は設定をcontextに設定します
+func SetCtxCfg(ctx context.Context, cfg *config.Config) context.Context {
+	return context.WithValue(ctx, config.CtxEnvKey, cfg)
+}
+
 func GetCtxCfg(ctx context.Context) *config.Config {
 	cfg, ok := ctx.Value(config.CtxEnvKey).(*config.Config)
 	if !ok {
@@ -51,13 +58,166 @@ func GetCtxCfg(ctx context.Context) *config.Config {
 	return cfg
 }
 
+const TXKEY CtxDBKey = "tx"
+
+// Querier は *sql.DB と *sql.Tx に共通するクエリ実行のインターフェースです
+// リポジトリはトランザクションの内外を意識せずにクエリを実行できます
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 func SetDB(ctx context.Context, db *sql.DB) context.Context {
 	return context.WithValue(ctx, DBKEY, db)
 }
 
-func GetDB(ctx context.Context) *sql.DB {
+// GetDB はクエリの実行先を返します
+// トランザクション中であれば *sql.Tx、そうでなければコネクションプールの *sql.DB を返します
+// どちらも設定されていない場合は nil を返します
+func GetDB(ctx context.Context) Querier {
+	if tx := GetTx(ctx); tx != nil {
+		return tx
+	}
+	if db := GetPool(ctx); db != nil {
+		return db
+	}
+	return nil
+}
+
+// GetPool はトランザクションの有無にかかわらずコネクションプールの *sql.DB を返します
+func GetPool(ctx context.Context) *sql.DB {
 	if db, ok := ctx.Value(DBKEY).(*sql.DB); ok {
 		return db
 	}
 	return nil
 }
+
+// SetTx は実行中のトランザクションをcontextに設定します
+func SetTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, TXKEY, tx)
+}
+
+// GetTx は実行中のトランザクションを返します
+// トランザクション外の場合は nil を返します
+func GetTx(ctx context.Context) *sql.Tx {
+	if tx, ok := ctx.Value(TXKEY).(*sql.Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+const AFTERCOMMITKEY CtxDBKey = "afterCommit"
+
+type afterCommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithAfterCommit はコミット後に実行する処理を登録できるcontextを返します
+// トランザクションマネージャーはトランザクションの開始時に呼び出し、コミットに成功した場合だけ返された関数を実行します
+func WithAfterCommit(ctx context.Context) (context.Context, func()) {
+	hooks := &afterCommitHooks{}
+	run := func() {
+		hooks.mu.Lock()
+		fns := hooks.fns
+		hooks.fns = nil
+		hooks.mu.Unlock()
+		for _, fn := range fns {
+			fn()
+		}
+	}
+	return context.WithValue(ctx, AFTERCOMMITKEY, hooks), run
+}
+
+// AfterCommit は実行中のトランザクションのコミット後に fn を実行するように登録します
+// トランザクション外の場合は登録せずに false を返します
+func AfterCommit(ctx context.Context, fn func()) bool {
+	hooks, ok := ctx.Value(AFTERCOMMITKEY).(*afterCommitHooks)
+	if !ok {
+		return false
+	}
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+	return true
+}
+
+// InTx はトランザクション中かどうかを返します
+// インメモリのリポジトリのように *sql.Tx を使わないトランザクションも含みます
+func InTx(ctx context.Context) bool {
+	if GetTx(ctx) != nil {
+		return true
+	}
+	_, ok := ctx.Value(AFTERCOMMITKEY).(*afterCommitHooks)
+	return ok
+}
+
+const REPLICASKEY CtxDBKey = "replicas"
+const READPRIMARYKEY CtxDBKey = "readPrimary"
+
+// ReplicaPicker は読み取りクエリを実行するレプリカを選びます
+// 利用できるレプリカがない場合は nil を返します
+type ReplicaPicker interface {
+	Pick() *sql.DB
+}
+
+// SetReplicas は読み取り専用のレプリカをcontextに設定します
+func SetReplicas(ctx context.Context, replicas ReplicaPicker) context.Context {
+	return context.WithValue(ctx, REPLICASKEY, replicas)
+}
+
+// GetReplicas はcontextに設定されたレプリカを返します
+// 設定されていない場合は nil を返します
+func GetReplicas(ctx context.Context) ReplicaPicker {
+	if replicas, ok := ctx.Value(REPLICASKEY).(ReplicaPicker); ok {
+		return replicas
+	}
+	return nil
+}
+
+// SetReadFromPrimary はこのcontextでの読み取りをプライマリで行うようにします
+// 書き込みの直後に、レプリケーションの遅延で書き込んだ内容が読めなくなることを防ぎます
+func SetReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, READPRIMARYKEY, true)
+}
+
+// IsReadFromPrimary は読み取りをプライマリで行う必要があるかを返します
+func IsReadFromPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(READPRIMARYKEY).(bool)
+	return v
+}
+
+// GetReadDB は読み取りクエリの実行先を返します
+// トランザクション中はトランザクション、プライマリから読む指定がある場合やレプリカを利用できない場合は
+// GetDB と同じ接続を返し、それ以外はレプリカを返します
+func GetReadDB(ctx context.Context) Querier {
+	if tx := GetTx(ctx); tx != nil {
+		return tx
+	}
+	if !IsReadFromPrimary(ctx) {
+		if replicas := GetReplicas(ctx); replicas != nil {
+			if db := replicas.Pick(); db != nil {
+				return db
+			}
+		}
+	}
+	return GetDB(ctx)
+}
+
+type CtxLangKey string
+
+const LANGKEY CtxLangKey = "lang"
+
+// SetLang はレスポンスに使用する言語をcontextに設定します
+func SetLang(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, LANGKEY, lang)
+}
+
+// GetLang はcontextからレスポンスに使用する言語を取得します
+func GetLang(ctx context.Context) string {
+	if lang, ok := ctx.Value(LANGKEY).(string); ok {
+		return lang
+	}
+	return ""
+}
@@ -3,7 +3,11 @@ package errors
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
 	"github.com/o-ga09/web-ya-hime/pkg/logger"
 )
 
@@ -16,13 +20,20 @@ type ErrType string
 type ErrCode string
 
 var (
-	ErrCodeUnAuthorized    ErrCode = "unauthorized"     // 401
-	ErrCodeUnAuthorization ErrCode = "unauthorization"  // 403
-	ErrCodeInValidArgument ErrCode = "invalid argument" // 400
-	ErrCodeBussiness       ErrCode = "business error"   // 400
-	ErrCodeConflict        ErrCode = "conflict"         // 409
-	ErrCodeNotFound        ErrCode = "not found"        // 404
-	ErrCodeCritical        ErrCode = "critical error"   // 500
+	ErrCodeUnAuthorized     ErrCode = "unauthorized"           // 401
+	ErrCodeUnAuthorization  ErrCode = "forbidden"              // 403
+	ErrCodeInValidArgument  ErrCode = "invalid_argument"       // 400
+	ErrCodeBussiness        ErrCode = "business_error"         // 400
+	ErrCodeConflict         ErrCode = "conflict"               // 409
+	ErrCodeNotFound         ErrCode = "not_found"              // 404
+	ErrCodeMethodNotAllowed ErrCode = "method_not_allowed"     // 405
+	ErrCodeRequestTooLarge  ErrCode = "request_too_large"      // 413
+	ErrCodeUnsupportedMedia ErrCode = "unsupported_media_type" // 415
+	ErrCodeTooManyRequests  ErrCode = "too_many_requests"      // 429
+	ErrCodeClientClosed     ErrCode = "client_closed_request"  // 499
+	ErrCodeCritical         ErrCode = "critical_error"         // 500
+	ErrCodeTimeout          ErrCode = "timeout"                // 503
+	ErrCodeGatewayTimeout   ErrCode = "gateway_timeout"        // 504
 )
 
 var (
@@ -61,6 +72,7 @@ var (
 	ErrOptimisticLockConflict = errors.New("optimistic lock conflict")
 	ErrForeignKeyConstraint   = errors.New("foreign key constraint error")
 	ErrUniqueConstraint       = errors.New("unique constraint error")
+	ErrRetryable              = errors.New("retryable database error")
 
 	// 画像エラー
 	ErrInvalidImageType  = errors.New("ファイルの種類が不正です。")
@@ -69,7 +81,13 @@ var (
 	ErrNotFoundImage     = errors.New("画像が見つかりません。")
 
 	// リクエストエラー
-	ErrRequestBodyNil = errors.New("リクエストボディが空です。")
+	ErrRequestBodyNil   = errors.New("リクエストボディが空です。")
+	ErrMethodNotAllowed = errors.New("許可されていないメソッドです。")
+	ErrTimeout          = errors.New("リクエストの処理がタイムアウトしました。")
+	// ErrRequestEntityTooLarge はリクエストボディがサイズの上限を超えた場合のエラーです
+	ErrRequestEntityTooLarge = errors.New("リクエストボディが大きすぎます。")
+	// ErrUnsupportedMediaType は受け付けない Content-Type のボディのエラーです
+	ErrUnsupportedMediaType = errors.New("対応していないContent-Typeです。")
 
 	// その他エラー
 	ErrSystem           = errors.New("システムエラーが発生しました。")
@@ -78,57 +96,159 @@ var (
 	ErrInvalidArgument  = errors.New("バリデーションエラーが発生しました。")
 	ErrInvalidOperation = errors.New("無効な操作です。")
 	ErrNotFound         = errors.New("指定されたデータが見つかりません。")
+	ErrTooManyRequests  = errors.New("リクエストが多すぎます。")
 )
 
+// domainErrors はドメインエラーとエラーコード、メッセージカタログのキーの対応です
+// ラップされたエラーが複数に該当する場合は先に定義したものを優先します
+// ここにないエラーは ErrCodeCritical として扱います
+var domainErrors = []struct {
+	err  error
+	code ErrCode
+	id   i18n.MessageID
+}{
+	{ErrRecordNotFound, ErrCodeNotFound, i18n.MsgErrRecordNotFound},
+	{ErrNotFound, ErrCodeNotFound, i18n.MsgErrNotFound},
+	{ErrNotFoundImage, ErrCodeNotFound, i18n.MsgErrNotFoundImage},
+	{ErrUniqueConstraint, ErrCodeConflict, i18n.MsgErrUniqueConstraint},
+	{ErrConflict, ErrCodeConflict, i18n.MsgErrConflict},
+	{ErrOptimisticLockConflict, ErrCodeConflict, i18n.MsgErrOptimisticLockConflict},
+	{ErrFollowed, ErrCodeConflict, i18n.MsgErrFollowed},
+	{ErrAuthorized, ErrCodeUnAuthorized, i18n.MsgErrAuthorized},
+	{ErrUnauthorized, ErrCodeUnAuthorization, i18n.MsgErrUnauthorized},
+	{ErrMethodNotAllowed, ErrCodeMethodNotAllowed, i18n.MsgMethodNotAllowed},
+	{ErrTooManyRequests, ErrCodeTooManyRequests, i18n.MsgErrTooManyRequests},
+	{ErrTimeout, ErrCodeTimeout, i18n.MsgTimeout},
+	{ErrRequestEntityTooLarge, ErrCodeRequestTooLarge, i18n.MsgErrRequestEntityTooLarge},
+	{ErrUnsupportedMediaType, ErrCodeUnsupportedMedia, i18n.MsgErrUnsupportedMediaType},
+	// データベースや外部のサービスの呼び出しが期限までに終わらなかった場合
+	{context.DeadlineExceeded, ErrCodeGatewayTimeout, i18n.MsgErrDeadlineExceeded},
+	// クライアントがレスポンスを待たずに切断し、リクエストの context がキャンセルされた場合
+	{context.Canceled, ErrCodeClientClosed, i18n.MsgErrCanceled},
+	{ErrFollowSelf, ErrCodeBussiness, i18n.MsgErrFollowSelf},
+	{ErrInvalidOperation, ErrCodeBussiness, i18n.MsgErrInvalidOperation},
+	{ErrForeignKeyConstraint, ErrCodeInValidArgument, i18n.MsgErrForeignKeyConstraint},
+	{ErrInvalidFirebaseID, ErrCodeInValidArgument, i18n.MsgErrInvalidFirebaseID},
+	{ErrInvalidUserID, ErrCodeInValidArgument, i18n.MsgErrInvalidUserID},
+	{ErrInvalidName, ErrCodeInValidArgument, i18n.MsgErrInvalidName},
+	{ErrInvalidDisplayName, ErrCodeInValidArgument, i18n.MsgErrInvalidDisplayName},
+	{ErrInvalidGroupID, ErrCodeInValidArgument, i18n.MsgErrInvalidGroupID},
+	{ErrInvalidRelationID, ErrCodeInValidArgument, i18n.MsgErrInvalidRelationID},
+	{ErrInvalidTwitterID, ErrCodeInValidArgument, i18n.MsgErrInvalidTwitterID},
+	{ErrInvalidGender, ErrCodeInValidArgument, i18n.MsgErrInvalidGender},
+	{ErrInvalidDateTime, ErrCodeInValidArgument, i18n.MsgErrInvalidDateTime},
+	{ErrInvalidProfileURL, ErrCodeInValidArgument, i18n.MsgErrInvalidProfileURL},
+	{ErrInvalidUserType, ErrCodeInValidArgument, i18n.MsgErrInvalidUserType},
+	{ErrRequestNotNil, ErrCodeInValidArgument, i18n.MsgErrRequestNotNil},
+	{ErrRequestBodyNil, ErrCodeInValidArgument, i18n.MsgErrRequestBodyNil},
+	{ErrInvalidArgument, ErrCodeInValidArgument, i18n.MsgErrInvalidArgument},
+	{ErrInvalidImageType, ErrCodeInValidArgument, i18n.MsgErrInvalidImageType},
+	{ErrFailedDecodeImage, ErrCodeInValidArgument, i18n.MsgErrFailedDecodeImage},
+	{ErrFailedImageName, ErrCodeCritical, i18n.MsgErrFailedImageName},
+	{ErrRetryable, ErrCodeCritical, i18n.MsgErrRetryable},
+	{ErrSystem, ErrCodeCritical, i18n.MsgErrSystem},
+}
+
+// wrappedError はエラーコード、利用者向けのメッセージ、発生箇所のコールスタックを付与したエラーです
+type wrappedError struct {
+	err     error
+	code    ErrCode
+	message string
+	stack   string
+}
+
+func (e *wrappedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.err
+}
+
+// LocalizedMessage はエラーに対応するドメインエラーのメッセージをcontextの言語で返します
+// ドメインエラーに該当しない場合は内部の詳細を隠してシステムエラーのメッセージを返します
+func LocalizedMessage(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return i18n.T(ctx, d.id)
+		}
+	}
+	return i18n.T(ctx, i18n.MsgErrSystem)
+}
+
 // ginのcontextに認証エラーをセットして、ログ出力する
 func MakeAuthorizationError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Warn(ctx, errMessage, callStack, stack)
+	logWarn(ctx, Wrap(ctx, fmt.Errorf("%w: %s", ErrAuthorized, msg)))
 }
 
 // ginのcontextに認可エラーをセットして、ログ出力する
 func MakeAuthorizedError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Warn(ctx, errMessage, callStack, stack)
+	logWarn(ctx, Wrap(ctx, fmt.Errorf("%w: %s", ErrUnauthorized, msg)))
 }
 
 // ginのcontextにシステムエラーをセットして、ログ出力する
 func MakeSystemError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Error(ctx, errMessage, callStack, stack)
+	wrapped := Wrap(ctx, fmt.Errorf("%w: %s", ErrSystem, msg))
+	logger.Error(ctx, wrapped.Error(), "code", GetCode(wrapped), callStack, GetCallstack(wrapped))
 }
 
 func MakeBusinessError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Warn(ctx, errMessage, callStack, stack)
+	logWarn(ctx, Wrap(ctx, fmt.Errorf("%w: %s", ErrInvalidOperation, msg)))
 }
 
 func MakeConflictError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Warn(ctx, errMessage, callStack, stack)
+	logWarn(ctx, Wrap(ctx, fmt.Errorf("%w: %s", ErrConflict, msg)))
 }
 
 func MakeNotFoundError(ctx context.Context, msg string) {
-	var wrapped error
-	stack := getCallstack(wrapped)
-	errMessage := GetMessage(wrapped)
-	logger.Warn(ctx, errMessage, callStack, stack)
+	logWarn(ctx, Wrap(ctx, fmt.Errorf("%w: %s", ErrNotFound, msg)))
+}
+
+func logWarn(ctx context.Context, err error) {
+	logger.Warn(ctx, err.Error(), "code", GetCode(err), callStack, GetCallstack(err))
 }
 
 // エラーをラップして、返す
-// もしエラーがラップされていない場合は、システムエラーでラップして返す
+// エラーコードとメッセージはドメインエラーから決め、呼び出し元のコールスタックを記録する
+// もしドメインエラーに該当しない場合は、システムエラーでラップして返す
+// すでにラップされている場合はそのまま返す
 func Wrap(ctx context.Context, err error) error {
-	return err
+	if err == nil || IsWrapped(err) {
+		return err
+	}
+	return wrap(ctx, err, "")
+}
+
+// WrapWithMessage はWrapと同様にエラーをラップし、利用者向けのメッセージを指定した文言に置き換えます
+// すでにラップされている場合はエラーコードとコールスタックを引き継ぎます
+func WrapWithMessage(ctx context.Context, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	var w *wrappedError
+	if errors.As(err, &w) {
+		return &wrappedError{err: err, code: w.code, message: msg, stack: w.stack}
+	}
+	return wrap(ctx, err, msg)
+}
+
+func wrap(ctx context.Context, err error, msg string) error {
+	code := codeOf(err)
+	if code == ErrCodeCritical && !errors.Is(err, ErrSystem) {
+		err = fmt.Errorf("%w: %w", ErrSystem, err)
+	}
+	if msg == "" {
+		msg = LocalizedMessage(ctx, err)
+	}
+	return &wrappedError{
+		err:     err,
+		code:    code,
+		message: msg,
+		stack:   captureCallstack(4),
+	}
 }
 
 func New(ctx context.Context, err string) error {
@@ -136,7 +256,8 @@ func New(ctx context.Context, err string) error {
 }
 
 func IsWrapped(err error) bool {
-	return errors.Is(err, ErrAuthorized)
+	var w *wrappedError
+	return errors.As(err, &w)
 }
 
 func Is(err error, target error) bool {
@@ -149,28 +270,66 @@ func Is(err error, target error) bool {
 	return false
 }
 
+// GetMessage は利用者向けのメッセージを返します
+// ラップされていないエラーの場合はエラーの文字列をそのまま返します
 func GetMessage(err error) string {
 	if err == nil {
 		return ""
 	}
-	if IsWrapped(err) {
-		return errors.Unwrap(err).Error()
+	var w *wrappedError
+	if errors.As(err, &w) {
+		return w.message
 	}
 	return err.Error()
 }
 
+// GetCode はエラーコードを返します
+// ラップされていないエラーの場合はドメインエラーから判定します
 func GetCode(err error) ErrCode {
 	if err == nil {
 		return ""
 	}
-
-	return ""
+	var w *wrappedError
+	if errors.As(err, &w) {
+		return w.code
+	}
+	return codeOf(err)
 }
 
-func getCallstack(err error) string {
+// GetCallstack はWrapしたときに記録したコールスタックを返します
+func GetCallstack(err error) string {
 	if err == nil {
 		return ""
 	}
-
+	var w *wrappedError
+	if errors.As(err, &w) {
+		return w.stack
+	}
 	return ""
 }
+
+func codeOf(err error) ErrCode {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return d.code
+		}
+	}
+	return ErrCodeCritical
+}
+
+// captureCallstack は呼び出し元のコールスタックを "関数名 ファイル:行" の改行区切りで返します
+func captureCallstack(skip int) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s %s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
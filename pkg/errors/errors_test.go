@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/o-ga09/web-ya-hime/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode ErrCode
+		wantMsg  string
+		wantIs   error
+	}{
+		{
+			name:     "成功ケース: レコードが存在しない",
+			err:      fmt.Errorf("user not found: %w", ErrRecordNotFound),
+			wantCode: ErrCodeNotFound,
+			wantMsg:  "データが見つかりません。",
+			wantIs:   ErrRecordNotFound,
+		},
+		{
+			name:     "成功ケース: 一意制約違反",
+			err:      fmt.Errorf("failed to save user: %w", ErrUniqueConstraint),
+			wantCode: ErrCodeConflict,
+			wantMsg:  "すでに登録されているデータです。",
+			wantIs:   ErrUniqueConstraint,
+		},
+		{
+			name:     "成功ケース: 認証エラー",
+			err:      ErrAuthorized,
+			wantCode: ErrCodeUnAuthorized,
+			wantMsg:  "認証に失敗しました。",
+			wantIs:   ErrAuthorized,
+		},
+		{
+			name:     "成功ケース: 認可エラー",
+			err:      ErrUnauthorized,
+			wantCode: ErrCodeUnAuthorization,
+			wantMsg:  "認可に失敗しました。",
+			wantIs:   ErrUnauthorized,
+		},
+		{
+			name:     "成功ケース: バリデーションエラー",
+			err:      ErrInvalidArgument,
+			wantCode: ErrCodeInValidArgument,
+			wantMsg:  "バリデーションエラーが発生しました。",
+			wantIs:   ErrInvalidArgument,
+		},
+		{
+			name:     "成功ケース: 呼び出し先の期限切れ",
+			err:      fmt.Errorf("failed to get user: %w", context.DeadlineExceeded),
+			wantCode: ErrCodeGatewayTimeout,
+			wantMsg:  "依存するサービスの応答がタイムアウトしました。",
+			wantIs:   context.DeadlineExceeded,
+		},
+		{
+			name:     "成功ケース: クライアントの切断",
+			err:      fmt.Errorf("failed to get user: %w", context.Canceled),
+			wantCode: ErrCodeClientClosed,
+			wantMsg:  "クライアントがリクエストを取り消しました。",
+			wantIs:   context.Canceled,
+		},
+		{
+			name:     "成功ケース: ドメインエラー以外はシステムエラーでラップする",
+			err:      errors.New("connection refused"),
+			wantCode: ErrCodeCritical,
+			wantMsg:  "システムエラーが発生しました。",
+			wantIs:   ErrSystem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := Wrap(ctx, tt.err)
+
+			assert.True(t, IsWrapped(wrapped))
+			assert.ErrorIs(t, wrapped, tt.err)
+			assert.ErrorIs(t, wrapped, tt.wantIs)
+			assert.Equal(t, tt.wantCode, GetCode(wrapped))
+			assert.Equal(t, tt.wantMsg, GetMessage(wrapped))
+			assert.Contains(t, wrapped.Error(), tt.err.Error())
+			// 呼び出し元がコールスタックの先頭になる
+			assert.True(t, strings.HasPrefix(GetCallstack(wrapped), "github.com/o-ga09/web-ya-hime/pkg/errors.TestWrap"))
+		})
+	}
+}
+
+func TestWrap_Nil(t *testing.T) {
+	assert.NoError(t, Wrap(context.Background(), nil))
+	assert.NoError(t, WrapWithMessage(context.Background(), nil, "message"))
+}
+
+func TestWrap_AlreadyWrapped(t *testing.T) {
+	ctx := context.Background()
+	wrapped := Wrap(ctx, ErrRecordNotFound)
+
+	assert.Same(t, wrapped, Wrap(ctx, wrapped))
+
+	// ラップ済みのエラーをさらに包んでもコードとコールスタックを引き継ぐ
+	outer := fmt.Errorf("outer: %w", wrapped)
+	assert.Equal(t, ErrCodeNotFound, GetCode(outer))
+	assert.Equal(t, GetCallstack(wrapped), GetCallstack(outer))
+}
+
+func TestWrapWithMessage(t *testing.T) {
+	ctx := i18n.WithLang(context.Background(), i18n.EN)
+
+	wrapped := WrapWithMessage(ctx, ErrUniqueConstraint, "Email already exists")
+	assert.Equal(t, ErrCodeConflict, GetCode(wrapped))
+	assert.Equal(t, "Email already exists", GetMessage(wrapped))
+
+	// ラップ済みのエラーはメッセージのみ置き換える
+	rewrapped := WrapWithMessage(ctx, Wrap(ctx, ErrRecordNotFound), "User not found")
+	assert.Equal(t, ErrCodeNotFound, GetCode(rewrapped))
+	assert.Equal(t, "User not found", GetMessage(rewrapped))
+	assert.ErrorIs(t, rewrapped, ErrRecordNotFound)
+}
+
+func TestGetCode_Unwrapped(t *testing.T) {
+	assert.Equal(t, ErrCode(""), GetCode(nil))
+	assert.Equal(t, ErrCodeNotFound, GetCode(fmt.Errorf("x: %w", ErrRecordNotFound)))
+	assert.Equal(t, ErrCodeInValidArgument, GetCode(ErrForeignKeyConstraint))
+	assert.Equal(t, ErrCodeCritical, GetCode(errors.New("unknown")))
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	ja := i18n.WithLang(context.Background(), i18n.JA)
+	en := i18n.WithLang(context.Background(), i18n.EN)
+
+	assert.Equal(t, "データが見つかりません。", LocalizedMessage(ja, ErrRecordNotFound))
+	assert.Equal(t, "Record not found", LocalizedMessage(en, fmt.Errorf("x: %w", ErrRecordNotFound)))
+	assert.Equal(t, "A system error occurred", LocalizedMessage(en, errors.New("db is down")))
+	assert.Equal(t, "", LocalizedMessage(en, nil))
+}
@@ -0,0 +1,182 @@
+// Package health は /livez と /readyz で返す依存先のチェックを管理します
+// データベースやマイグレーションのようなチェックを Registry に登録し、結果をチェックごとの状態と所要時間のレポートで返します
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status はチェックの結果です
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+)
+
+// ShutdownCheckName は終了処理中に /readyz を失敗にするチェックの名前です
+const ShutdownCheckName = "shutdown"
+
+// ErrShuttingDown は終了処理中のためリクエストを受け付けないことを表します
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// CheckFunc は依存先が利用できるかを確認します。利用できない場合はエラーを返します
+// ctx には Registry のタイムアウトが設定されています
+type CheckFunc func(ctx context.Context) error
+
+// Result はチェックごとの結果です
+type Result struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report はすべてのチェックの結果です。1つでも失敗した場合は Status が fail になります
+type Report struct {
+	Status Status    `json:"status"`
+	Checks []*Result `json:"checks"`
+}
+
+// OK はすべてのチェックが成功したかを返します
+func (r *Report) OK() bool {
+	return r.Status == StatusPass
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+
+	// mu は同時に届いたプローブで同じチェックを重ねて実行しないようにします
+	mu   sync.Mutex
+	last *Result
+}
+
+// Registry は /readyz で確認する依存先のチェックを保持します
+// 各チェックは timeout を上限に実行し、結果を ttl の間再利用します
+type Registry struct {
+	timeout      time.Duration
+	ttl          time.Duration
+	mu           sync.RWMutex
+	checks       []*check
+	shuttingDown atomic.Bool
+	now          func() time.Time
+}
+
+func NewRegistry(timeout, ttl time.Duration) *Registry {
+	return &Registry{timeout: timeout, ttl: ttl, now: time.Now}
+}
+
+// Register はチェックを追加します。レポートには追加した順に並びます
+// 同じ名前で登録した場合は置き換えます
+func (r *Registry) Register(name string, fn CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := &check{name: name, fn: fn}
+	for i, existing := range r.checks {
+		if existing.name == name {
+			r.checks[i] = c
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+}
+
+// Shutdown は終了処理を始めたことを記録します
+// 以降の Check は依存先を確認せずに失敗を返すため、ロードバランサーが振り分け先から外します
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Check はすべてのチェックを並行して実行し、結果をまとめて返します
+// リクエストの取り消しで結果が失敗にならないように、チェックには ctx の取り消しを伝えません
+func (r *Registry) Check(ctx context.Context) *Report {
+	if r.shuttingDown.Load() {
+		return &Report{
+			Status: StatusFail,
+			Checks: []*Result{{Name: ShutdownCheckName, Status: StatusFail, Error: ErrShuttingDown.Error(), CheckedAt: r.now()}},
+		}
+	}
+
+	r.mu.RLock()
+	checks := append([]*check{}, r.checks...)
+	r.mu.RUnlock()
+
+	report := &Report{Status: StatusPass, Checks: make([]*Result, len(checks))}
+	ctx = context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusPass {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run は ttl 以内の結果があれば再利用し、なければチェックを実行します
+func (r *Registry) run(ctx context.Context, c *check) *Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && r.now().Sub(c.last.CheckedAt) < r.ttl {
+		return c.last
+	}
+
+	start := r.now()
+	err := r.call(ctx, c.fn)
+	result := &Result{
+		Name:      c.name,
+		Status:    StatusPass,
+		LatencyMs: float64(r.now().Sub(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	c.last = result
+	return result
+}
+
+// call は timeout を上限にチェックを実行します
+// ctx に従わないチェックでも timeout で失敗にするため、別の goroutine で実行します
+func (r *Registry) call(ctx context.Context, fn CheckFunc) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out after %s: %w", r.timeout, ctx.Err())
+	}
+}
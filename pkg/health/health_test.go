@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     map[string]CheckFunc
+		wantStatus Status
+		wantErrs   map[string]string
+	}{
+		{
+			name:       "成功ケース: チェックがない",
+			wantStatus: StatusPass,
+		},
+		{
+			name: "成功ケース: すべてのチェックが成功する",
+			checks: map[string]CheckFunc{
+				"database": func(context.Context) error { return nil },
+			},
+			wantStatus: StatusPass,
+		},
+		{
+			name: "失敗ケース: 1つでも失敗すると fail",
+			checks: map[string]CheckFunc{
+				"database":   func(context.Context) error { return nil },
+				"migrations": func(context.Context) error { return errors.New("1 pending migration") },
+			},
+			wantStatus: StatusFail,
+			wantErrs:   map[string]string{"migrations": "1 pending migration"},
+		},
+		{
+			name: "失敗ケース: タイムアウトを過ぎたチェック",
+			checks: map[string]CheckFunc{
+				"slow": func(context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				},
+			},
+			wantStatus: StatusFail,
+			wantErrs:   map[string]string{"slow": "check timed out after 10ms: context deadline exceeded"},
+		},
+		{
+			name: "失敗ケース: パニックしたチェック",
+			checks: map[string]CheckFunc{
+				"panic": func(context.Context) error { panic("boom") },
+			},
+			wantStatus: StatusFail,
+			wantErrs:   map[string]string{"panic": "check panicked: boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(10*time.Millisecond, 0)
+			for name, fn := range tt.checks {
+				r.Register(name, fn)
+			}
+
+			report := r.Check(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Len(t, report.Checks, len(tt.checks))
+			for _, result := range report.Checks {
+				assert.Equal(t, tt.wantErrs[result.Name], result.Error, result.Name)
+				if tt.wantErrs[result.Name] == "" {
+					assert.Equal(t, StatusPass, result.Status)
+				} else {
+					assert.Equal(t, StatusFail, result.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Check_Cache(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	r := NewRegistry(time.Second, 5*time.Second)
+	r.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	r.Register("database", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	r.Check(context.Background())
+	r.Check(context.Background())
+	assert.Equal(t, int32(1), calls.Load(), "ttl の間は結果を再利用する")
+
+	now = now.Add(5 * time.Second)
+	r.Check(context.Background())
+	assert.Equal(t, int32(2), calls.Load(), "ttl を過ぎると再度チェックする")
+}
+
+func TestRegistry_Check_CanceledRequest(t *testing.T) {
+	r := NewRegistry(time.Second, 0)
+	r.Register("database", func(ctx context.Context) error { return ctx.Err() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// プローブの切断でチェックの結果を失敗にしない
+	assert.True(t, r.Check(ctx).OK())
+}
+
+func TestRegistry_Register_Replace(t *testing.T) {
+	r := NewRegistry(time.Second, 0)
+	r.Register("database", func(context.Context) error { return errors.New("down") })
+	r.Register("outbox", func(context.Context) error { return nil })
+	r.Register("database", func(context.Context) error { return nil })
+
+	report := r.Check(context.Background())
+	assert.True(t, report.OK())
+	if assert.Len(t, report.Checks, 2) {
+		assert.Equal(t, "database", report.Checks[0].Name)
+		assert.Equal(t, "outbox", report.Checks[1].Name)
+	}
+}
+
+func TestRegistry_Shutdown(t *testing.T) {
+	r := NewRegistry(time.Second, 0)
+	var calls atomic.Int32
+	r.Register("database", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	assert.True(t, r.Check(context.Background()).OK())
+
+	r.Shutdown()
+	report := r.Check(context.Background())
+	assert.False(t, report.OK())
+	if assert.Len(t, report.Checks, 1) {
+		assert.Equal(t, ShutdownCheckName, report.Checks[0].Name)
+		assert.Equal(t, ErrShuttingDown.Error(), report.Checks[0].Error)
+	}
+	// 終了処理中は依存先を確認しない
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,53 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrNonPublicAddress はサーバーの内部から外部に送信するリクエストの宛先が内部のアドレスであることを表します
+var ErrNonPublicAddress = errors.New("destination is not a public address")
+
+// sharedAddressSpace はキャリアグレードNAT (RFC 6598) のアドレスです。クラウドのメタデータサーバーにも使われます
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicAddr はアドレスがインターネットから到達できるアドレスかを判定します
+// ループバック、プライベート、リンクローカル (169.254.169.254 のメタデータサーバーを含む)、未指定のアドレスは false を返します
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsUnspecified() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// IsPublicHost は URL のホストが内部のアドレスを指していないかを判定します
+// IPアドレスは IsPublicAddr で判定し、localhost は false を返します
+// それ以外のホスト名は名前解決の結果が変わるため、接続時に PublicDialControl で確認します
+func IsPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return IsPublicAddr(addr)
+	}
+	return true
+}
+
+// PublicDialControl は net.Dialer.Control に指定し、名前解決した後の接続先が内部のアドレスの場合は接続を拒否します
+// 登録時の検証の後に名前解決の結果を変える DNS リバインディングも防ぎます
+func PublicDialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, address)
+	}
+	return nil
+}
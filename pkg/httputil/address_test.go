@@ -0,0 +1,46 @@
+package httputil
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{name: "成功ケース: ホスト名は接続時に確認する", host: "example.com", want: true},
+		{name: "成功ケース: グローバルなIPv4アドレス", host: "93.184.216.34", want: true},
+		{name: "成功ケース: グローバルなIPv6アドレス", host: "[2606:2800:220:1:248:1893:25c8:1946]", want: true},
+		{name: "失敗ケース: localhost", host: "localhost", want: false},
+		{name: "失敗ケース: localhost のサブドメイン", host: "api.localhost.", want: false},
+		{name: "失敗ケース: ループバック", host: "127.0.0.1", want: false},
+		{name: "失敗ケース: IPv6のループバック", host: "[::1]", want: false},
+		{name: "失敗ケース: プライベート (10/8)", host: "10.0.0.1", want: false},
+		{name: "失敗ケース: プライベート (192.168/16)", host: "192.168.1.1", want: false},
+		{name: "失敗ケース: メタデータサーバー (リンクローカル)", host: "169.254.169.254", want: false},
+		{name: "失敗ケース: 共有アドレス空間", host: "100.100.100.200", want: false},
+		{name: "失敗ケース: 未指定のアドレス", host: "0.0.0.0", want: false},
+		{name: "失敗ケース: IPv4射影アドレスのループバック", host: "[::ffff:127.0.0.1]", want: false},
+		{name: "失敗ケース: IPv6のユニークローカル", host: "[fd00::1]", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPublicHost(tt.host))
+		})
+	}
+}
+
+func TestPublicDialControl(t *testing.T) {
+	assert.NoError(t, PublicDialControl("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, PublicDialControl("tcp4", "169.254.169.254:80", nil), ErrNonPublicAddress)
+	assert.ErrorIs(t, PublicDialControl("tcp6", "[::1]:8080", nil), ErrNonPublicAddress)
+	assert.True(t, errors.Is(PublicDialControl("tcp", "invalid", nil), ErrNonPublicAddress))
+	assert.False(t, IsPublicAddr(netip.Addr{}))
+}
@@ -0,0 +1,303 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeCSV    = "text/csv"
+)
+
+// Lister は NDJSON と CSV で1件ずつ返せる一覧のレスポンスです
+type Lister interface {
+	// ListItems は一覧の要素のスライスを返します
+	ListItems() any
+}
+
+// TotalCounter は NDJSON と CSV で全件数を X-Total-Count ヘッダーで返す一覧のレスポンスです
+// JSON ではボディの total で返すため、NDJSON と CSV でだけ使います
+type TotalCounter interface {
+	TotalCount() int
+}
+
+// TotalCountHeader は NDJSON と CSV の一覧で全件数を返すヘッダーです
+const TotalCountHeader = "X-Total-Count"
+
+// mediaTypeAliases は Accept で受け付ける別名です
+var mediaTypeAliases = map[string]string{
+	"application/ndjson":    ContentTypeNDJSON,
+	"application/jsonl":     ContentTypeNDJSON,
+	"application/x-jsonl":   ContentTypeNDJSON,
+	"application/jsonlines": ContentTypeNDJSON,
+}
+
+// Negotiate は Accept ヘッダーから data を返す Content-Type を決めます
+// NDJSON と CSV は data が Lister の場合だけ選びます
+// Accept がない場合や、どの形式も受け付けない場合は JSON を返します
+func Negotiate(r *http.Request, data any) string {
+	offers := []string{ContentTypeJSON}
+	if _, ok := data.(Lister); ok {
+		offers = append(offers, ContentTypeNDJSON, ContentTypeCSV)
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return ContentTypeJSON
+	}
+
+	best, bestQ := ContentTypeJSON, 0.0
+	for _, offer := range offers {
+		// 同じ品質の場合は offers の順 (JSON) を優先する
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// acceptQuality は Accept ヘッダーで offer に一致する最も具体的な範囲の品質 (q) を返します
+func acceptQuality(accept, offer string) float64 {
+	offerType, _, _ := strings.Cut(offer, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+		if alias, ok := mediaTypeAliases[mediaRange]; ok {
+			mediaRange = alias
+		}
+
+		s := -1
+		switch {
+		case mediaRange == offer:
+			s = 2
+		case mediaRange == offerType+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, parseQuality(params)
+	}
+	return q
+}
+
+// parseQuality はメディアタイプのパラメーターから q の値を返します。指定がない場合は1です
+func parseQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(key, "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(value, 64)
+		if err != nil || q < 0 {
+			return 0
+		}
+		return min(q, 1)
+	}
+	return 1
+}
+
+// NegotiatedResponse は Accept ヘッダーで選んだ形式で data を返します
+// 一覧 (Lister) は NDJSON と CSV でも返せます。NDJSON と CSV は1件ずつ書き込みます
+func NegotiatedResponse(w *http.ResponseWriter, r *http.Request, status int, data any) {
+	contentType := Negotiate(r, data)
+	(*w).Header().Add("Vary", "Accept")
+	if contentType == ContentTypeJSON {
+		write(w, status, ContentTypeJSON, data)
+		return
+	}
+
+	setListHeaders(*w, contentType, data)
+	(*w).WriteHeader(status)
+	_ = encodeList(*w, contentType, data.(Lister))
+}
+
+// setListHeaders は NDJSON と CSV の一覧の Content-Type と X-Total-Count ヘッダーを設定します
+func setListHeaders(w http.ResponseWriter, contentType string, data any) {
+	if contentType == ContentTypeCSV {
+		w.Header().Set("Content-Type", ContentTypeCSV+"; charset=utf-8; header=present")
+	} else {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if c, ok := data.(TotalCounter); ok {
+		w.Header().Set(TotalCountHeader, strconv.Itoa(c.TotalCount()))
+	}
+}
+
+// encode は contentType の形式で data を返すボディを生成します
+func encode(contentType string, data any) ([]byte, error) {
+	if contentType == ContentTypeJSON {
+		return json.Marshal(data)
+	}
+	var buf bytes.Buffer
+	if err := encodeList(&buf, contentType, data.(Lister)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeList は一覧の要素を NDJSON (1行に1件のJSON) または CSV で書き込みます
+func encodeList(w io.Writer, contentType string, list Lister) error {
+	items := reflect.ValueOf(list.ListItems())
+	if items.Kind() != reflect.Slice {
+		return fmt.Errorf("ListItems must return a slice, got %s", items.Kind())
+	}
+
+	if contentType == ContentTypeNDJSON {
+		enc := json.NewEncoder(w)
+		for i := range items.Len() {
+			if err := enc.Encode(items.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	columns := csvColumns(items.Type().Elem(), "", nil, map[reflect.Type]bool{})
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for i := range items.Len() {
+		item := items.Index(i)
+		for j, c := range columns {
+			record[j] = csvValue(item, c.index)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvColumn は CSV の列です。name は JSON のキーで、入れ子の構造体は "user.id" のようにドットでつなぎます
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// csvColumns は t のフィールドから JSON と同じキーで CSV の列を作ります
+// 入れ子の構造体は列を展開し、スライスやマップは JSON の文字列の1列にします
+func csvColumns(t reflect.Type, prefix string, index []int, visiting map[reflect.Type]bool) []csvColumn {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isScalar(t) {
+		return []csvColumn{{name: strings.TrimSuffix(prefix, "."), index: index}}
+	}
+	if visiting[t] {
+		// 再帰する型は展開しない
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var columns []csvColumn
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if f.Anonymous && name == "" {
+			// 埋め込みの構造体は JSON と同じく親のキーに展開する
+			columns = append(columns, csvColumns(f.Type, prefix, fieldIndex, visiting)...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		columns = append(columns, csvColumns(f.Type, prefix+name+".", fieldIndex, visiting)...)
+	}
+	return columns
+}
+
+// isScalar は構造体でも1列にする型 (time.Time など文字列に変換できる型) を判定します
+func isScalar(t reflect.Type) bool {
+	return t == timeType || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// csvValue は v の index のフィールドを CSV のセルの文字列にします
+// 途中の nil のポインターは空文字列にします
+func csvValue(v reflect.Value, index []int) string {
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if !v.CanInterface() {
+		return ""
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339)
+	case v.Kind() == reflect.String:
+		return escapeCSVFormula(v.String())
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10)
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case v.Type() == rawMessageType:
+		return escapeCSVFormula(string(v.Bytes()))
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return escapeCSVFormula(string(text))
+		}
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return escapeCSVFormula(string(b))
+}
+
+// escapeCSVFormula は表計算ソフトで数式として実行されないように、数式の記号で始まる文字列の先頭に ' を付けます
+func escapeCSVFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,165 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type testItem struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Count     int             `json:"count"`
+	User      *testUser       `json:"user,omitempty"`
+	Tags      []string        `json:"tags"`
+	Payload   json.RawMessage `json:"payload"`
+	Secret    string          `json:"-"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type testList struct {
+	Items []*testItem `json:"items"`
+	Total int         `json:"total"`
+}
+
+func (l testList) ListItems() any  { return l.Items }
+func (l testList) TotalCount() int { return l.Total }
+
+func newTestList() testList {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	return testList{
+		Items: []*testItem{
+			{ID: "1", Title: "Go, \"入門\"", Count: 3, User: &testUser{ID: "u1", Name: "taro"}, Tags: []string{"go"}, Payload: json.RawMessage(`{"a":1}`), Secret: "x", CreatedAt: createdAt},
+			{ID: "2", Title: "=HYPERLINK()", Tags: nil, CreatedAt: createdAt},
+		},
+		Total: 10,
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		data   any
+		want   string
+	}{
+		{name: "成功ケース: Accept がない場合はJSON", accept: "", data: testList{}, want: ContentTypeJSON},
+		{name: "成功ケース: NDJSON を指定", accept: "application/x-ndjson", data: testList{}, want: ContentTypeNDJSON},
+		{name: "成功ケース: NDJSON の別名", accept: "application/ndjson", data: testList{}, want: ContentTypeNDJSON},
+		{name: "成功ケース: CSV を指定", accept: "text/csv", data: testList{}, want: ContentTypeCSV},
+		{name: "成功ケース: 品質の高い形式を選ぶ", accept: "application/json;q=0.5, text/csv", data: testList{}, want: ContentTypeCSV},
+		{name: "成功ケース: ワイルドカードは JSON を優先する", accept: "*/*", data: testList{}, want: ContentTypeJSON},
+		{name: "成功ケース: 具体的な範囲の品質を使う", accept: "text/*;q=0.9, text/csv;q=0, application/x-ndjson;q=0.5", data: testList{}, want: ContentTypeNDJSON},
+		{name: "成功ケース: 一覧でない場合はJSON", accept: "text/csv", data: map[string]string{}, want: ContentTypeJSON},
+		{name: "成功ケース: 受け付ける形式がない場合はJSON", accept: "text/html", data: testList{}, want: ContentTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.want, Negotiate(r, tt.data))
+		})
+	}
+}
+
+func TestNegotiatedResponse(t *testing.T) {
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		wantTotal       string
+		wantBody        string
+	}{
+		{
+			name:            "成功ケース: JSON",
+			wantContentType: ContentTypeJSON,
+			wantBody:        `{"items":[{"id":"1","title":"Go, \"入門\"","count":3,"user":{"id":"u1","name":"taro"},"tags":["go"],"payload":{"a":1},"created_at":"2026-10-19T09:00:00Z"},{"id":"2","title":"=HYPERLINK()","count":0,"tags":null,"payload":null,"created_at":"2026-10-19T09:00:00Z"}],"total":10}`,
+		},
+		{
+			name:            "成功ケース: NDJSON は1行に1件",
+			accept:          "application/x-ndjson",
+			wantContentType: ContentTypeNDJSON,
+			wantTotal:       "10",
+			wantBody: `{"id":"1","title":"Go, \"入門\"","count":3,"user":{"id":"u1","name":"taro"},"tags":["go"],"payload":{"a":1},"created_at":"2026-10-19T09:00:00Z"}
+{"id":"2","title":"=HYPERLINK()","count":0,"tags":null,"payload":null,"created_at":"2026-10-19T09:00:00Z"}
+`,
+		},
+		{
+			name:            "成功ケース: CSV は入れ子の構造体を展開し、数式をエスケープする",
+			accept:          "text/csv",
+			wantContentType: "text/csv; charset=utf-8; header=present",
+			wantTotal:       "10",
+			wantBody: `id,title,count,user.id,user.name,tags,payload,created_at
+1,"Go, ""入門""",3,u1,taro,"[""go""]","{""a"":1}",2026-10-19T09:00:00Z
+2,'=HYPERLINK(),0,,,null,,2026-10-19T09:00:00Z
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			var w http.ResponseWriter = rec
+			NegotiatedResponse(&w, r, http.StatusOK, newTestList())
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantTotal, rec.Header().Get(TotalCountHeader))
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestNegotiatedResponse_EmptyCSV(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	r.Header.Set("Accept", "text/csv")
+	rec := httptest.NewRecorder()
+	var w http.ResponseWriter = rec
+	NegotiatedResponse(&w, r, http.StatusOK, testList{})
+
+	// 要素がなくてもヘッダー行を返す
+	assert.Equal(t, "id,title,count,user.id,user.name,tags,payload,created_at\n", rec.Body.String())
+}
+
+func TestCachedResponse_Negotiate(t *testing.T) {
+	request := func(accept, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		r.Header.Set("Accept", accept)
+		r.Header.Set("If-None-Match", ifNoneMatch)
+		rec := httptest.NewRecorder()
+		var w http.ResponseWriter = rec
+		CachedResponse(&w, r, 0, newTestList())
+		return rec
+	}
+
+	jsonRes := request("application/json", "")
+	csvRes := request("text/csv", "")
+	assert.Equal(t, ContentTypeJSON, jsonRes.Header().Get("Content-Type"))
+	assert.Equal(t, "text/csv; charset=utf-8; header=present", csvRes.Header().Get("Content-Type"))
+	// 形式ごとに ETag が異なる
+	assert.NotEqual(t, jsonRes.Header().Get("ETag"), csvRes.Header().Get("ETag"))
+
+	notModified := request("text/csv", csvRes.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+
+	modified := request("application/json", csvRes.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, modified.Code)
+}
@@ -1,10 +1,21 @@
 package httputil
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeProblem = "application/problem+json"
+)
+
+// Response は message をJSONで返します。message がない場合はボディを返しません
+// 一覧を Accept ヘッダーで選んだ形式で返す場合は NegotiatedResponse を使います
 func Response(w *http.ResponseWriter, status int, message ...interface{}) {
 	if len(message) == 0 {
 		(*w).WriteHeader(status)
@@ -18,15 +29,84 @@ func Response(w *http.ResponseWriter, status int, message ...interface{}) {
 		data = message
 	}
 
+	write(w, status, ContentTypeJSON, data)
+}
+
+// ProblemResponse はRFC 7807のapplication/problem+jsonでレスポンスを返します
+func ProblemResponse(w *http.ResponseWriter, status int, problem interface{}) {
+	write(w, status, ContentTypeProblem, problem)
+}
+
+// CachedResponse はレスポンスのボディから計算した ETag と Cache-Control を付けて200を返します
+// ボディの形式は Accept ヘッダーで選び (NegotiatedResponse)、ETag は形式ごとに異なります
+// If-None-Match が ETag と一致する場合はボディを返さずに304を返します
+func CachedResponse(w *http.ResponseWriter, r *http.Request, maxAge time.Duration, data interface{}) {
+	contentType := Negotiate(r, data)
+	(*w).Header().Add("Vary", "Accept")
+	body, err := encode(contentType, data)
+	if err != nil {
+		write(w, http.StatusOK, ContentTypeJSON, data)
+		return
+	}
+
+	etag := ETag(body)
+	(*w).Header().Set("ETag", etag)
+	(*w).Header().Set("Cache-Control", CacheControl(maxAge))
+
+	if MatchETag(r.Header.Get("If-None-Match"), etag) {
+		(*w).WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if contentType == ContentTypeJSON {
+		(*w).Header().Set("Content-Type", ContentTypeJSON)
+	} else {
+		setListHeaders(*w, contentType, data)
+	}
+	(*w).WriteHeader(http.StatusOK)
+	(*w).Write(body)
+}
+
+// ETag はボディのハッシュから強いETagを生成します
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%x"`, sum[:16])
+}
+
+// MatchETag は If-None-Match ヘッダーのいずれかのETagが etag と一致するかを返します
+// If-None-Match では弱い比較を行うため W/ の接頭辞は無視します
+func MatchETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheControl は Cache-Control ヘッダーの値を返します
+// maxAge が0の場合はクライアントにキャッシュを保存させつつ、毎回 ETag で再検証させます
+func CacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+}
+
+func write(w *http.ResponseWriter, status int, contentType string, data interface{}) {
 	json, err := json.Marshal(data)
 	if err != nil {
-		(*w).Header().Set("Content-Type", "application/json")
+		(*w).Header().Set("Content-Type", ContentTypeJSON)
 		(*w).WriteHeader(http.StatusInternalServerError)
 		(*w).Write([]byte(`{"message": "error marshalling json"}`))
 		return
 	}
 
-	(*w).Header().Set("Content-Type", "application/json")
+	(*w).Header().Set("Content-Type", contentType)
 	(*w).WriteHeader(status)
 	(*w).Write(json)
 }
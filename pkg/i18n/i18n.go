@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	Ctx "github.com/o-ga09/web-ya-hime/pkg/context"
+)
+
+// Lang はメッセージの言語です
+type Lang string
+
+const (
+	JA Lang = "ja"
+	EN Lang = "en"
+
+	// DefaultLang はAccept-Languageが指定されていない、または対応していない場合の言語です
+	DefaultLang = JA
+)
+
+// MessageID はメッセージカタログのキーです
+type MessageID string
+
+// catalogs は言語ごとのメッセージカタログです
+// メッセージは fmt の書式で、引数は %[1]s のように位置を指定して参照します
+var catalogs = map[Lang]map[MessageID]string{
+	JA: ja,
+	EN: en,
+}
+
+// Supported は対応している言語かどうかを返します
+func Supported(lang Lang) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Message は指定した言語のメッセージを返します
+// 指定した言語にメッセージがない場合はデフォルト言語、それもない場合はIDをそのまま返します
+func Message(lang Lang, id MessageID, args ...any) string {
+	format, ok := catalogs[lang][id]
+	if !ok {
+		format, ok = catalogs[DefaultLang][id]
+	}
+	if !ok {
+		return string(id)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// T はcontextに設定された言語のメッセージを返します
+func T(ctx context.Context, id MessageID, args ...any) string {
+	return Message(FromContext(ctx), id, args...)
+}
+
+// FromContext はcontextに設定された言語を返します
+// 設定されていない場合はデフォルト言語を返します
+func FromContext(ctx context.Context) Lang {
+	lang := Lang(Ctx.GetLang(ctx))
+	if Supported(lang) {
+		return lang
+	}
+	return DefaultLang
+}
+
+// WithLang はcontextに言語を設定します
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return Ctx.SetLang(ctx, string(lang))
+}
+
+// ParseAcceptLanguage はAccept-Languageヘッダーから対応している言語のうち最も優先度の高いものを返します
+// "ja-JP" のような地域付きの指定は主言語タグで判定し、対応する言語がない場合はデフォルト言語を返します
+func ParseAcceptLanguage(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(tag, "-")
+		lang := Lang(strings.ToLower(primary))
+		if primary == "*" {
+			lang = DefaultLang
+		}
+		if Supported(lang) {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLang
+	}
+	// 同じ優先度の場合はヘッダーでの出現順を優先する
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
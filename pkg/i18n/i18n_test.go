@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Lang
+	}{
+		{name: "成功ケース: 未指定の場合はデフォルト言語", header: "", want: DefaultLang},
+		{name: "成功ケース: 英語", header: "en", want: EN},
+		{name: "成功ケース: 地域付きの指定", header: "en-US,en;q=0.9", want: EN},
+		{name: "成功ケース: 優先度の高い言語を選ぶ", header: "en;q=0.5, ja-JP;q=0.8", want: JA},
+		{name: "成功ケース: 同じ優先度の場合は先に指定した言語", header: "en, ja", want: EN},
+		{name: "成功ケース: 対応していない言語は読み飛ばす", header: "fr-FR, de;q=0.9, en;q=0.1", want: EN},
+		{name: "成功ケース: q=0の言語は選ばない", header: "en;q=0, ja;q=0.1", want: JA},
+		{name: "成功ケース: ワイルドカード", header: "*", want: DefaultLang},
+		{name: "失敗ケース: 対応する言語がない場合はデフォルト言語", header: "fr, de", want: DefaultLang},
+		{name: "失敗ケース: 不正なq値は読み飛ばす", header: "en;q=abc", want: DefaultLang},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   MessageID
+		args []any
+		want string
+	}{
+		{
+			name: "成功ケース: 日本語",
+			ctx:  WithLang(context.Background(), JA),
+			id:   MsgIDRequiredForUpdate,
+			args: []any{Message(JA, ResUser)},
+			want: "更新にはユーザーのIDが必要です。",
+		},
+		{
+			name: "成功ケース: 英語",
+			ctx:  WithLang(context.Background(), EN),
+			id:   MsgIDRequiredForUpdate,
+			args: []any{Message(EN, ResUser)},
+			want: "The user ID is required for update",
+		},
+		{
+			name: "成功ケース: 言語が未設定の場合はデフォルト言語",
+			ctx:  context.Background(),
+			id:   MsgMethodNotAllowed,
+			want: "許可されていないメソッドです。",
+		},
+		{
+			name: "成功ケース: 対応していない言語はデフォルト言語",
+			ctx:  WithLang(context.Background(), Lang("fr")),
+			id:   MsgMethodNotAllowed,
+			want: "許可されていないメソッドです。",
+		},
+		{
+			name: "失敗ケース: カタログにないIDはそのまま返す",
+			ctx:  context.Background(),
+			id:   MessageID("unknown.message"),
+			want: "unknown.message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, T(tt.ctx, tt.id, tt.args...))
+		})
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	// すべての言語が同じキーを持つことを確認する
+	for lang, catalog := range catalogs {
+		for id := range catalogs[DefaultLang] {
+			_, ok := catalog[id]
+			assert.True(t, ok, "%s: %s is missing", lang, id)
+		}
+		assert.Len(t, catalog, len(catalogs[DefaultLang]), "%s", lang)
+	}
+}
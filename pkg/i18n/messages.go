@@ -0,0 +1,84 @@
+package i18n
+
+// リソース名
+const (
+	ResUser        MessageID = "resource.user"
+	ResSummary     MessageID = "resource.summary"
+	ResCategory    MessageID = "resource.category"
+	ResSubcategory MessageID = "resource.subcategory"
+	ResWebhook     MessageID = "resource.webhook"
+)
+
+// ハンドラー・ミドルウェアのメッセージ
+// リソース名を受け取るメッセージは、引数に T(ctx, ResUser) のように翻訳済みのリソース名を渡します
+const (
+	MsgMethodNotAllowed            MessageID = "handler.method_not_allowed"
+	MsgInvalidRequest              MessageID = "handler.invalid_request"
+	MsgDeleted                     MessageID = "handler.deleted"
+	MsgIDRequiredForUpdate         MessageID = "handler.id_required_for_update"
+	MsgEmailAlreadyExists          MessageID = "handler.email_already_exists"
+	MsgInvalidSubcategory          MessageID = "handler.invalid_subcategory"
+	MsgSubcategoryCategoryMismatch MessageID = "handler.subcategory_category_mismatch"
+	MsgSummaryAlreadyPublished     MessageID = "handler.summary_already_published"
+	MsgInternalServerError         MessageID = "server.internal_server_error"
+	MsgTimeout                     MessageID = "server.timeout"
+	MsgDBConnectionNotFound        MessageID = "server.db_connection_not_found"
+	MsgDBConnectionError           MessageID = "server.db_connection_error"
+	MsgDBConnectionHealthy         MessageID = "server.db_connection_healthy"
+)
+
+// バリデーションルールのメッセージ
+// 引数は %[1]s がフィールド名、%[2]s がルールのパラメータです
+// 大小比較のルールは値の種類ごとに .number / .string / .items のメッセージを持ちます
+const (
+	MsgValidationRequired     MessageID = "validation.required"
+	MsgValidationEmail        MessageID = "validation.email"
+	MsgValidationUUID         MessageID = "validation.uuid"
+	MsgValidationURL          MessageID = "validation.url"
+	MsgValidationPublicURL    MessageID = "validation.public_url"
+	MsgValidationOneOf        MessageID = "validation.oneof"
+	MsgValidationRegexp       MessageID = "validation.regexp"
+	MsgValidationCustom       MessageID = "validation.custom"
+	MsgValidationUnknownRule  MessageID = "validation.unknown_rule"
+	MsgValidationInvalidParam MessageID = "validation.invalid_param"
+)
+
+// ドメインエラーのメッセージ
+const (
+	MsgErrInvalidFirebaseID      MessageID = "error.invalid_firebase_id"
+	MsgErrInvalidUserID          MessageID = "error.invalid_user_id"
+	MsgErrInvalidName            MessageID = "error.invalid_name"
+	MsgErrInvalidDisplayName     MessageID = "error.invalid_display_name"
+	MsgErrInvalidGroupID         MessageID = "error.invalid_group_id"
+	MsgErrInvalidRelationID      MessageID = "error.invalid_relation_id"
+	MsgErrInvalidTwitterID       MessageID = "error.invalid_twitter_id"
+	MsgErrInvalidGender          MessageID = "error.invalid_gender"
+	MsgErrInvalidDateTime        MessageID = "error.invalid_date_time"
+	MsgErrInvalidProfileURL      MessageID = "error.invalid_profile_url"
+	MsgErrInvalidUserType        MessageID = "error.invalid_user_type"
+	MsgErrFollowed               MessageID = "error.followed"
+	MsgErrFollowSelf             MessageID = "error.follow_self"
+	MsgErrRequestNotNil          MessageID = "error.request_not_nil"
+	MsgErrRecordNotFound         MessageID = "error.record_not_found"
+	MsgErrConflict               MessageID = "error.conflict"
+	MsgErrOptimisticLockConflict MessageID = "error.optimistic_lock_conflict"
+	MsgErrForeignKeyConstraint   MessageID = "error.foreign_key_constraint"
+	MsgErrUniqueConstraint       MessageID = "error.unique_constraint"
+	MsgErrRetryable              MessageID = "error.retryable"
+	MsgErrInvalidImageType       MessageID = "error.invalid_image_type"
+	MsgErrFailedImageName        MessageID = "error.failed_image_name"
+	MsgErrFailedDecodeImage      MessageID = "error.failed_decode_image"
+	MsgErrNotFoundImage          MessageID = "error.not_found_image"
+	MsgErrRequestBodyNil         MessageID = "error.request_body_nil"
+	MsgErrSystem                 MessageID = "error.system"
+	MsgErrAuthorized             MessageID = "error.authorized"
+	MsgErrUnauthorized           MessageID = "error.unauthorized"
+	MsgErrInvalidArgument        MessageID = "error.invalid_argument"
+	MsgErrInvalidOperation       MessageID = "error.invalid_operation"
+	MsgErrNotFound               MessageID = "error.not_found"
+	MsgErrTooManyRequests        MessageID = "error.too_many_requests"
+	MsgErrDeadlineExceeded       MessageID = "error.deadline_exceeded"
+	MsgErrCanceled               MessageID = "error.canceled"
+	MsgErrRequestEntityTooLarge  MessageID = "error.request_entity_too_large"
+	MsgErrUnsupportedMediaType   MessageID = "error.unsupported_media_type"
+)
@@ -0,0 +1,91 @@
+package i18n
+
+var en = map[MessageID]string{
+	ResUser:        "user",
+	ResSummary:     "summary",
+	ResCategory:    "category",
+	ResSubcategory: "subcategory",
+	ResWebhook:     "webhook",
+
+	MsgMethodNotAllowed:            "Method not allowed",
+	MsgInvalidRequest:              "Invalid request: %[1]v",
+	MsgDeleted:                     "The %[1]s was deleted successfully",
+	MsgIDRequiredForUpdate:         "The %[1]s ID is required for update",
+	MsgEmailAlreadyExists:          "Email already exists",
+	MsgInvalidSubcategory:          "Invalid subcategory",
+	MsgSubcategoryCategoryMismatch: "Subcategory does not belong to the specified category",
+	MsgSummaryAlreadyPublished:     "Summary is already published",
+	MsgInternalServerError:         "Internal Server Error",
+	MsgTimeout:                     "The request timed out",
+	MsgDBConnectionNotFound:        "Database connection not found",
+	MsgDBConnectionError:           "Database connection error",
+	MsgDBConnectionHealthy:         "Database connection is healthy",
+
+	MsgValidationRequired:     "%[1]s is required",
+	MsgValidationEmail:        "%[1]s must be a valid email address",
+	MsgValidationUUID:         "%[1]s must be a valid UUID",
+	MsgValidationURL:          "%[1]s must be a valid URL",
+	MsgValidationPublicURL:    "%[1]s must be a URL reachable from the internet",
+	MsgValidationOneOf:        "%[1]s must be one of [%[2]s]",
+	MsgValidationRegexp:       "%[1]s must match the pattern %[2]s",
+	MsgValidationCustom:       "%[1]s failed on the %[2]s rule",
+	MsgValidationUnknownRule:  "unknown validation rule %[2]s for %[1]s",
+	MsgValidationInvalidParam: "invalid validation parameter %[2]s for %[1]s",
+	"validation.max.number":   "%[1]s must be less than or equal to %[2]s",
+	"validation.max.string":   "%[1]s must be less than or equal to %[2]s characters",
+	"validation.max.items":    "%[1]s must be less than or equal to %[2]s items",
+	"validation.min.number":   "%[1]s must be at least %[2]s",
+	"validation.min.string":   "%[1]s must be at least %[2]s characters",
+	"validation.min.items":    "%[1]s must be at least %[2]s items",
+	"validation.len.string":   "%[1]s must be exactly %[2]s characters",
+	"validation.len.items":    "%[1]s must be exactly %[2]s items",
+	"validation.gt.number":    "%[1]s must be greater than %[2]s",
+	"validation.gt.string":    "%[1]s must be greater than %[2]s characters",
+	"validation.gt.items":     "%[1]s must be greater than %[2]s items",
+	"validation.gte.number":   "%[1]s must be greater than or equal to %[2]s",
+	"validation.gte.string":   "%[1]s must be greater than or equal to %[2]s characters",
+	"validation.gte.items":    "%[1]s must be greater than or equal to %[2]s items",
+	"validation.lt.number":    "%[1]s must be less than %[2]s",
+	"validation.lt.string":    "%[1]s must be less than %[2]s characters",
+	"validation.lt.items":     "%[1]s must be less than %[2]s items",
+	"validation.lte.number":   "%[1]s must be less than or equal to %[2]s",
+	"validation.lte.string":   "%[1]s must be less than or equal to %[2]s characters",
+	"validation.lte.items":    "%[1]s must be less than or equal to %[2]s items",
+
+	MsgErrInvalidFirebaseID:      "Invalid Firebase ID",
+	MsgErrInvalidUserID:          "Invalid user ID",
+	MsgErrInvalidName:            "Invalid user name",
+	MsgErrInvalidDisplayName:     "Invalid display name",
+	MsgErrInvalidGroupID:         "Invalid group ID",
+	MsgErrInvalidRelationID:      "Invalid relation ID",
+	MsgErrInvalidTwitterID:       "Invalid Twitter ID",
+	MsgErrInvalidGender:          "Gender is out of range",
+	MsgErrInvalidDateTime:        "Invalid date format",
+	MsgErrInvalidProfileURL:      "Invalid profile URL",
+	MsgErrInvalidUserType:        "Invalid user type",
+	MsgErrFollowed:               "Already followed",
+	MsgErrFollowSelf:             "You cannot follow yourself",
+	MsgErrRequestNotNil:          "Invalid request",
+	MsgErrRecordNotFound:         "Record not found",
+	MsgErrConflict:               "The data conflicts with existing data",
+	MsgErrOptimisticLockConflict: "The data has been updated by another operation",
+	MsgErrForeignKeyConstraint:   "The related data does not exist",
+	MsgErrUniqueConstraint:       "The data already exists",
+	MsgErrRetryable:              "The database is busy. Please try again later",
+	MsgErrInvalidImageType:       "Invalid file type",
+	MsgErrFailedImageName:        "Failed to generate the file name",
+	MsgErrFailedDecodeImage:      "Failed to decode the image",
+	MsgErrNotFoundImage:          "Image not found",
+	MsgErrRequestBodyNil:         "Request body is empty",
+	MsgErrSystem:                 "A system error occurred",
+	MsgErrAuthorized:             "Authentication failed",
+	MsgErrUnauthorized:           "Authorization failed",
+	MsgErrInvalidArgument:        "Validation failed",
+	MsgErrInvalidOperation:       "Invalid operation",
+	MsgErrNotFound:               "The specified data was not found",
+	MsgErrTooManyRequests:        "Too many requests. Please try again later",
+	MsgErrDeadlineExceeded:       "A dependent service did not respond in time",
+	MsgErrCanceled:               "The request was canceled by the client",
+	MsgErrRequestEntityTooLarge:  "The request body is too large",
+	MsgErrUnsupportedMediaType:   "Unsupported Content-Type. Send the request body as application/json",
+}
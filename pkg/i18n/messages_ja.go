@@ -0,0 +1,91 @@
+package i18n
+
+var ja = map[MessageID]string{
+	ResUser:        "ユーザー",
+	ResSummary:     "概要欄",
+	ResCategory:    "カテゴリ",
+	ResSubcategory: "サブカテゴリ",
+	ResWebhook:     "Webhook",
+
+	MsgMethodNotAllowed:            "許可されていないメソッドです。",
+	MsgInvalidRequest:              "リクエストの形式が正しくありません: %[1]v",
+	MsgDeleted:                     "%[1]sを削除しました。",
+	MsgIDRequiredForUpdate:         "更新には%[1]sのIDが必要です。",
+	MsgEmailAlreadyExists:          "このメールアドレスはすでに登録されています。",
+	MsgInvalidSubcategory:          "不正なサブカテゴリです。",
+	MsgSubcategoryCategoryMismatch: "サブカテゴリが指定されたカテゴリに属していません。",
+	MsgSummaryAlreadyPublished:     "このサマリーはすでに公開されています。",
+	MsgInternalServerError:         "サーバー内部でエラーが発生しました。",
+	MsgTimeout:                     "リクエストがタイムアウトしました。",
+	MsgDBConnectionNotFound:        "データベース接続が見つかりません。",
+	MsgDBConnectionError:           "データベース接続でエラーが発生しました。",
+	MsgDBConnectionHealthy:         "データベース接続は正常です。",
+
+	MsgValidationRequired:     "%[1]sは必須です。",
+	MsgValidationEmail:        "%[1]sは正しいメールアドレスの形式で入力してください。",
+	MsgValidationUUID:         "%[1]sは正しいUUIDの形式で入力してください。",
+	MsgValidationURL:          "%[1]sは正しいURLの形式で入力してください。",
+	MsgValidationPublicURL:    "%[1]sにはインターネットから到達できるURLを指定してください。",
+	MsgValidationOneOf:        "%[1]sは[%[2]s]のいずれかを指定してください。",
+	MsgValidationRegexp:       "%[1]sはパターン%[2]sに一致する必要があります。",
+	MsgValidationCustom:       "%[1]sが%[2]sルールを満たしていません。",
+	MsgValidationUnknownRule:  "%[1]sに不明なバリデーションルール%[2]sが指定されています。",
+	MsgValidationInvalidParam: "%[1]sのバリデーションルールのパラメータ%[2]sが不正です。",
+	"validation.max.number":   "%[1]sは%[2]s以下で入力してください。",
+	"validation.max.string":   "%[1]sは%[2]s文字以下で入力してください。",
+	"validation.max.items":    "%[1]sは%[2]s件以下で指定してください。",
+	"validation.min.number":   "%[1]sは%[2]s以上で入力してください。",
+	"validation.min.string":   "%[1]sは%[2]s文字以上で入力してください。",
+	"validation.min.items":    "%[1]sは%[2]s件以上で指定してください。",
+	"validation.len.string":   "%[1]sは%[2]s文字で入力してください。",
+	"validation.len.items":    "%[1]sは%[2]s件で指定してください。",
+	"validation.gt.number":    "%[1]sは%[2]sより大きい値で入力してください。",
+	"validation.gt.string":    "%[1]sは%[2]s文字より長く入力してください。",
+	"validation.gt.items":     "%[1]sは%[2]s件より多く指定してください。",
+	"validation.gte.number":   "%[1]sは%[2]s以上で入力してください。",
+	"validation.gte.string":   "%[1]sは%[2]s文字以上で入力してください。",
+	"validation.gte.items":    "%[1]sは%[2]s件以上で指定してください。",
+	"validation.lt.number":    "%[1]sは%[2]sより小さい値で入力してください。",
+	"validation.lt.string":    "%[1]sは%[2]s文字より短く入力してください。",
+	"validation.lt.items":     "%[1]sは%[2]s件より少なく指定してください。",
+	"validation.lte.number":   "%[1]sは%[2]s以下で入力してください。",
+	"validation.lte.string":   "%[1]sは%[2]s文字以下で入力してください。",
+	"validation.lte.items":    "%[1]sは%[2]s件以下で指定してください。",
+
+	MsgErrInvalidFirebaseID:      "不正なFirebaseIDです。",
+	MsgErrInvalidUserID:          "不正なUserIDです。",
+	MsgErrInvalidName:            "不正なユーザー名です。",
+	MsgErrInvalidDisplayName:     "不正な表示名です。",
+	MsgErrInvalidGroupID:         "不正なグループIDです。",
+	MsgErrInvalidRelationID:      "不正なリレーションIDです。",
+	MsgErrInvalidTwitterID:       "不正なTwitterIDです。",
+	MsgErrInvalidGender:          "性別の値の範囲が不正です。",
+	MsgErrInvalidDateTime:        "日付のフォーマットが不正です。",
+	MsgErrInvalidProfileURL:      "不正なプロフィールURLです。",
+	MsgErrInvalidUserType:        "無効なユーザータイプフォーマットです。",
+	MsgErrFollowed:               "すでにフォロー済みです。",
+	MsgErrFollowSelf:             "自分自身をフォローすることはできません。",
+	MsgErrRequestNotNil:          "リクエストが正しくありません。",
+	MsgErrRecordNotFound:         "データが見つかりません。",
+	MsgErrConflict:               "データが競合しています。",
+	MsgErrOptimisticLockConflict: "他の操作によってデータが更新されています。",
+	MsgErrForeignKeyConstraint:   "関連するデータが存在しません。",
+	MsgErrUniqueConstraint:       "すでに登録されているデータです。",
+	MsgErrRetryable:              "データベースが混雑しています。時間をおいて再度お試しください。",
+	MsgErrInvalidImageType:       "ファイルの種類が不正です。",
+	MsgErrFailedImageName:        "ファイル名の生成に失敗しました。",
+	MsgErrFailedDecodeImage:      "画像のデコードに失敗しました。",
+	MsgErrNotFoundImage:          "画像が見つかりません。",
+	MsgErrRequestBodyNil:         "リクエストボディが空です。",
+	MsgErrSystem:                 "システムエラーが発生しました。",
+	MsgErrAuthorized:             "認証に失敗しました。",
+	MsgErrUnauthorized:           "認可に失敗しました。",
+	MsgErrInvalidArgument:        "バリデーションエラーが発生しました。",
+	MsgErrInvalidOperation:       "無効な操作です。",
+	MsgErrNotFound:               "指定されたデータが見つかりません。",
+	MsgErrTooManyRequests:        "リクエストが多すぎます。時間をおいて再度お試しください。",
+	MsgErrDeadlineExceeded:       "依存するサービスの応答がタイムアウトしました。",
+	MsgErrCanceled:               "クライアントがリクエストを取り消しました。",
+	MsgErrRequestEntityTooLarge:  "リクエストボディが大きすぎます。",
+	MsgErrUnsupportedMediaType:   "対応していないContent-Typeです。リクエストボディは application/json で送信してください。",
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"database/sql"
+	"runtime"
+	"time"
+)
+
+// NamedDB はラベルを付けて統計情報を出力するコネクションプールです
+type NamedDB struct {
+	Name string
+	DB   *sql.DB
+}
+
+// NewDBStatsCollector はコネクションプールの統計情報 (sql.DBStats) を db ラベルを付けて出力します
+func NewDBStatsCollector(dbs ...NamedDB) Collector {
+	return CollectorFunc(func(w *Writer) {
+		stats := make([]sql.DBStats, len(dbs))
+		for i, db := range dbs {
+			stats[i] = db.DB.Stats()
+		}
+
+		gauge := func(name, help string, value func(s sql.DBStats) float64) {
+			w.Header(name, help, TypeGauge)
+			for i, db := range dbs {
+				w.Sample(name, value(stats[i]), "db", db.Name)
+			}
+		}
+		counter := func(name, help string, value func(s sql.DBStats) float64) {
+			w.Header(name, help, TypeCounter)
+			for i, db := range dbs {
+				w.Sample(name, value(stats[i]), "db", db.Name)
+			}
+		}
+
+		gauge("db_max_open_connections", "Maximum number of open connections to the database.",
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+		gauge("db_open_connections", "The number of established connections both in use and idle.",
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+		gauge("db_in_use_connections", "The number of connections currently in use.",
+			func(s sql.DBStats) float64 { return float64(s.InUse) })
+		gauge("db_idle_connections", "The number of idle connections.",
+			func(s sql.DBStats) float64 { return float64(s.Idle) })
+		counter("db_wait_count_total", "The total number of connections waited for.",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+		counter("db_wait_duration_seconds_total", "The total time blocked waiting for a new connection.",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+		counter("db_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+		counter("db_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+		counter("db_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.",
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+	})
+}
+
+// NewRuntimeCollector はゴルーチン数・メモリ・GCなどの Go ランタイムの統計情報を出力します
+// 出力のたびに runtime.ReadMemStats を呼び出します
+func NewRuntimeCollector() Collector {
+	start := time.Now()
+
+	return CollectorFunc(func(w *Writer) {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+
+		w.Header("go_info", "Information about the Go environment.", TypeGauge)
+		w.Sample("go_info", 1, "version", runtime.Version())
+		w.Header("go_goroutines", "Number of goroutines that currently exist.", TypeGauge)
+		w.Sample("go_goroutines", float64(runtime.NumGoroutine()))
+		w.Header("go_threads", "Number of OS threads that can execute Go code simultaneously (GOMAXPROCS).", TypeGauge)
+		w.Sample("go_threads", float64(runtime.GOMAXPROCS(0)))
+
+		w.Header("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", TypeGauge)
+		w.Sample("go_memstats_alloc_bytes", float64(m.Alloc))
+		w.Header("go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", TypeCounter)
+		w.Sample("go_memstats_alloc_bytes_total", float64(m.TotalAlloc))
+		w.Header("go_memstats_sys_bytes", "Number of bytes obtained from system.", TypeGauge)
+		w.Sample("go_memstats_sys_bytes", float64(m.Sys))
+		w.Header("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", TypeGauge)
+		w.Sample("go_memstats_heap_inuse_bytes", float64(m.HeapInuse))
+		w.Header("go_memstats_heap_objects", "Number of allocated objects.", TypeGauge)
+		w.Sample("go_memstats_heap_objects", float64(m.HeapObjects))
+
+		w.Header("go_gc_cycles_total", "Number of completed GC cycles.", TypeCounter)
+		w.Sample("go_gc_cycles_total", float64(m.NumGC))
+		w.Header("go_gc_pause_seconds_total", "Total time spent in GC stop-the-world pauses.", TypeCounter)
+		w.Sample("go_gc_pause_seconds_total", time.Duration(m.PauseTotalNs).Seconds())
+
+		w.Header("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", TypeGauge)
+		w.Sample("process_start_time_seconds", float64(start.Unix()))
+	})
+}
@@ -0,0 +1,129 @@
+// Package metrics はメトリクスを集計し、Prometheus のテキスト形式 (version 0.0.4) で出力します
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Type はメトリクスの種類です
+type Type string
+
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// DefBuckets は秒単位の処理時間のヒストグラムのバケットの既定値です
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector は出力のたびにメトリクスを書き込みます
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc は関数を Collector として使います
+type CollectorFunc func(w *Writer)
+
+func (f CollectorFunc) Collect(w *Writer) { f(w) }
+
+// Registry は出力するメトリクスの一覧です
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default はアプリケーション全体で使う Registry です
+var Default = NewRegistry()
+
+// Register は出力するメトリクスを追加します
+func (r *Registry) Register(cs ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, cs...)
+}
+
+// Register は c を Default に追加して返します。パッケージ変数の宣言で使います
+func Register[C Collector](c C) C {
+	Default.Register(c)
+	return c
+}
+
+// Handler はメトリクスを Prometheus のテキスト形式で返すハンドラーです
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(r.Gather())
+	})
+}
+
+// Gather はすべてのメトリクスをテキスト形式で返します
+func (r *Registry) Gather() []byte {
+	r.mu.RLock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.RUnlock()
+
+	w := &Writer{}
+	for _, c := range collectors {
+		c.Collect(w)
+	}
+	return w.buf.Bytes()
+}
+
+// Writer はテキスト形式でメトリクスを書き込みます
+type Writer struct {
+	buf bytes.Buffer
+}
+
+// Header はメトリクスの説明と種類を書き込みます。同じ名前のサンプルの前に1回だけ呼び出します
+func (w *Writer) Header(name, help string, typ Type) {
+	w.buf.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	w.buf.WriteString("# TYPE " + name + " " + string(typ) + "\n")
+}
+
+// Sample はラベルの名前と値を交互に指定して1つの値を書き込みます
+func (w *Writer) Sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 1 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.buf.WriteString(labels[i] + `="` + escapeLabel(labels[i+1]) + `"`)
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestCounterVec(t *testing.T) {
+	c := NewCounterVec("http_requests_total", "Total number of HTTP requests.", "route", "status")
+	c.Inc("GET /summaries/{id}", "200")
+	c.Inc("GET /summaries/{id}", "200")
+	c.Add(3, "GET /summaries", "500")
+	c.Add(-1, "GET /summaries", "500")
+
+	w := &Writer{}
+	c.Collect(w)
+
+	assert.Equal(t, `# HELP http_requests_total Total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{route="GET /summaries",status="500"} 3
+http_requests_total{route="GET /summaries/{id}",status="200"} 2
+`, w.buf.String())
+}
+
+func TestGaugeVec(t *testing.T) {
+	g := NewGaugeVec("http_requests_in_flight", "Number of HTTP requests being served.")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+
+	w := &Writer{}
+	g.Collect(w)
+
+	assert.Equal(t, `# HELP http_requests_in_flight Number of HTTP requests being served.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight 1
+`, w.buf.String())
+}
+
+func TestHistogramVec(t *testing.T) {
+	h := NewHistogramVec("request_duration_seconds", "Request latency.", []float64{1, 0.1}, "route")
+	h.Observe(0.05, "a")
+	h.Observe(0.1, "a")
+	h.Observe(0.5, "a")
+	h.Observe(3, "a")
+
+	w := &Writer{}
+	h.Collect(w)
+
+	// バケットは昇順に並べ、上限と等しい値はそのバケットに含める
+	assert.Equal(t, `# HELP request_duration_seconds Request latency.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{route="a",le="0.1"} 2
+request_duration_seconds_bucket{route="a",le="1"} 3
+request_duration_seconds_bucket{route="a",le="+Inf"} 4
+request_duration_seconds_sum{route="a"} 3.65
+request_duration_seconds_count{route="a"} 4
+`, w.buf.String())
+}
+
+func TestWriter_Escape(t *testing.T) {
+	w := &Writer{}
+	w.Header("m", "line1\nline2 \\", TypeGauge)
+	w.Sample("m", 1, "path", "a\"b\\c\nd")
+
+	assert.Equal(t, "# HELP m line1\\nline2 \\\\\n# TYPE m gauge\nm{path=\"a\\\"b\\\\c\\nd\"} 1\n", w.buf.String())
+}
+
+func TestVec_LabelCount(t *testing.T) {
+	c := NewCounterVec("c", "help", "route")
+	assert.Panics(t, func() { c.Inc() })
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	r := NewRegistry()
+	c := NewCounterVec("requests_total", "Total requests.")
+	c.Inc()
+	r.Register(c, NewDBStatsCollector(NamedDB{Name: "primary", DB: db}), NewRuntimeCollector())
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "requests_total 1\n")
+	assert.Contains(t, body, `db_open_connections{db="primary"} `)
+	assert.Contains(t, body, "# TYPE db_wait_count_total counter\n")
+	assert.Contains(t, body, "go_goroutines ")
+	assert.True(t, strings.HasSuffix(body, "\n"))
+}
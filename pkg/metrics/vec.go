@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// vec はラベルの値ごとの値を保持します
+type vec[T any] struct {
+	name       string
+	help       string
+	labelNames []string
+	mu         sync.Mutex
+	series     map[string]*series[T]
+	newValue   func() T
+}
+
+type series[T any] struct {
+	labelValues []string
+	value       T
+}
+
+func newVec[T any](name, help string, labelNames []string, newValue func() T) vec[T] {
+	return vec[T]{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		series:     map[string]*series[T]{},
+		newValue:   newValue,
+	}
+}
+
+// with はラベルの値の値を返します。v.mu を取得してから呼び出します
+// ラベルの数が定義と異なる場合は呼び出し側の誤りのため panic します
+func (v *vec[T]) with(labelValues []string) *series[T] {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s requires %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series[T]{labelValues: slices.Clone(labelValues), value: v.newValue()}
+		v.series[key] = s
+	}
+	return s
+}
+
+// sorted は値をラベルの値の順に返します。v.mu を取得してから呼び出します
+func (v *vec[T]) sorted() []*series[T] {
+	out := make([]*series[T], 0, len(v.series))
+	for _, s := range v.series {
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b *series[T]) int { return slices.Compare(a.labelValues, b.labelValues) })
+	return out
+}
+
+// labels はラベルの名前と値を交互に並べ、extra を後ろに追加します
+func (v *vec[T]) labels(s *series[T], extra ...string) []string {
+	labels := make([]string, 0, len(v.labelNames)*2+len(extra))
+	for i, name := range v.labelNames {
+		labels = append(labels, name, s.labelValues[i])
+	}
+	return append(labels, extra...)
+}
+
+// CounterVec は増えるだけの値です (リクエスト数など)
+type CounterVec struct {
+	vec[float64]
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(name, help, labelNames, func() float64 { return 0 })}
+}
+
+// Inc は1増やします
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add は delta 増やします。負の値は無視します
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.with(labelValues).value += delta
+}
+
+func (c *CounterVec) Collect(w *Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.Header(c.name, c.help, TypeCounter)
+	for _, s := range c.sorted() {
+		w.Sample(c.name, s.value, c.labels(s)...)
+	}
+}
+
+// GaugeVec は増減する値です (処理中のリクエスト数など)
+type GaugeVec struct {
+	vec[float64]
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, help, labelNames, func() float64 { return 0 })}
+}
+
+// Set は値を設定します
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(labelValues).value = value
+}
+
+// Add は delta 増やします。負の値で減らします
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.with(labelValues).value += delta
+}
+
+func (g *GaugeVec) Inc(labelValues ...string) { g.Add(1, labelValues...) }
+func (g *GaugeVec) Dec(labelValues ...string) { g.Add(-1, labelValues...) }
+
+func (g *GaugeVec) Collect(w *Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	w.Header(g.name, g.help, TypeGauge)
+	for _, s := range g.sorted() {
+		w.Sample(g.name, s.value, g.labels(s)...)
+	}
+}
+
+// HistogramVec は値の分布です (処理時間など)
+// バケットごとの件数と合計、件数を出力します
+type HistogramVec struct {
+	vec[*histogram]
+	buckets []float64
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec は上限が buckets のヒストグラムを生成します。buckets が空の場合は DefBuckets を使います
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &HistogramVec{
+		vec:     newVec(name, help, labelNames, func() *histogram { return &histogram{counts: make([]uint64, len(buckets))} }),
+		buckets: buckets,
+	}
+}
+
+// Observe は値を1件記録します
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist := h.with(labelValues).value
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(hist.counts) {
+		hist.counts[i]++
+	}
+	hist.sum += value
+	hist.count++
+}
+
+func (h *HistogramVec) Collect(w *Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w.Header(h.name, h.help, TypeHistogram)
+	for _, s := range h.sorted() {
+		// バケットは上限以下の件数の累計で出力する
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.value.counts[i]
+			w.Sample(h.name+"_bucket", float64(cumulative), h.labels(s, "le", formatFloat(upper))...)
+		}
+		w.Sample(h.name+"_bucket", float64(s.value.count), h.labels(s, "le", formatFloat(math.Inf(1)))...)
+		w.Sample(h.name+"_sum", s.value.sum, h.labels(s)...)
+		w.Sample(h.name+"_count", float64(s.value.count), h.labels(s)...)
+	}
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval はメモリのストアが満杯になったバケットを削除する間隔です
+const sweepInterval = time.Minute
+
+// MemoryStore はプロセス内のメモリにバケットを保持するストアです
+// 上限はインスタンスごとになります
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]time.Time{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	tat, result := Take(s.buckets[key], now, limit)
+	if result.Allowed {
+		s.buckets[key] = tat
+	}
+	return result, nil
+}
+
+// sweep は満杯まで回復したバケットを削除します。s.mu を取得してから呼び出します
+// 満杯のバケットは存在しない場合と同じ結果になるため、削除しても上限は変わりません
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, tat := range s.buckets {
+		if !tat.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
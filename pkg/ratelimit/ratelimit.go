@@ -0,0 +1,118 @@
+// Package ratelimit はクライアントごとのリクエスト数をトークンバケットで制限します
+// バケットの状態は GCRA (Generic Cell Rate Algorithm) で次にトークンが満杯になる時刻の1つの値として保持するため、
+// データベースのような外部のストアでも1行の比較と更新で扱えます
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit は Period あたりのリクエスト数の上限です
+// Requests 回まで続けてリクエストでき、その後は Period / Requests ごとに1回分回復します
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit は "60/1m" (1分あたり60回) の形式の上限を解析します
+// 空文字列は制限なし (IsZero が true) として扱います
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: want <requests>/<period>", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", s)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// IsZero は制限しない場合に true を返します
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// Policy は RateLimit-Policy ヘッダーの値です (例: 60;w=60)
+func (l Limit) Policy() string {
+	return fmt.Sprintf("%d;w=%d", l.Requests, int64(ceilSeconds(l.Period)))
+}
+
+// interval はトークンが1つ回復するまでの時間です
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result はトークンを消費した結果です
+type Result struct {
+	Allowed bool
+	// Limit は上限のリクエスト数です
+	Limit int
+	// Remaining は続けてリクエストできる残りの回数です
+	Remaining int
+	// Reset はトークンが満杯まで回復するまでの時間です
+	Reset time.Duration
+	// RetryAfter は拒否された場合に次のリクエストができるまでの時間です
+	RetryAfter time.Duration
+}
+
+// Take は満杯になる時刻が tat のバケットからトークンを1つ消費し、更新後の満杯になる時刻と結果を返します
+// tat がゼロ値の場合は満杯のバケットとして扱います。拒否された場合は tat をそのまま返します
+func Take(tat, now time.Time, limit Limit) (time.Time, Result) {
+	interval := limit.interval()
+	if tat.Before(now) {
+		tat = now
+	}
+
+	next := tat.Add(interval)
+	// next - now が Period を超える場合はトークンが残っていない
+	if allowAt := next.Add(-limit.Period); now.Before(allowAt) {
+		return tat, Result{
+			Allowed:    false,
+			Limit:      limit.Requests,
+			Remaining:  0,
+			Reset:      tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}
+	}
+
+	return next, Result{
+		Allowed:   true,
+		Limit:     limit.Requests,
+		Remaining: int((limit.Period - next.Sub(now)) / interval),
+		Reset:     next.Sub(now),
+	}
+}
+
+// Store はキーごとのバケットを保持します
+// 複数のインスタンスで上限を共有する場合はデータベースなどの共有のストアを使います
+type Store interface {
+	// Take は key のバケットからトークンを1つ消費します
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// ceilSeconds は d を秒に切り上げます。ヘッダーには整数の秒で返します
+func ceilSeconds(d time.Duration) time.Duration {
+	return (d + time.Second - 1) / time.Second
+}
+
+// Seconds は d を切り上げた整数の秒の文字列で返します
+func Seconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64(ceilSeconds(d)), 10)
+}
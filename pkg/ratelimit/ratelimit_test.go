@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Limit
+		wantErr bool
+	}{
+		{name: "成功ケース: 期間あたりのリクエスト数", value: "60/1m", want: Limit{Requests: 60, Period: time.Minute}},
+		{name: "成功ケース: 空文字列は制限なし", value: "", want: Limit{}},
+		{name: "失敗ケース: 区切りがない", value: "60", wantErr: true},
+		{name: "失敗ケース: リクエスト数が0", value: "0/1m", wantErr: true},
+		{name: "失敗ケース: 期間が不正", value: "60/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	var (
+		tat    time.Time
+		result Result
+	)
+	// 満杯のバケットから Requests 回まで続けて消費できる
+	for i := range 3 {
+		tat, result = Take(tat, start, limit)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+		assert.Equal(t, time.Duration(i+1)*time.Second, result.Reset)
+	}
+
+	// トークンがなくなると回復するまで拒否する
+	denied, result := Take(tat, start, limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, tat, denied)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Period / Requests ごとに1回分回復する
+	_, result = Take(tat, start.Add(time.Second), limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// 十分に時間が経つと満杯に戻る
+	_, result = Take(tat, start.Add(time.Hour), limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestLimit_Policy(t *testing.T) {
+	assert.Equal(t, "60;w=60", Limit{Requests: 60, Period: time.Minute}.Policy())
+	assert.Equal(t, "10;w=2", Limit{Requests: 10, Period: 1500 * time.Millisecond}.Policy())
+}
+
+func TestMemoryStore(t *testing.T) {
+	current := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return current }
+	limit := Limit{Requests: 2, Period: time.Minute}
+	ctx := context.Background()
+
+	t.Run("成功ケース: キーごとに上限を数える", func(t *testing.T) {
+		for range 2 {
+			result, err := s.Take(ctx, "ip:192.0.2.1", limit)
+			assert.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+		result, err := s.Take(ctx, "ip:192.0.2.1", limit)
+		assert.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 30*time.Second, result.RetryAfter)
+
+		result, err = s.Take(ctx, "ip:192.0.2.2", limit)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("成功ケース: 満杯まで回復したバケットを削除する", func(t *testing.T) {
+		current = current.Add(2 * time.Minute)
+		_, err := s.Take(ctx, "ip:192.0.2.3", limit)
+		assert.NoError(t, err)
+		assert.Len(t, s.buckets, 1)
+	})
+}
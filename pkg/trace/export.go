@@ -0,0 +1,305 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/o-ga09/web-ya-hime/pkg/logger"
+)
+
+// 出力の設定
+var (
+	// maxQueueSize は出力を待つスパンの上限です。超えた分は捨てます
+	maxQueueSize = 2048
+	// shutdownTimeout は停止時に残りのスパンを出力するのに使える時間です
+	shutdownTimeout = 5 * time.Second
+)
+
+// Exporter は終了したスパンを出力します
+type Exporter interface {
+	Export(ctx context.Context, spans []*Span) error
+}
+
+// Provider は終了したスパンを溜めて、定期的に、または batchSize 件溜まった時点でまとめて出力します
+// 出力が追いつかない場合、リクエストの処理を遅らせないようにスパンを捨てます
+type Provider struct {
+	exporter  Exporter
+	interval  time.Duration
+	batchSize int
+	mu        sync.Mutex
+	queue     []*Span
+	dropped   int
+	full      chan struct{}
+}
+
+// NewProvider は interval ごとに最大 batchSize 件ずつスパンを出力する Provider を生成します
+func NewProvider(exporter Exporter, interval time.Duration, batchSize int) *Provider {
+	return &Provider{
+		exporter:  exporter,
+		interval:  interval,
+		batchSize: max(batchSize, 1),
+		full:      make(chan struct{}, 1),
+	}
+}
+
+var provider atomic.Pointer[Provider]
+
+// SetProvider は終了したスパンを渡す Provider を設定します。nil の場合はスパンを出力しません
+func SetProvider(p *Provider) {
+	provider.Store(p)
+}
+
+func getProvider() *Provider {
+	return provider.Load()
+}
+
+// Run は ctx がキャンセルされるまで定期的にスパンを出力し、キャンセル後に残りを出力します
+func (p *Provider) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+			defer cancel()
+			if err := p.Flush(flushCtx); err != nil {
+				logger.Error(ctx, fmt.Sprintf("failed to export spans: %v", err))
+			}
+			return
+		case <-ticker.C:
+		case <-p.full:
+		}
+		if err := p.Flush(ctx); err != nil {
+			logger.Warn(ctx, fmt.Sprintf("failed to export spans: %v", err))
+		}
+	}
+}
+
+// Flush は溜まっているスパンをすべて出力します。出力に失敗したスパンは捨てます
+func (p *Provider) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	spans := p.queue
+	dropped := p.dropped
+	p.queue = nil
+	p.dropped = 0
+	p.mu.Unlock()
+
+	if dropped > 0 {
+		logger.Warn(ctx, fmt.Sprintf("dropped %d spans because the export queue was full", dropped))
+	}
+	for len(spans) > 0 {
+		n := min(len(spans), p.batchSize)
+		if err := p.exporter.Export(ctx, spans[:n]); err != nil {
+			return err
+		}
+		spans = spans[n:]
+	}
+	return nil
+}
+
+func (p *Provider) enqueue(s *Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.queue) >= maxQueueSize {
+		p.dropped++
+		return
+	}
+	p.queue = append(p.queue, s)
+	if len(p.queue) >= p.batchSize {
+		select {
+		case p.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// OTLPExporter はスパンを OTLP/HTTP (JSON) でコレクターに送信します
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	client      *http.Client
+}
+
+// NewOTLPExporter は endpoint (例: http://localhost:4318/v1/traces) に送信する Exporter を生成します
+func NewOTLPExporter(endpoint, serviceName string, timeout time.Duration) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: serviceName,
+		// 送信自体はトレースしない
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []*Span) error {
+	body, err := MarshalOTLP(e.serviceName, spans)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WriterExporter はスパンを OTLP/JSON で1回の出力につき1行ずつ書き込みます。標準出力への出力に使います
+type WriterExporter struct {
+	mu          sync.Mutex
+	w           io.Writer
+	serviceName string
+}
+
+func NewWriterExporter(w io.Writer, serviceName string) *WriterExporter {
+	return &WriterExporter{w: w, serviceName: serviceName}
+}
+
+func (e *WriterExporter) Export(_ context.Context, spans []*Span) error {
+	body, err := MarshalOTLP(e.serviceName, spans)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(body, '\n'))
+	return err
+}
+
+// OTLP/JSON の形式 (https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding)
+// 64ビット整数は文字列で表します
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              SpanKind        `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+	otlpStatus struct {
+		// 0: 未設定、2: エラー
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// MarshalOTLP はスパンを OTLP/JSON の ExportTraceServiceRequest に変換します
+func MarshalOTLP(serviceName string, spans []*Span) ([]byte, error) {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, s.otlp())
+	}
+
+	return json.Marshal(otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: []otlpAttribute{attribute("service.name", serviceName)}},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/o-ga09/web-ya-hime/pkg/trace"},
+				Spans: out,
+			}},
+		}},
+	})
+}
+
+func (s *Span) otlp() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span := otlpSpan{
+		TraceID:           s.sc.TraceID.String(),
+		SpanID:            s.sc.SpanID.String(),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+	}
+	if s.parent.IsValid() {
+		span.ParentSpanID = s.parent.String()
+	}
+	for key, value := range s.attributes {
+		span.Attributes = append(span.Attributes, attribute(key, value))
+	}
+	// 出力を比較しやすいようにキーの順に並べる
+	slices.SortFunc(span.Attributes, func(a, b otlpAttribute) int { return strings.Compare(a.Key, b.Key) })
+	if s.err != "" {
+		span.Status = otlpStatus{Code: 2, Message: s.err}
+	}
+	return span
+}
+
+func attribute(key string, value any) otlpAttribute {
+	var v otlpValue
+	switch value := value.(type) {
+	case string:
+		v.StringValue = &value
+	case int:
+		n := strconv.Itoa(value)
+		v.IntValue = &n
+	case int64:
+		n := strconv.FormatInt(value, 10)
+		v.IntValue = &n
+	case bool:
+		v.BoolValue = &value
+	case float64:
+		v.DoubleValue = &value
+	default:
+		str := fmt.Sprint(value)
+		v.StringValue = &str
+	}
+	return otlpAttribute{Key: key, Value: v}
+}
@@ -0,0 +1,175 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingExporter は出力されたスパンを記録します
+type recordingExporter struct {
+	spans   []*Span
+	batches int
+	err     error
+}
+
+func (e *recordingExporter) Export(_ context.Context, spans []*Span) error {
+	e.batches++
+	e.spans = append(e.spans, spans...)
+	return e.err
+}
+
+// fixedClock は呼び出すたびに1秒進む時刻を返します
+func fixedClock(t *testing.T) {
+	current := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestMarshalOTLP(t *testing.T) {
+	fixedClock(t)
+	remote, _ := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span := Start(ContextWithRemote(context.Background(), remote), "GET /summaries",
+		WithKind(SpanKindServer),
+		WithAttributes("http.request.method", "GET", "http.response.status_code", 500, "retry", false),
+	)
+	span.sc.SpanID = SpanID{0, 0, 0, 0, 0, 0, 0, 1}
+	span.SetError(errors.New("database error"))
+	span.End()
+
+	body, err := MarshalOTLP("web-ya-hime", []*Span{span})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"resourceSpans":[{
+		"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"web-ya-hime"}}]},
+		"scopeSpans":[{
+			"scope":{"name":"github.com/o-ga09/web-ya-hime/pkg/trace"},
+			"spans":[{
+				"traceId":"4bf92f3577b34da6a3ce929d0e0e4736",
+				"spanId":"0000000000000001",
+				"parentSpanId":"00f067aa0ba902b7",
+				"name":"GET /summaries",
+				"kind":2,
+				"startTimeUnixNano":"1792368001000000000",
+				"endTimeUnixNano":"1792368002000000000",
+				"attributes":[
+					{"key":"http.request.method","value":{"stringValue":"GET"}},
+					{"key":"http.response.status_code","value":{"intValue":"500"}},
+					{"key":"retry","value":{"boolValue":false}}
+				],
+				"status":{"code":2,"message":"database error"}
+			}]
+		}]
+	}]}`, string(body))
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("成功ケース: サンプリング対象のスパンを batchSize 件ずつ出力する", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		p := NewProvider(exporter, time.Minute, 2)
+		SetProvider(p)
+		defer SetProvider(nil)
+
+		for range 3 {
+			_, span := Start(context.Background(), "sampled")
+			span.End()
+		}
+		remote, _ := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		_, unsampled := Start(ContextWithRemote(context.Background(), remote), "unsampled")
+		unsampled.End()
+
+		assert.NoError(t, p.Flush(context.Background()))
+		assert.Len(t, exporter.spans, 3)
+		assert.Equal(t, 2, exporter.batches)
+	})
+
+	t.Run("成功ケース: End を複数回呼び出しても1回だけ出力する", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		p := NewProvider(exporter, time.Minute, 10)
+		SetProvider(p)
+		defer SetProvider(nil)
+
+		_, span := Start(context.Background(), "span")
+		span.End()
+		span.End()
+
+		assert.NoError(t, p.Flush(context.Background()))
+		assert.Len(t, exporter.spans, 1)
+	})
+
+	t.Run("成功ケース: 停止時に残りのスパンを出力する", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		p := NewProvider(exporter, time.Hour, 10)
+		SetProvider(p)
+		defer SetProvider(nil)
+
+		_, span := Start(context.Background(), "span")
+		span.End()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		p.Run(ctx)
+
+		assert.Len(t, exporter.spans, 1)
+	})
+
+	t.Run("失敗ケース: 出力に失敗したらエラーを返す", func(t *testing.T) {
+		exporter := &recordingExporter{err: errors.New("export error")}
+		p := NewProvider(exporter, time.Minute, 10)
+		SetProvider(p)
+		defer SetProvider(nil)
+
+		_, span := Start(context.Background(), "span")
+		span.End()
+
+		assert.Error(t, p.Flush(context.Background()))
+	})
+}
+
+func TestOTLPExporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "成功ケース: コレクターにJSONで送信する", status: http.StatusOK},
+		{name: "失敗ケース: 2xx 以外のレスポンス", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				contentType string
+				body        []byte
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			_, span := Start(context.Background(), "span")
+			span.End()
+
+			err := NewOTLPExporter(srv.URL, "web-ya-hime", time.Second).Export(context.Background(), []*Span{span})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "application/json", contentType)
+			assert.Contains(t, string(body), span.SpanContext().SpanID.String())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TraceparentHeader は W3C Trace Context のヘッダーです (00-<trace-id>-<parent-id>-<flags>)
+	TraceparentHeader = "traceparent"
+	// CloudTraceContextHeader は Cloud Run が付与するヘッダーです (TRACE_ID/SPAN_ID;o=OPTIONS)
+	// SPAN_ID は10進数です
+	CloudTraceContextHeader = "X-Cloud-Trace-Context"
+)
+
+// Extract はリクエストのヘッダーから呼び出し元のスパンを取得します
+// traceparent を優先し、なければ X-Cloud-Trace-Context を使います。どちらもないか不正な場合は false を返します
+func Extract(h http.Header) (SpanContext, bool) {
+	if sc, ok := parseTraceparent(h.Get(TraceparentHeader)); ok {
+		return sc, true
+	}
+	return parseCloudTraceContext(h.Get(CloudTraceContextHeader))
+}
+
+// Inject はスパンの識別子を traceparent ヘッダーに設定します。識別子が不正な場合は何もしません
+func Inject(sc SpanContext, h http.Header) {
+	if !sc.IsValid() {
+		return
+	}
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	h.Set(TraceparentHeader, fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags))
+}
+
+func parseTraceparent(v string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	// 将来のバージョンでは後ろにフィールドが増える可能性がある
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if !decodeHex(parts[1], sc.TraceID[:]) || !decodeHex(parts[2], sc.SpanID[:]) || !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	var flags [1]byte
+	if !decodeHex(parts[3], flags[:]) {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	return sc, true
+}
+
+func parseCloudTraceContext(v string) (SpanContext, bool) {
+	traceID, rest, ok := strings.Cut(strings.TrimSpace(v), "/")
+	if !ok {
+		return SpanContext{}, false
+	}
+	spanID, options, _ := strings.Cut(rest, ";")
+
+	var sc SpanContext
+	if !decodeHex(traceID, sc.TraceID[:]) {
+		return SpanContext{}, false
+	}
+	n, err := strconv.ParseUint(spanID, 10, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	binary.BigEndian.PutUint64(sc.SpanID[:], n)
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	// o=0 の場合だけ出力しない
+	sc.Sampled = options != "o=0"
+	return sc, true
+}
+
+// decodeHex は dst と同じ長さの小文字の16進数を変換します
+func decodeHex(s string, dst []byte) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// Transport は外部へのHTTPリクエストごとにスパンを記録し、traceparent ヘッダーで送信先にトレースを引き継ぎます
+type Transport struct {
+	// Base は実際にリクエストを送信します。nil の場合は http.DefaultTransport を使います
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Start(req.Context(), "HTTP "+req.Method, WithKind(SpanKindClient), WithAttributes(
+		"http.request.method", req.Method,
+		"server.address", req.URL.Host,
+		"url.full", req.URL.Redacted(),
+	))
+	defer span.End()
+
+	// RoundTripper はリクエストを変更してはいけないため複製してからヘッダーを設定する
+	req = req.Clone(ctx)
+	Inject(span.SpanContext(), req.Header)
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	span.SetAttributes("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,185 @@
+// Package trace はリクエスト・リポジトリの呼び出し・外部へのHTTPリクエストの処理時間をスパンとして記録します
+// W3C Trace Context (traceparent) と Cloud Run の X-Cloud-Trace-Context で呼び出し元のトレースを引き継ぎ、
+// 終了したスパンは SetProvider で設定した Provider が OTLP/JSON で出力します
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID はトレースのIDです (16バイト)
+type TraceID [16]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid はすべて0ではないかを返します
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// SpanID はスパンのIDです (8バイト)
+type SpanID [8]byte
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid はすべて0ではないかを返します
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext はプロセスをまたいで引き継ぐスパンの識別子です
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Sampled が false のスパンは出力しません
+	Sampled bool
+}
+
+// IsValid はトレースIDとスパンIDが設定されているかを返します
+func (sc SpanContext) IsValid() bool { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+
+// SpanKind はスパンの種類です。値は OTLP の SpanKind と同じです
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Span は1つの処理の開始から終了までの記録です
+type Span struct {
+	mu         sync.Mutex
+	name       string
+	kind       SpanKind
+	sc         SpanContext
+	parent     SpanID
+	start      time.Time
+	end        time.Time
+	attributes map[string]any
+	err        string
+	ended      bool
+}
+
+// SpanContext はスパンの識別子を返します
+func (s *Span) SpanContext() SpanContext { return s.sc }
+
+// SetAttributes は key と値を交互に指定して属性を設定します。値は string・int・int64・bool・float64 を使えます
+func (s *Span) SetAttributes(kv ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			s.attributes[key] = kv[i+1]
+		}
+	}
+}
+
+// SetError はスパンをエラーとして記録します。err が nil の場合は何もしません
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err.Error()
+}
+
+// End はスパンを終了し、サンプリング対象であれば Provider に渡します。2回目以降の呼び出しは無視します
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = now()
+	s.mu.Unlock()
+
+	if s.sc.Sampled {
+		if p := getProvider(); p != nil {
+			p.enqueue(s)
+		}
+	}
+}
+
+// StartOption はスパンの開始時の設定です
+type StartOption func(*Span)
+
+// WithKind はスパンの種類を設定します。省略した場合は SpanKindInternal です
+func WithKind(kind SpanKind) StartOption {
+	return func(s *Span) { s.kind = kind }
+}
+
+// WithAttributes はスパンの属性を設定します
+func WithAttributes(kv ...any) StartOption {
+	return func(s *Span) { s.SetAttributes(kv...) }
+}
+
+// Start はスパンを開始し、そのスパンを設定したcontextを返します
+// ctx にスパンか呼び出し元のスパン (ContextWithRemote) があればその子に、なければ新しいトレースを開始します
+// 呼び出し側は処理の終了時に必ず End を呼び出してください
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	s := &Span{
+		name:       name,
+		kind:       SpanKindInternal,
+		start:      now(),
+		attributes: map[string]any{},
+	}
+
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		s.sc = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+		s.parent = parent.SpanID
+	} else {
+		s.sc = SpanContext{TraceID: newTraceID(), Sampled: true}
+	}
+	s.sc.SpanID = newSpanID()
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// ContextWithRemote は呼び出し元のスパンをcontextに設定します。以降に Start したスパンはその子になります
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// FromContext は実行中のスパンを返します。スパンがない場合は nil を返します
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SpanContextFromContext は実行中のスパン、なければ呼び出し元のスパンの識別子を返します
+// どちらもない場合はゼロ値を返します
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if s := FromContext(ctx); s != nil {
+		return s.sc
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// now はテストで時刻を固定するために差し替えます
+var now = time.Now
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
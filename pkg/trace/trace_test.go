@@ -0,0 +1,169 @@
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStart(t *testing.T) {
+	t.Run("成功ケース: 親がない場合は新しいトレースを開始する", func(t *testing.T) {
+		ctx, span := Start(context.Background(), "root")
+		sc := span.SpanContext()
+
+		assert.True(t, sc.IsValid())
+		assert.True(t, sc.Sampled)
+		assert.False(t, span.parent.IsValid())
+		assert.Equal(t, sc, SpanContextFromContext(ctx))
+	})
+
+	t.Run("成功ケース: 実行中のスパンの子になる", func(t *testing.T) {
+		ctx, parent := Start(context.Background(), "parent")
+		_, child := Start(ctx, "child")
+
+		assert.Equal(t, parent.SpanContext().TraceID, child.SpanContext().TraceID)
+		assert.Equal(t, parent.SpanContext().SpanID, child.parent)
+		assert.NotEqual(t, parent.SpanContext().SpanID, child.SpanContext().SpanID)
+	})
+
+	t.Run("成功ケース: 呼び出し元のスパンの子になり、サンプリングの判定を引き継ぐ", func(t *testing.T) {
+		remote, _ := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		_, span := Start(ContextWithRemote(context.Background(), remote), "server")
+
+		assert.Equal(t, remote.TraceID, span.SpanContext().TraceID)
+		assert.Equal(t, remote.SpanID, span.parent)
+		assert.False(t, span.SpanContext().Sampled)
+	})
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantTraceID string
+		wantSpanID  string
+		wantSampled bool
+		wantOK      bool
+	}{
+		{
+			name:        "成功ケース: traceparent",
+			headers:     map[string]string{TraceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+			wantSampled: true,
+			wantOK:      true,
+		},
+		{
+			name:        "成功ケース: サンプリングしない traceparent",
+			headers:     map[string]string{TraceparentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+			wantOK:      true,
+		},
+		{
+			name:        "成功ケース: X-Cloud-Trace-Context (スパンIDは10進数)",
+			headers:     map[string]string{CloudTraceContextHeader: "105445aa7843bc8bf206b12000100000/1;o=1"},
+			wantTraceID: "105445aa7843bc8bf206b12000100000",
+			wantSpanID:  "0000000000000001",
+			wantSampled: true,
+			wantOK:      true,
+		},
+		{
+			name: "成功ケース: traceparent を X-Cloud-Trace-Context より優先する",
+			headers: map[string]string{
+				TraceparentHeader:       "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				CloudTraceContextHeader: "105445aa7843bc8bf206b12000100000/1;o=1",
+			},
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+			wantSampled: true,
+			wantOK:      true,
+		},
+		{
+			name:    "失敗ケース: トレースIDがすべて0",
+			headers: map[string]string{TraceparentHeader: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		},
+		{
+			name:    "失敗ケース: 大文字の16進数",
+			headers: map[string]string{TraceparentHeader: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		},
+		{
+			name:    "失敗ケース: バージョン ff",
+			headers: map[string]string{TraceparentHeader: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		},
+		{
+			name:    "失敗ケース: X-Cloud-Trace-Context のスパンIDが数値ではない",
+			headers: map[string]string{CloudTraceContextHeader: "105445aa7843bc8bf206b12000100000/abc;o=1"},
+		},
+		{
+			name: "失敗ケース: ヘッダーがない",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+
+			sc, ok := Extract(h)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.wantTraceID, sc.TraceID.String())
+			assert.Equal(t, tt.wantSpanID, sc.SpanID.String())
+			assert.Equal(t, tt.wantSampled, sc.Sampled)
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	sc, _ := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h := http.Header{}
+
+	Inject(sc, h)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", h.Get(TraceparentHeader))
+}
+
+func TestTransport(t *testing.T) {
+	var received http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	exporter := &recordingExporter{}
+	p := NewProvider(exporter, 0, 10)
+	SetProvider(p)
+	defer SetProvider(nil)
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, nil)
+	resp, err := (&http.Client{Transport: &Transport{}}).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.NoError(t, p.Flush(context.Background()))
+	if assert.Len(t, exporter.spans, 1) {
+		span := exporter.spans[0]
+		assert.Equal(t, "HTTP POST", span.name)
+		assert.Equal(t, SpanKindClient, span.kind)
+		assert.Equal(t, parent.SpanContext().SpanID, span.parent)
+		assert.Equal(t, http.StatusInternalServerError, span.attributes["http.response.status_code"])
+		assert.NotEmpty(t, span.err)
+
+		sc, ok := Extract(received)
+		assert.True(t, ok)
+		assert.Equal(t, span.SpanContext(), sc)
+	}
+	// 元のリクエストは変更しない
+	assert.Empty(t, req.Header.Get(TraceparentHeader))
+}
@@ -15,3 +15,11 @@ func GenerateIDV7() (string, error) {
 	}
 	return uuid.String(), nil
 }
+
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx 形式のUUIDかどうかを判定する
+func IsValid(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	return uuid.Validate(s) == nil
+}